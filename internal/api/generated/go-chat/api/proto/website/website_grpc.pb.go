@@ -20,12 +20,35 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RoomService_CreateRoom_FullMethodName    = "/website.RoomService/CreateRoom"
-	RoomService_GetRoom_FullMethodName       = "/website.RoomService/GetRoom"
-	RoomService_GetOwnerRooms_FullMethodName = "/website.RoomService/GetOwnerRooms"
-	RoomService_SearchRooms_FullMethodName   = "/website.RoomService/SearchRooms"
-	RoomService_DeleteRoom_FullMethodName    = "/website.RoomService/DeleteRoom"
-	RoomService_GetAllRooms_FullMethodName   = "/website.RoomService/GetAllRooms"
+	RoomService_CreateRoom_FullMethodName             = "/website.RoomService/CreateRoom"
+	RoomService_GetRoom_FullMethodName                = "/website.RoomService/GetRoom"
+	RoomService_GetOwnerRooms_FullMethodName          = "/website.RoomService/GetOwnerRooms"
+	RoomService_SearchRooms_FullMethodName            = "/website.RoomService/SearchRooms"
+	RoomService_DeleteRoom_FullMethodName             = "/website.RoomService/DeleteRoom"
+	RoomService_GetAllRooms_FullMethodName            = "/website.RoomService/GetAllRooms"
+	RoomService_GetOrCreateDirectRoom_FullMethodName  = "/website.RoomService/GetOrCreateDirectRoom"
+	RoomService_CreateGroupRoom_FullMethodName        = "/website.RoomService/CreateGroupRoom"
+	RoomService_GetMemberRooms_FullMethodName         = "/website.RoomService/GetMemberRooms"
+	RoomService_CreateRoomScript_FullMethodName       = "/website.RoomService/CreateRoomScript"
+	RoomService_UpdateRoomScript_FullMethodName       = "/website.RoomService/UpdateRoomScript"
+	RoomService_DeleteRoomScript_FullMethodName       = "/website.RoomService/DeleteRoomScript"
+	RoomService_GetRoomScripts_FullMethodName         = "/website.RoomService/GetRoomScripts"
+	RoomService_GetEnabledScripts_FullMethodName      = "/website.RoomService/GetEnabledScripts"
+	RoomService_CreateRoomWebhook_FullMethodName      = "/website.RoomService/CreateRoomWebhook"
+	RoomService_UpdateRoomWebhook_FullMethodName      = "/website.RoomService/UpdateRoomWebhook"
+	RoomService_DeleteRoomWebhook_FullMethodName      = "/website.RoomService/DeleteRoomWebhook"
+	RoomService_GetRoomWebhooks_FullMethodName        = "/website.RoomService/GetRoomWebhooks"
+	RoomService_GetWebhookDeliveries_FullMethodName   = "/website.RoomService/GetWebhookDeliveries"
+	RoomService_GetWebhookDeadLetters_FullMethodName  = "/website.RoomService/GetWebhookDeadLetters"
+	RoomService_RedeliverWebhook_FullMethodName       = "/website.RoomService/RedeliverWebhook"
+	RoomService_PublishRoomEvent_FullMethodName       = "/website.RoomService/PublishRoomEvent"
+	RoomService_CreateIncomingWebhook_FullMethodName  = "/website.RoomService/CreateIncomingWebhook"
+	RoomService_GetIncomingWebhooks_FullMethodName    = "/website.RoomService/GetIncomingWebhooks"
+	RoomService_DeleteIncomingWebhook_FullMethodName  = "/website.RoomService/DeleteIncomingWebhook"
+	RoomService_ResolveIncomingWebhook_FullMethodName = "/website.RoomService/ResolveIncomingWebhook"
+	RoomService_SetRetentionPolicy_FullMethodName     = "/website.RoomService/SetRetentionPolicy"
+	RoomService_GetRetentionPolicy_FullMethodName     = "/website.RoomService/GetRetentionPolicy"
+	RoomService_GetRetentionPolicies_FullMethodName   = "/website.RoomService/GetRetentionPolicies"
 )
 
 // RoomServiceClient is the client API for RoomService service.
@@ -38,6 +61,36 @@ type RoomServiceClient interface {
 	SearchRooms(ctx context.Context, in *SearchRoomsRequest, opts ...grpc.CallOption) (*RoomsResponse, error)
 	DeleteRoom(ctx context.Context, in *DeleteRoomRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetAllRooms(ctx context.Context, in *GetAllRoomsRequest, opts ...grpc.CallOption) (*RoomsResponse, error)
+	GetOrCreateDirectRoom(ctx context.Context, in *GetOrCreateDirectRoomRequest, opts ...grpc.CallOption) (*CreateRoomResponse, error)
+	CreateGroupRoom(ctx context.Context, in *CreateGroupRoomRequest, opts ...grpc.CallOption) (*CreateRoomResponse, error)
+	GetMemberRooms(ctx context.Context, in *GetMemberRoomsRequest, opts ...grpc.CallOption) (*RoomsResponse, error)
+	CreateRoomScript(ctx context.Context, in *CreateRoomScriptRequest, opts ...grpc.CallOption) (*RoomScript, error)
+	UpdateRoomScript(ctx context.Context, in *UpdateRoomScriptRequest, opts ...grpc.CallOption) (*RoomScript, error)
+	DeleteRoomScript(ctx context.Context, in *DeleteRoomScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetRoomScripts(ctx context.Context, in *GetRoomScriptsRequest, opts ...grpc.CallOption) (*RoomScriptsResponse, error)
+	// Only callable with the service token; used by the chat service to run scripts.
+	GetEnabledScripts(ctx context.Context, in *GetEnabledScriptsRequest, opts ...grpc.CallOption) (*RoomScriptsResponse, error)
+	CreateRoomWebhook(ctx context.Context, in *CreateRoomWebhookRequest, opts ...grpc.CallOption) (*RoomWebhook, error)
+	UpdateRoomWebhook(ctx context.Context, in *UpdateRoomWebhookRequest, opts ...grpc.CallOption) (*RoomWebhook, error)
+	DeleteRoomWebhook(ctx context.Context, in *DeleteRoomWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetRoomWebhooks(ctx context.Context, in *GetRoomWebhooksRequest, opts ...grpc.CallOption) (*RoomWebhooksResponse, error)
+	GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*WebhookDeliveriesResponse, error)
+	GetWebhookDeadLetters(ctx context.Context, in *GetWebhookDeadLettersRequest, opts ...grpc.CallOption) (*WebhookDeadLettersResponse, error)
+	RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Only callable with the service token; used by the chat service to queue
+	// events for the webhooks of a room.
+	PublishRoomEvent(ctx context.Context, in *PublishRoomEventRequest, opts ...grpc.CallOption) (*PublishRoomEventResponse, error)
+	CreateIncomingWebhook(ctx context.Context, in *CreateIncomingWebhookRequest, opts ...grpc.CallOption) (*CreateIncomingWebhookResponse, error)
+	GetIncomingWebhooks(ctx context.Context, in *GetIncomingWebhooksRequest, opts ...grpc.CallOption) (*IncomingWebhooksResponse, error)
+	DeleteIncomingWebhook(ctx context.Context, in *DeleteIncomingWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Only callable with the service token; used by the chat service to find the
+	// room and name of the incoming webhook a token belongs to.
+	ResolveIncomingWebhook(ctx context.Context, in *ResolveIncomingWebhookRequest, opts ...grpc.CallOption) (*IncomingWebhook, error)
+	SetRetentionPolicy(ctx context.Context, in *SetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error)
+	GetRetentionPolicy(ctx context.Context, in *GetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error)
+	// Only callable with the service token; used by the chat service to purge
+	// expired messages.
+	GetRetentionPolicies(ctx context.Context, in *GetRetentionPoliciesRequest, opts ...grpc.CallOption) (*RetentionPoliciesResponse, error)
 }
 
 type roomServiceClient struct {
@@ -108,6 +161,236 @@ func (c *roomServiceClient) GetAllRooms(ctx context.Context, in *GetAllRoomsRequ
 	return out, nil
 }
 
+func (c *roomServiceClient) GetOrCreateDirectRoom(ctx context.Context, in *GetOrCreateDirectRoomRequest, opts ...grpc.CallOption) (*CreateRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateRoomResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetOrCreateDirectRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) CreateGroupRoom(ctx context.Context, in *CreateGroupRoomRequest, opts ...grpc.CallOption) (*CreateRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateRoomResponse)
+	err := c.cc.Invoke(ctx, RoomService_CreateGroupRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetMemberRooms(ctx context.Context, in *GetMemberRoomsRequest, opts ...grpc.CallOption) (*RoomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomsResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetMemberRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) CreateRoomScript(ctx context.Context, in *CreateRoomScriptRequest, opts ...grpc.CallOption) (*RoomScript, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomScript)
+	err := c.cc.Invoke(ctx, RoomService_CreateRoomScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) UpdateRoomScript(ctx context.Context, in *UpdateRoomScriptRequest, opts ...grpc.CallOption) (*RoomScript, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomScript)
+	err := c.cc.Invoke(ctx, RoomService_UpdateRoomScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) DeleteRoomScript(ctx context.Context, in *DeleteRoomScriptRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, RoomService_DeleteRoomScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetRoomScripts(ctx context.Context, in *GetRoomScriptsRequest, opts ...grpc.CallOption) (*RoomScriptsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomScriptsResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetRoomScripts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetEnabledScripts(ctx context.Context, in *GetEnabledScriptsRequest, opts ...grpc.CallOption) (*RoomScriptsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomScriptsResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetEnabledScripts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) CreateRoomWebhook(ctx context.Context, in *CreateRoomWebhookRequest, opts ...grpc.CallOption) (*RoomWebhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomWebhook)
+	err := c.cc.Invoke(ctx, RoomService_CreateRoomWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) UpdateRoomWebhook(ctx context.Context, in *UpdateRoomWebhookRequest, opts ...grpc.CallOption) (*RoomWebhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomWebhook)
+	err := c.cc.Invoke(ctx, RoomService_UpdateRoomWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) DeleteRoomWebhook(ctx context.Context, in *DeleteRoomWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, RoomService_DeleteRoomWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetRoomWebhooks(ctx context.Context, in *GetRoomWebhooksRequest, opts ...grpc.CallOption) (*RoomWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomWebhooksResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetRoomWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*WebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetWebhookDeadLetters(ctx context.Context, in *GetWebhookDeadLettersRequest, opts ...grpc.CallOption) (*WebhookDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookDeadLettersResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetWebhookDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, RoomService_RedeliverWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) PublishRoomEvent(ctx context.Context, in *PublishRoomEventRequest, opts ...grpc.CallOption) (*PublishRoomEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishRoomEventResponse)
+	err := c.cc.Invoke(ctx, RoomService_PublishRoomEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) CreateIncomingWebhook(ctx context.Context, in *CreateIncomingWebhookRequest, opts ...grpc.CallOption) (*CreateIncomingWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateIncomingWebhookResponse)
+	err := c.cc.Invoke(ctx, RoomService_CreateIncomingWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetIncomingWebhooks(ctx context.Context, in *GetIncomingWebhooksRequest, opts ...grpc.CallOption) (*IncomingWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncomingWebhooksResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetIncomingWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) DeleteIncomingWebhook(ctx context.Context, in *DeleteIncomingWebhookRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, RoomService_DeleteIncomingWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) ResolveIncomingWebhook(ctx context.Context, in *ResolveIncomingWebhookRequest, opts ...grpc.CallOption) (*IncomingWebhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncomingWebhook)
+	err := c.cc.Invoke(ctx, RoomService_ResolveIncomingWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) SetRetentionPolicy(ctx context.Context, in *SetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPolicy)
+	err := c.cc.Invoke(ctx, RoomService_SetRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetRetentionPolicy(ctx context.Context, in *GetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPolicy)
+	err := c.cc.Invoke(ctx, RoomService_GetRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetRetentionPolicies(ctx context.Context, in *GetRetentionPoliciesRequest, opts ...grpc.CallOption) (*RetentionPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPoliciesResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetRetentionPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RoomServiceServer is the server API for RoomService service.
 // All implementations must embed UnimplementedRoomServiceServer
 // for forward compatibility.
@@ -118,6 +401,36 @@ type RoomServiceServer interface {
 	SearchRooms(context.Context, *SearchRoomsRequest) (*RoomsResponse, error)
 	DeleteRoom(context.Context, *DeleteRoomRequest) (*emptypb.Empty, error)
 	GetAllRooms(context.Context, *GetAllRoomsRequest) (*RoomsResponse, error)
+	GetOrCreateDirectRoom(context.Context, *GetOrCreateDirectRoomRequest) (*CreateRoomResponse, error)
+	CreateGroupRoom(context.Context, *CreateGroupRoomRequest) (*CreateRoomResponse, error)
+	GetMemberRooms(context.Context, *GetMemberRoomsRequest) (*RoomsResponse, error)
+	CreateRoomScript(context.Context, *CreateRoomScriptRequest) (*RoomScript, error)
+	UpdateRoomScript(context.Context, *UpdateRoomScriptRequest) (*RoomScript, error)
+	DeleteRoomScript(context.Context, *DeleteRoomScriptRequest) (*emptypb.Empty, error)
+	GetRoomScripts(context.Context, *GetRoomScriptsRequest) (*RoomScriptsResponse, error)
+	// Only callable with the service token; used by the chat service to run scripts.
+	GetEnabledScripts(context.Context, *GetEnabledScriptsRequest) (*RoomScriptsResponse, error)
+	CreateRoomWebhook(context.Context, *CreateRoomWebhookRequest) (*RoomWebhook, error)
+	UpdateRoomWebhook(context.Context, *UpdateRoomWebhookRequest) (*RoomWebhook, error)
+	DeleteRoomWebhook(context.Context, *DeleteRoomWebhookRequest) (*emptypb.Empty, error)
+	GetRoomWebhooks(context.Context, *GetRoomWebhooksRequest) (*RoomWebhooksResponse, error)
+	GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*WebhookDeliveriesResponse, error)
+	GetWebhookDeadLetters(context.Context, *GetWebhookDeadLettersRequest) (*WebhookDeadLettersResponse, error)
+	RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*emptypb.Empty, error)
+	// Only callable with the service token; used by the chat service to queue
+	// events for the webhooks of a room.
+	PublishRoomEvent(context.Context, *PublishRoomEventRequest) (*PublishRoomEventResponse, error)
+	CreateIncomingWebhook(context.Context, *CreateIncomingWebhookRequest) (*CreateIncomingWebhookResponse, error)
+	GetIncomingWebhooks(context.Context, *GetIncomingWebhooksRequest) (*IncomingWebhooksResponse, error)
+	DeleteIncomingWebhook(context.Context, *DeleteIncomingWebhookRequest) (*emptypb.Empty, error)
+	// Only callable with the service token; used by the chat service to find the
+	// room and name of the incoming webhook a token belongs to.
+	ResolveIncomingWebhook(context.Context, *ResolveIncomingWebhookRequest) (*IncomingWebhook, error)
+	SetRetentionPolicy(context.Context, *SetRetentionPolicyRequest) (*RetentionPolicy, error)
+	GetRetentionPolicy(context.Context, *GetRetentionPolicyRequest) (*RetentionPolicy, error)
+	// Only callable with the service token; used by the chat service to purge
+	// expired messages.
+	GetRetentionPolicies(context.Context, *GetRetentionPoliciesRequest) (*RetentionPoliciesResponse, error)
 	mustEmbedUnimplementedRoomServiceServer()
 }
 
@@ -146,6 +459,75 @@ func (UnimplementedRoomServiceServer) DeleteRoom(context.Context, *DeleteRoomReq
 func (UnimplementedRoomServiceServer) GetAllRooms(context.Context, *GetAllRoomsRequest) (*RoomsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllRooms not implemented")
 }
+func (UnimplementedRoomServiceServer) GetOrCreateDirectRoom(context.Context, *GetOrCreateDirectRoomRequest) (*CreateRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrCreateDirectRoom not implemented")
+}
+func (UnimplementedRoomServiceServer) CreateGroupRoom(context.Context, *CreateGroupRoomRequest) (*CreateRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroupRoom not implemented")
+}
+func (UnimplementedRoomServiceServer) GetMemberRooms(context.Context, *GetMemberRoomsRequest) (*RoomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemberRooms not implemented")
+}
+func (UnimplementedRoomServiceServer) CreateRoomScript(context.Context, *CreateRoomScriptRequest) (*RoomScript, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRoomScript not implemented")
+}
+func (UnimplementedRoomServiceServer) UpdateRoomScript(context.Context, *UpdateRoomScriptRequest) (*RoomScript, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoomScript not implemented")
+}
+func (UnimplementedRoomServiceServer) DeleteRoomScript(context.Context, *DeleteRoomScriptRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRoomScript not implemented")
+}
+func (UnimplementedRoomServiceServer) GetRoomScripts(context.Context, *GetRoomScriptsRequest) (*RoomScriptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomScripts not implemented")
+}
+func (UnimplementedRoomServiceServer) GetEnabledScripts(context.Context, *GetEnabledScriptsRequest) (*RoomScriptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEnabledScripts not implemented")
+}
+func (UnimplementedRoomServiceServer) CreateRoomWebhook(context.Context, *CreateRoomWebhookRequest) (*RoomWebhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRoomWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) UpdateRoomWebhook(context.Context, *UpdateRoomWebhookRequest) (*RoomWebhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoomWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) DeleteRoomWebhook(context.Context, *DeleteRoomWebhookRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRoomWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) GetRoomWebhooks(context.Context, *GetRoomWebhooksRequest) (*RoomWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomWebhooks not implemented")
+}
+func (UnimplementedRoomServiceServer) GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*WebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeliveries not implemented")
+}
+func (UnimplementedRoomServiceServer) GetWebhookDeadLetters(context.Context, *GetWebhookDeadLettersRequest) (*WebhookDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeadLetters not implemented")
+}
+func (UnimplementedRoomServiceServer) RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) PublishRoomEvent(context.Context, *PublishRoomEventRequest) (*PublishRoomEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishRoomEvent not implemented")
+}
+func (UnimplementedRoomServiceServer) CreateIncomingWebhook(context.Context, *CreateIncomingWebhookRequest) (*CreateIncomingWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIncomingWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) GetIncomingWebhooks(context.Context, *GetIncomingWebhooksRequest) (*IncomingWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIncomingWebhooks not implemented")
+}
+func (UnimplementedRoomServiceServer) DeleteIncomingWebhook(context.Context, *DeleteIncomingWebhookRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteIncomingWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) ResolveIncomingWebhook(context.Context, *ResolveIncomingWebhookRequest) (*IncomingWebhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveIncomingWebhook not implemented")
+}
+func (UnimplementedRoomServiceServer) SetRetentionPolicy(context.Context, *SetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRetentionPolicy not implemented")
+}
+func (UnimplementedRoomServiceServer) GetRetentionPolicy(context.Context, *GetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionPolicy not implemented")
+}
+func (UnimplementedRoomServiceServer) GetRetentionPolicies(context.Context, *GetRetentionPoliciesRequest) (*RetentionPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionPolicies not implemented")
+}
 func (UnimplementedRoomServiceServer) mustEmbedUnimplementedRoomServiceServer() {}
 func (UnimplementedRoomServiceServer) testEmbeddedByValue()                     {}
 
@@ -275,36 +657,542 @@ func _RoomService_GetAllRooms_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-// RoomService_ServiceDesc is the grpc.ServiceDesc for RoomService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var RoomService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "website.RoomService",
-	HandlerType: (*RoomServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateRoom",
-			Handler:    _RoomService_CreateRoom_Handler,
-		},
-		{
-			MethodName: "GetRoom",
-			Handler:    _RoomService_GetRoom_Handler,
-		},
-		{
-			MethodName: "GetOwnerRooms",
-			Handler:    _RoomService_GetOwnerRooms_Handler,
-		},
-		{
-			MethodName: "SearchRooms",
-			Handler:    _RoomService_SearchRooms_Handler,
-		},
-		{
-			MethodName: "DeleteRoom",
-			Handler:    _RoomService_DeleteRoom_Handler,
-		},
-		{
-			MethodName: "GetAllRooms",
-			Handler:    _RoomService_GetAllRooms_Handler,
+func _RoomService_GetOrCreateDirectRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrCreateDirectRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetOrCreateDirectRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetOrCreateDirectRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetOrCreateDirectRoom(ctx, req.(*GetOrCreateDirectRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_CreateGroupRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).CreateGroupRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_CreateGroupRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).CreateGroupRoom(ctx, req.(*CreateGroupRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetMemberRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemberRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetMemberRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetMemberRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetMemberRooms(ctx, req.(*GetMemberRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_CreateRoomScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoomScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).CreateRoomScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_CreateRoomScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).CreateRoomScript(ctx, req.(*CreateRoomScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_UpdateRoomScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoomScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).UpdateRoomScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_UpdateRoomScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).UpdateRoomScript(ctx, req.(*UpdateRoomScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_DeleteRoomScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoomScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).DeleteRoomScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_DeleteRoomScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).DeleteRoomScript(ctx, req.(*DeleteRoomScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetRoomScripts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomScriptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetRoomScripts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetRoomScripts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetRoomScripts(ctx, req.(*GetRoomScriptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetEnabledScripts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEnabledScriptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetEnabledScripts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetEnabledScripts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetEnabledScripts(ctx, req.(*GetEnabledScriptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_CreateRoomWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoomWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).CreateRoomWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_CreateRoomWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).CreateRoomWebhook(ctx, req.(*CreateRoomWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_UpdateRoomWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoomWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).UpdateRoomWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_UpdateRoomWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).UpdateRoomWebhook(ctx, req.(*UpdateRoomWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_DeleteRoomWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoomWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).DeleteRoomWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_DeleteRoomWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).DeleteRoomWebhook(ctx, req.(*DeleteRoomWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetRoomWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetRoomWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetRoomWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetRoomWebhooks(ctx, req.(*GetRoomWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetWebhookDeliveries(ctx, req.(*GetWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetWebhookDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetWebhookDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetWebhookDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetWebhookDeadLetters(ctx, req.(*GetWebhookDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_RedeliverWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).RedeliverWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_RedeliverWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).RedeliverWebhook(ctx, req.(*RedeliverWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_PublishRoomEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRoomEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).PublishRoomEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_PublishRoomEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).PublishRoomEvent(ctx, req.(*PublishRoomEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_CreateIncomingWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIncomingWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).CreateIncomingWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_CreateIncomingWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).CreateIncomingWebhook(ctx, req.(*CreateIncomingWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetIncomingWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIncomingWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetIncomingWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetIncomingWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetIncomingWebhooks(ctx, req.(*GetIncomingWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_DeleteIncomingWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteIncomingWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).DeleteIncomingWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_DeleteIncomingWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).DeleteIncomingWebhook(ctx, req.(*DeleteIncomingWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_ResolveIncomingWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveIncomingWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).ResolveIncomingWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_ResolveIncomingWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).ResolveIncomingWebhook(ctx, req.(*ResolveIncomingWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_SetRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).SetRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_SetRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).SetRetentionPolicy(ctx, req.(*SetRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetRetentionPolicy(ctx, req.(*GetRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetRetentionPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetentionPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetRetentionPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetRetentionPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetRetentionPolicies(ctx, req.(*GetRetentionPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RoomService_ServiceDesc is the grpc.ServiceDesc for RoomService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RoomService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "website.RoomService",
+	HandlerType: (*RoomServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateRoom",
+			Handler:    _RoomService_CreateRoom_Handler,
+		},
+		{
+			MethodName: "GetRoom",
+			Handler:    _RoomService_GetRoom_Handler,
+		},
+		{
+			MethodName: "GetOwnerRooms",
+			Handler:    _RoomService_GetOwnerRooms_Handler,
+		},
+		{
+			MethodName: "SearchRooms",
+			Handler:    _RoomService_SearchRooms_Handler,
+		},
+		{
+			MethodName: "DeleteRoom",
+			Handler:    _RoomService_DeleteRoom_Handler,
+		},
+		{
+			MethodName: "GetAllRooms",
+			Handler:    _RoomService_GetAllRooms_Handler,
+		},
+		{
+			MethodName: "GetOrCreateDirectRoom",
+			Handler:    _RoomService_GetOrCreateDirectRoom_Handler,
+		},
+		{
+			MethodName: "CreateGroupRoom",
+			Handler:    _RoomService_CreateGroupRoom_Handler,
+		},
+		{
+			MethodName: "GetMemberRooms",
+			Handler:    _RoomService_GetMemberRooms_Handler,
+		},
+		{
+			MethodName: "CreateRoomScript",
+			Handler:    _RoomService_CreateRoomScript_Handler,
+		},
+		{
+			MethodName: "UpdateRoomScript",
+			Handler:    _RoomService_UpdateRoomScript_Handler,
+		},
+		{
+			MethodName: "DeleteRoomScript",
+			Handler:    _RoomService_DeleteRoomScript_Handler,
+		},
+		{
+			MethodName: "GetRoomScripts",
+			Handler:    _RoomService_GetRoomScripts_Handler,
+		},
+		{
+			MethodName: "GetEnabledScripts",
+			Handler:    _RoomService_GetEnabledScripts_Handler,
+		},
+		{
+			MethodName: "CreateRoomWebhook",
+			Handler:    _RoomService_CreateRoomWebhook_Handler,
+		},
+		{
+			MethodName: "UpdateRoomWebhook",
+			Handler:    _RoomService_UpdateRoomWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteRoomWebhook",
+			Handler:    _RoomService_DeleteRoomWebhook_Handler,
+		},
+		{
+			MethodName: "GetRoomWebhooks",
+			Handler:    _RoomService_GetRoomWebhooks_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeliveries",
+			Handler:    _RoomService_GetWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeadLetters",
+			Handler:    _RoomService_GetWebhookDeadLetters_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhook",
+			Handler:    _RoomService_RedeliverWebhook_Handler,
+		},
+		{
+			MethodName: "PublishRoomEvent",
+			Handler:    _RoomService_PublishRoomEvent_Handler,
+		},
+		{
+			MethodName: "CreateIncomingWebhook",
+			Handler:    _RoomService_CreateIncomingWebhook_Handler,
+		},
+		{
+			MethodName: "GetIncomingWebhooks",
+			Handler:    _RoomService_GetIncomingWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteIncomingWebhook",
+			Handler:    _RoomService_DeleteIncomingWebhook_Handler,
+		},
+		{
+			MethodName: "ResolveIncomingWebhook",
+			Handler:    _RoomService_ResolveIncomingWebhook_Handler,
+		},
+		{
+			MethodName: "SetRetentionPolicy",
+			Handler:    _RoomService_SetRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "GetRetentionPolicy",
+			Handler:    _RoomService_GetRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "GetRetentionPolicies",
+			Handler:    _RoomService_GetRetentionPolicies_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,245 @@
+// Package roomscript defines the Starlark dialect of room scripts: the hooks a
+// script may implement, the built-ins it can call and how a hook is run.
+//
+// A script is a Starlark file that defines any of the hook functions:
+//
+//	def on_message(msg):   # msg.room_id, msg.user_id, msg.content, msg.parent_id
+//	    if "spam" in msg.content:
+//	        return reject("no spam please")
+//	    return msg.content.replace(":)", "🙂")
+//
+//	def on_join(event):    # event.room_id, event.user_id
+//	    reply("Welcome!")
+//
+//	def on_schedule(event):  # event.room_id
+//	    reply("Daily stand-up in 5 minutes")
+//
+// on_message may return None to keep the message, a string to replace its
+// content, or reject(reason) to refuse it. Every hook may call reply(text) to
+// post a message to the room. Scripts cannot load modules, recurse or loop with
+// while, and run under a step limit.
+package roomscript
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// Hook names.
+const (
+	HookMessage  = "on_message"
+	HookJoin     = "on_join"
+	HookSchedule = "on_schedule"
+)
+
+const (
+	// MaxReplies bounds the replies a single hook call may post.
+	MaxReplies = 5
+	// MaxTextLength bounds replies, rejection reasons and replaced content, in characters.
+	MaxTextLength = 4000
+	// maxErrorLength bounds the error reported for a failed hook.
+	maxErrorLength = 500
+)
+
+// fileOptions keeps the dialect small: no while loops, recursion or top-level control flow.
+var fileOptions = &syntax.FileOptions{Set: true}
+
+// Event is the argument a hook is called with. Fields a hook does not use are empty.
+type Event struct {
+	RoomID   string `json:"room_id"`
+	UserID   string `json:"user_id,omitempty"`
+	Content  string `json:"content,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// Request asks for one hook of a script to be run.
+type Request struct {
+	ScriptID string `json:"script_id"`
+	Source   string `json:"source"`
+	Hook     string `json:"hook"`
+	Event    Event  `json:"event"`
+	MaxSteps uint64 `json:"max_steps"`
+}
+
+// Result is the outcome of a hook. A failed hook only reports its Error.
+type Result struct {
+	Content  *string  `json:"content,omitempty"` // Replacement content returned by on_message.
+	Rejected bool     `json:"rejected,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+	Replies  []string `json:"replies,omitempty"`
+	Steps    uint64   `json:"steps"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Compile checks a script for syntax errors and references to undefined names
+// without running it.
+func Compile(name, source string) error {
+	_, _, err := starlark.SourceProgramOptions(fileOptions, name, source, builtins().Has)
+	return err
+}
+
+// Run runs one hook of a script. A script that does not define the hook leaves
+// everything unchanged. Run does not bound memory; callers run it in a process
+// of its own for that.
+func Run(req *Request) *Result {
+	state := &hookState{}
+	thread := &starlark.Thread{
+		Name:  req.ScriptID,
+		Print: func(*starlark.Thread, string) {},
+	}
+	thread.SetLocal(stateKey, state)
+	if req.MaxSteps > 0 {
+		thread.SetMaxExecutionSteps(req.MaxSteps)
+	}
+
+	result, err := run(thread, state, req)
+	if err != nil {
+		result = &Result{Error: truncate(err.Error(), maxErrorLength)}
+	}
+	result.Steps = thread.ExecutionSteps()
+	return result
+}
+
+func run(thread *starlark.Thread, state *hookState, req *Request) (*Result, error) {
+	switch req.Hook {
+	case HookMessage, HookJoin, HookSchedule:
+	default:
+		return nil, errors.Errorf("unknown hook %q", req.Hook)
+	}
+
+	globals, err := starlark.ExecFileOptions(fileOptions, thread, req.ScriptID+".star", req.Source, builtins())
+	if err != nil {
+		return nil, err
+	}
+
+	hook, ok := globals[req.Hook].(starlark.Callable)
+	if !ok {
+		return &Result{}, nil
+	}
+
+	value, err := starlark.Call(thread, hook, starlark.Tuple{eventValue(req.Hook, req.Event)}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Replies: state.replies}
+	switch v := value.(type) {
+	case starlark.NoneType:
+	case *rejection:
+		if req.Hook != HookMessage {
+			return nil, errors.Errorf("%s cannot reject", req.Hook)
+		}
+		result.Rejected = true
+		result.Reason = v.reason
+	case starlark.String:
+		if req.Hook != HookMessage {
+			return nil, errors.Errorf("%s must return None", req.Hook)
+		}
+		content := string(v)
+		if err := checkText(content); err != nil {
+			return nil, errors.Wrap(err, "invalid content")
+		}
+		result.Content = &content
+	default:
+		return nil, errors.Errorf("%s returned %s, want None, string or reject()", req.Hook, value.Type())
+	}
+
+	return result, nil
+}
+
+// eventValue builds the struct a hook is called with.
+func eventValue(hook string, event Event) starlark.Value {
+	fields := starlark.StringDict{
+		"room_id": starlark.String(event.RoomID),
+	}
+	if hook != HookSchedule {
+		fields["user_id"] = starlark.String(event.UserID)
+	}
+	if hook == HookMessage {
+		fields["content"] = starlark.String(event.Content)
+		fields["parent_id"] = starlark.None
+		if event.ParentID != "" {
+			fields["parent_id"] = starlark.String(event.ParentID)
+		}
+	}
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, fields)
+}
+
+const stateKey = "roomscript.state"
+
+// hookState collects what a hook call asked for through the built-ins.
+type hookState struct {
+	replies []string
+}
+
+// rejection is the value returned by reject().
+type rejection struct {
+	reason string
+}
+
+func (r *rejection) String() string        { return fmt.Sprintf("reject(%q)", r.reason) }
+func (r *rejection) Type() string          { return "rejection" }
+func (r *rejection) Freeze()               {}
+func (r *rejection) Truth() starlark.Bool  { return starlark.True }
+func (r *rejection) Hash() (uint32, error) { return starlark.String(r.reason).Hash() }
+
+func builtins() starlark.StringDict {
+	return starlark.StringDict{
+		"reply":  starlark.NewBuiltin("reply", reply),
+		"reject": starlark.NewBuiltin("reject", reject),
+		"struct": starlark.NewBuiltin("struct", starlarkstruct.Make),
+	}
+}
+
+func reply(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &text); err != nil {
+		return nil, err
+	}
+	if err := checkText(text); err != nil {
+		return nil, errors.Wrap(err, b.Name())
+	}
+
+	state := thread.Local(stateKey).(*hookState)
+	if len(state.replies) >= MaxReplies {
+		return nil, errors.Errorf("%s: at most %d replies per call", b.Name(), MaxReplies)
+	}
+	state.replies = append(state.replies, text)
+
+	return starlark.None, nil
+}
+
+func reject(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	reason := ""
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0, &reason); err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(reason) > MaxTextLength {
+		return nil, errors.Errorf("%s: reason exceeds %d characters", b.Name(), MaxTextLength)
+	}
+
+	return &rejection{reason: reason}, nil
+}
+
+func checkText(text string) error {
+	if text == "" {
+		return errors.New("text is empty")
+	}
+	if utf8.RuneCountInString(text) > MaxTextLength {
+		return errors.Errorf("text exceeds %d characters", MaxTextLength)
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "") + "…"
+}
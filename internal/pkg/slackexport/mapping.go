@@ -0,0 +1,62 @@
+package slackexport
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Mapping ties the users and conversations of a Slack export to the go-chat
+// users and rooms they were imported as. The Website Service import writes
+// it; the Chat Service import reads it to attribute and place messages.
+type Mapping struct {
+	Users map[string]MappedUser `json:"users"` // By Slack user ID.
+	Rooms map[string]uuid.UUID  `json:"rooms"` // By Slack conversation ID.
+}
+
+type MappedUser struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+// LoadMapping reads a mapping file. A missing file yields an empty mapping.
+func LoadMapping(fileName string) (*Mapping, error) {
+	mapping := &Mapping{
+		Users: make(map[string]MappedUser),
+		Rooms: make(map[string]uuid.UUID),
+	}
+
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return mapping, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read mapping")
+	}
+
+	if err := json.Unmarshal(data, mapping); err != nil {
+		return nil, errors.Wrap(err, "failed to decode mapping")
+	}
+	if mapping.Users == nil {
+		mapping.Users = make(map[string]MappedUser)
+	}
+	if mapping.Rooms == nil {
+		mapping.Rooms = make(map[string]uuid.UUID)
+	}
+	return mapping, nil
+}
+
+// Save writes the mapping to a file, replacing it.
+func (m *Mapping) Save(fileName string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode mapping")
+	}
+
+	if err := os.WriteFile(fileName, data, 0o600); err != nil {
+		return errors.Wrap(err, "failed to write mapping")
+	}
+	return nil
+}
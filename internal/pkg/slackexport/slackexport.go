@@ -0,0 +1,251 @@
+// Package slackexport reads the zip archives produced by Slack's workspace
+// export and the mapping shared by the import tools of the Website and Chat
+// services.
+//
+// An archive holds users.json, the lists of conversations (channels.json for
+// public channels and, in full exports, groups.json, dms.json and mpims.json
+// for private channels, direct messages and group direct messages) and one
+// folder per conversation with a JSON file of messages per day:
+//
+//	users.json
+//	channels.json
+//	general/2019-03-01.json
+//	general/2019-03-02.json
+//	D024BE91L/2019-03-01.json
+//
+// Public and private channels and group direct messages are stored under their
+// name, direct messages under their ID.
+package slackexport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConversationKind tells what kind of Slack conversation a room comes from.
+type ConversationKind string
+
+const (
+	KindChannel ConversationKind = "channel" // Public channel.
+	KindPrivate ConversationKind = "private" // Private channel.
+	KindDirect  ConversationKind = "direct"  // Direct message between two users.
+	KindGroup   ConversationKind = "group"   // Group direct message.
+)
+
+// conversationLists maps the files listing conversations to their kind.
+var conversationLists = []struct {
+	file string
+	kind ConversationKind
+}{
+	{"channels.json", KindChannel},
+	{"groups.json", KindPrivate},
+	{"dms.json", KindDirect},
+	{"mpims.json", KindGroup},
+}
+
+type User struct {
+	ID      string `json:"id"`
+	TeamID  string `json:"team_id"`
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+	IsBot   bool   `json:"is_bot"`
+	Profile struct {
+		Email       string `json:"email"`
+		RealName    string `json:"real_name"`
+		DisplayName string `json:"display_name"`
+	} `json:"profile"`
+}
+
+type Conversation struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Created int64    `json:"created"` // Unix time.
+	Creator string   `json:"creator"`
+	Members []string `json:"members"`
+	Topic   struct {
+		Value string `json:"value"`
+	} `json:"topic"`
+
+	Kind ConversationKind `json:"-"`
+}
+
+// Dir returns the folder holding the messages of the conversation.
+func (c *Conversation) Dir() string {
+	if c.Kind == KindDirect {
+		return c.ID
+	}
+	return c.Name
+}
+
+type Message struct {
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	User     string `json:"user"`
+	BotID    string `json:"bot_id"`
+	Username string `json:"username"` // Set on bot messages.
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+	Edited   *struct {
+		TS string `json:"ts"`
+	} `json:"edited"`
+	BotProfile *struct {
+		Name string `json:"name"`
+	} `json:"bot_profile"`
+	Files []struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	} `json:"files"`
+}
+
+// IsReply reports whether the message answers another message of a thread.
+func (m *Message) IsReply() bool {
+	return m.ThreadTS != "" && m.ThreadTS != m.TS
+}
+
+// Time returns when the message was posted.
+func (m *Message) Time() (time.Time, error) {
+	return ParseTS(m.TS)
+}
+
+// ParseTS parses a Slack message timestamp, the Unix time in seconds with a
+// microsecond fraction, e.g. "1551398400.000200". Within a conversation it
+// also identifies the message.
+func ParseTS(ts string) (time.Time, error) {
+	seconds, fraction, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid timestamp %q", ts)
+	}
+
+	var usec int64
+	if fraction != "" {
+		fraction = (fraction + "000000")[:6]
+		if usec, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+			return time.Time{}, errors.Errorf("invalid timestamp %q", ts)
+		}
+	}
+	return time.Unix(sec, usec*int64(time.Microsecond)).UTC(), nil
+}
+
+// Archive is an opened Slack export.
+type Archive struct {
+	zip   *zip.ReadCloser
+	root  string // Folder holding users.json, empty at the top of the zip.
+	files map[string]*zip.File
+}
+
+// Open opens the export at fileName. Exports that were unpacked and zipped again
+// under a top folder are accepted too.
+func Open(fileName string) (*Archive, error) {
+	r, err := zip.OpenReader(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+
+	a := &Archive{zip: r, files: make(map[string]*zip.File, len(r.File))}
+	root := ""
+	found := false
+	for _, f := range r.File {
+		a.files[f.Name] = f
+		if dir, file := path.Split(f.Name); file == "users.json" && (!found || len(dir) < len(root)) {
+			root, found = dir, true
+		}
+	}
+	if !found {
+		r.Close()
+		return nil, errors.New("users.json not found, not a Slack export")
+	}
+
+	a.root = root
+	return a, nil
+}
+
+func (a *Archive) Close() error {
+	return a.zip.Close()
+}
+
+// Users returns every user of the workspace, including deactivated ones.
+func (a *Archive) Users() ([]User, error) {
+	var users []User
+	if err := a.decode("users.json", &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Conversations returns the conversations of the export. Lists missing from
+// the export, e.g. private ones from a standard export, are skipped.
+func (a *Archive) Conversations() ([]Conversation, error) {
+	var conversations []Conversation
+	for _, list := range conversationLists {
+		if _, ok := a.files[a.root+list.file]; !ok {
+			continue
+		}
+
+		var listed []Conversation
+		if err := a.decode(list.file, &listed); err != nil {
+			return nil, err
+		}
+		for i := range listed {
+			listed[i].Kind = list.kind
+		}
+		conversations = append(conversations, listed...)
+	}
+	return conversations, nil
+}
+
+// Messages calls fn for every message of a conversation, oldest day first
+// and in the order of the export within a day. It stops at the first error
+// returned by fn.
+func (a *Archive) Messages(conversation *Conversation, fn func(*Message) error) error {
+	prefix := a.root + conversation.Dir() + "/"
+
+	var days []string
+	for name := range a.files {
+		if dir, file := path.Split(name); dir == prefix && strings.HasSuffix(file, ".json") {
+			days = append(days, name)
+		}
+	}
+	// Days are named YYYY-MM-DD.json, so they sort by date.
+	sort.Strings(days)
+
+	for _, day := range days {
+		var messages []Message
+		if err := a.decode(strings.TrimPrefix(day, a.root), &messages); err != nil {
+			return err
+		}
+		for i := range messages {
+			if err := fn(&messages[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Archive) decode(name string, v any) error {
+	f, ok := a.files[a.root+name]
+	if !ok {
+		return errors.Errorf("%s not found in archive", name)
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", name)
+	}
+	defer r.Close()
+
+	if err := json.NewDecoder(r).Decode(v); err != nil && err != io.EOF {
+		return errors.Wrapf(err, "failed to decode %s", name)
+	}
+	return nil
+}
@@ -12,16 +12,21 @@ import (
 	"github.com/HexArch/go-chat/internal/services/auth/internal/metrics"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/services/auth"
 	tokenstorage "github.com/HexArch/go-chat/internal/services/auth/internal/services/auth/storage"
+	"github.com/HexArch/go-chat/internal/services/auth/internal/services/bot"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/services/user"
 	userstorage "github.com/HexArch/go-chat/internal/services/auth/internal/services/user/storage"
 
+	createbot "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/create-bot"
 	createuser "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/create-user"
+	deletebot "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/delete-bot"
 	deleteuser "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/delete-user"
+	getbots "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/get-bots"
 	getuser "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/get-user"
 	getusers "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/get-users"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/login"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/logout"
 	refreshtoken "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/refresh-token"
+	rotatebottoken "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/rotate-bot-token"
 	updateuser "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/update-user"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/validatetoken"
 
@@ -61,6 +66,10 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 
 	// Initialize services.
 	userService := user.NewService(user.Deps{UserStorage: userStorage})
+	botService := bot.NewService(bot.Deps{
+		UserStorage:  userStorage,
+		TokenStorage: tokenStorage,
+	})
 	authService := auth.NewService(
 		auth.Deps{
 			UserStorage:  userStorage,
@@ -86,6 +95,10 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 	getUsersUC := getusers.New(getusers.Deps{UserService: userService})
 	updateUserUC := updateuser.New(updateuser.Deps{UserService: userService})
 	deleteUserUC := deleteuser.New(deleteuser.Deps{UserService: userService})
+	createBotUC := createbot.New(createbot.Deps{BotService: botService})
+	getBotsUC := getbots.New(getbots.Deps{BotService: botService})
+	rotateBotTokenUC := rotatebottoken.New(rotatebottoken.Deps{BotService: botService})
+	deleteBotUC := deletebot.New(deletebot.Deps{BotService: botService})
 
 	// Initialize metrics.
 	metrics := metrics.NewAuthMetrics("auth_service")
@@ -102,6 +115,10 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 		refreshTokenUC,
 		validateTokenUC,
 		logoutUC,
+		createBotUC,
+		getBotsUC,
+		rotateBotTokenUC,
+		deleteBotUC,
 	)
 
 	usersCtrl := controllers.NewUsersController(
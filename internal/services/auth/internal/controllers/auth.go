@@ -8,11 +8,16 @@ import (
 	"github.com/HexArch/go-chat/internal/services/auth/internal/controllers/middleware"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/metrics"
+	createbot "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/create-bot"
 	createuser "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/create-user"
+	deletebot "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/delete-bot"
+	getbots "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/get-bots"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/login"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/logout"
 	refreshtoken "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/refresh-token"
+	rotatebottoken "github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/rotate-bot-token"
 	"github.com/HexArch/go-chat/internal/services/auth/internal/use-cases/validatetoken"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -22,13 +27,17 @@ import (
 )
 
 type AuthController struct {
-	logger          *zap.Logger
-	metrics         *metrics.AuthMetrics
-	createUserUC    *createuser.UseCase
-	loginUC         *login.UseCase
-	refreshTokenUC  *refreshtoken.UseCase
-	validateTokenUC *validatetoken.UseCase
-	logoutUC        *logout.UseCase
+	logger           *zap.Logger
+	metrics          *metrics.AuthMetrics
+	createUserUC     *createuser.UseCase
+	loginUC          *login.UseCase
+	refreshTokenUC   *refreshtoken.UseCase
+	validateTokenUC  *validatetoken.UseCase
+	logoutUC         *logout.UseCase
+	createBotUC      *createbot.UseCase
+	getBotsUC        *getbots.UseCase
+	rotateBotTokenUC *rotatebottoken.UseCase
+	deleteBotUC      *deletebot.UseCase
 	auth.UnimplementedAuthServiceServer
 }
 
@@ -40,15 +49,23 @@ func NewAuthController(
 	refreshTokenUC *refreshtoken.UseCase,
 	validateTokenUC *validatetoken.UseCase,
 	logoutUC *logout.UseCase,
+	createBotUC *createbot.UseCase,
+	getBotsUC *getbots.UseCase,
+	rotateBotTokenUC *rotatebottoken.UseCase,
+	deleteBotUC *deletebot.UseCase,
 ) *AuthController {
 	return &AuthController{
-		logger:          logger,
-		metrics:         metrics,
-		createUserUC:    createUserUC,
-		loginUC:         loginUC,
-		refreshTokenUC:  refreshTokenUC,
-		validateTokenUC: validateTokenUC,
-		logoutUC:        logoutUC,
+		logger:           logger,
+		metrics:          metrics,
+		createUserUC:     createUserUC,
+		loginUC:          loginUC,
+		refreshTokenUC:   refreshTokenUC,
+		validateTokenUC:  validateTokenUC,
+		logoutUC:         logoutUC,
+		createBotUC:      createBotUC,
+		getBotsUC:        getBotsUC,
+		rotateBotTokenUC: rotateBotTokenUC,
+		deleteBotUC:      deleteBotUC,
 	}
 }
 
@@ -139,7 +156,8 @@ func (c *AuthController) ValidateToken(ctx context.Context, req *auth.ValidateTo
 
 	c.metrics.RecordTokenValidation("validation_success")
 	return &auth.ValidateTokenResponse{
-		User: c.mapUserToProto(user),
+		User:        c.mapUserToProto(user),
+		Permissions: c.permissionsToStrings(user.Permissions),
 	}, nil
 }
 
@@ -178,6 +196,12 @@ func (c *AuthController) mapErrorToStatus(err error) error {
 		return status.Error(codes.InvalidArgument, "invalid token claims")
 	case errors.Is(err, entities.ErrPermissionDenied):
 		return status.Error(codes.PermissionDenied, "permission denied")
+	case errors.Is(err, entities.ErrBotNotFound):
+		return status.Error(codes.NotFound, "bot not found")
+	case errors.Is(err, entities.ErrInvalidBot):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entities.ErrBotLimitReached):
+		return status.Errorf(codes.FailedPrecondition, "a user can have at most %d bots", entities.MaxBotsPerOwner)
 	default:
 		return status.Error(codes.Internal, "internal error")
 	}
@@ -194,6 +218,8 @@ func (c *AuthController) mapUserToProto(user *entities.User) *auth.User {
 		Permissions: c.permissionsToStrings(user.Permissions),
 		CreatedAt:   timestamppb.New(user.CreatedAt),
 		UpdatedAt:   timestamppb.New(user.UpdatedAt),
+		Type:        userTypeToProto(user.Type),
+		OwnerId:     ownerIDToProto(user.OwnerID),
 	}
 }
 
@@ -204,3 +230,17 @@ func (c *AuthController) permissionsToStrings(perms []entities.Permission) []str
 	}
 	return result
 }
+
+func userTypeToProto(userType entities.UserType) string {
+	if userType == "" {
+		return string(entities.UserTypeRegular)
+	}
+	return string(userType)
+}
+
+func ownerIDToProto(ownerID *uuid.UUID) string {
+	if ownerID == nil {
+		return ""
+	}
+	return ownerID.String()
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/auth"
+	"github.com/HexArch/go-chat/internal/services/auth/internal/controllers/middleware"
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (c *AuthController) CreateBot(ctx context.Context, req *auth.CreateBotRequest) (*auth.BotTokenResponse, error) {
+	start := time.Now()
+	defer func() {
+		c.metrics.RecordRequestDuration("bots", "create_bot", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		c.metrics.RecordError("create_bot_unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+
+	bot, token, err := c.createBotUC.Execute(ctx, ownerID, &entities.User{
+		Username: req.Username,
+		Bio:      req.Bio,
+	})
+	if err != nil {
+		c.metrics.RecordError("create_bot_failed")
+		c.logger.Error("Failed to create bot", zap.Error(err))
+		return nil, c.mapErrorToStatus(err)
+	}
+
+	c.logger.Info("Bot created",
+		zap.String("bot_id", bot.ID.String()),
+		zap.String("owner_id", ownerID.String()))
+
+	return &auth.BotTokenResponse{
+		Bot:   c.mapUserToProto(bot),
+		Token: token,
+	}, nil
+}
+
+func (c *AuthController) GetBots(ctx context.Context, req *auth.GetBotsRequest) (*auth.GetBotsResponse, error) {
+	start := time.Now()
+	defer func() {
+		c.metrics.RecordRequestDuration("bots", "get_bots", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		c.metrics.RecordError("get_bots_unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+
+	bots, err := c.getBotsUC.Execute(ctx, ownerID)
+	if err != nil {
+		c.metrics.RecordError("get_bots_failed")
+		c.logger.Error("Failed to get bots", zap.Error(err))
+		return nil, c.mapErrorToStatus(err)
+	}
+
+	response := &auth.GetBotsResponse{
+		Bots: make([]*auth.User, len(bots)),
+	}
+	for i, bot := range bots {
+		response.Bots[i] = c.mapUserToProto(bot)
+	}
+
+	return response, nil
+}
+
+func (c *AuthController) RotateBotToken(ctx context.Context, req *auth.RotateBotTokenRequest) (*auth.BotTokenResponse, error) {
+	start := time.Now()
+	defer func() {
+		c.metrics.RecordRequestDuration("bots", "rotate_bot_token", time.Since(start).Seconds())
+	}()
+
+	requesterID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		c.metrics.RecordError("rotate_bot_token_unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+
+	botID, err := uuid.Parse(req.BotId)
+	if err != nil {
+		c.metrics.RecordError("invalid_bot_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid bot ID format")
+	}
+
+	bot, token, err := c.rotateBotTokenUC.Execute(ctx, requesterID, botID)
+	if err != nil {
+		c.metrics.RecordError("rotate_bot_token_failed")
+		c.logger.Error("Failed to rotate bot token", zap.Error(err))
+		return nil, c.mapErrorToStatus(err)
+	}
+
+	c.logger.Info("Bot token rotated",
+		zap.String("bot_id", req.BotId),
+		zap.String("requester_id", requesterID.String()))
+
+	return &auth.BotTokenResponse{
+		Bot:   c.mapUserToProto(bot),
+		Token: token,
+	}, nil
+}
+
+func (c *AuthController) DeleteBot(ctx context.Context, req *auth.DeleteBotRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	defer func() {
+		c.metrics.RecordRequestDuration("bots", "delete_bot", time.Since(start).Seconds())
+	}()
+
+	requesterID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		c.metrics.RecordError("delete_bot_unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+
+	botID, err := uuid.Parse(req.BotId)
+	if err != nil {
+		c.metrics.RecordError("invalid_bot_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid bot ID format")
+	}
+
+	if err := c.deleteBotUC.Execute(ctx, requesterID, botID); err != nil {
+		c.metrics.RecordError("delete_bot_failed")
+		c.logger.Error("Failed to delete bot", zap.Error(err))
+		return nil, c.mapErrorToStatus(err)
+	}
+
+	c.logger.Info("Bot deleted",
+		zap.String("bot_id", req.BotId),
+		zap.String("requester_id", requesterID.String()))
+
+	return &emptypb.Empty{}, nil
+}
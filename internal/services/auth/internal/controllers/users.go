@@ -184,6 +184,8 @@ func (c *UsersController) mapUserToProto(user *entities.User) *auth.User {
 		Permissions: c.permissionsToStrings(user.Permissions),
 		CreatedAt:   timestamppb.New(user.CreatedAt),
 		UpdatedAt:   timestamppb.New(user.UpdatedAt),
+		Type:        userTypeToProto(user.Type),
+		OwnerId:     ownerIDToProto(user.OwnerID),
 	}
 }
 
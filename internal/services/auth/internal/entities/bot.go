@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// BotTokenPrefix starts every bot token, telling them apart from JWT access tokens.
+	BotTokenPrefix = "bot_"
+	// BotEmailDomain holds the placeholder emails of bots, which cannot log in.
+	BotEmailDomain = "bots.invalid"
+	// MaxBotsPerOwner bounds the number of bots a user may create.
+	MaxBotsPerOwner = 20
+	// MaxBotNameLength bounds the username of a bot.
+	MaxBotNameLength = 50
+)
+
+// BotToken is the long-lived credential of a bot. It does not expire; rotating
+// it replaces the previous one. Only its hash is stored.
+type BotToken struct {
+	Hash      string
+	BotID     uuid.UUID
+	CreatedAt time.Time
+}
+
+// IsBotToken reports whether a token is a bot token rather than an access token.
+func IsBotToken(token string) bool {
+	return strings.HasPrefix(token, BotTokenPrefix)
+}
+
+// HashBotToken returns the hash under which a bot token is stored. Bot tokens
+// are random, so a plain SHA-256 is enough to keep them secret at rest.
+func HashBotToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// BotEmail returns the placeholder email of a bot.
+func BotEmail(botID uuid.UUID) string {
+	return fmt.Sprintf("%s@%s", botID, BotEmailDomain)
+}
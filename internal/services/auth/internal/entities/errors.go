@@ -12,4 +12,7 @@ var (
 	ErrPasswordValidation  = errors.New("password validation failed")
 	ErrInvalidEmailFormat  = errors.New("invalid email format")
 	ErrPermissionDenied    = errors.New("permission denied")
+	ErrBotNotFound         = errors.New("bot not found")
+	ErrInvalidBot          = errors.New("invalid bot")
+	ErrBotLimitReached     = errors.New("bot limit reached")
 )
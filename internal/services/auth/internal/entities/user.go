@@ -18,10 +18,24 @@ type User struct {
 	Age         int
 	Bio         string
 	Permissions []Permission
+	Type        UserType
+	OwnerID     *uuid.UUID // User who created the bot; nil for regular users.
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+type UserType string
+
+const (
+	UserTypeRegular UserType = "user"
+	UserTypeBot     UserType = "bot"
+)
+
+// IsBot reports whether the user is a bot account.
+func (u *User) IsBot() bool {
+	return u.Type == UserTypeBot
+}
+
 type Permission string
 
 const (
@@ -14,7 +14,7 @@ type AuthService interface {
 	Login(ctx context.Context, email, password string) (*TokenPair, error)
 	// Refresh validates refresh token and returns new token pair.
 	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
-	// Validate validates access token and returns user info.
+	// Validate validates an access token or a bot token and returns user info.
 	Validate(ctx context.Context, accessToken string) (*entities.User, error)
 	// Revoke invalidates all user's tokens.
 	Revoke(ctx context.Context, userID uuid.UUID) error
@@ -42,6 +42,11 @@ func (s *service) Login(ctx context.Context, email, password string) (*TokenPair
 		return nil, errors.Wrap(err, "failed to get user")
 	}
 
+	// Bots authenticate with their bot token only.
+	if user.IsBot() {
+		return nil, entities.ErrInvalidCredentials
+	}
+
 	if err := comparePasswords(user.Password, password); err != nil {
 		return nil, entities.ErrInvalidCredentials
 	}
@@ -82,6 +87,10 @@ func (s *service) Refresh(ctx context.Context, refreshToken string) (*TokenPair,
 }
 
 func (s *service) Validate(ctx context.Context, accessToken string) (*entities.User, error) {
+	if entities.IsBotToken(accessToken) {
+		return s.validateBotToken(ctx, accessToken)
+	}
+
 	claims, err := s.tokenManager.ValidateAccessToken(accessToken)
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid access token")
@@ -95,6 +104,21 @@ func (s *service) Validate(ctx context.Context, accessToken string) (*entities.U
 	return user, nil
 }
 
+// validateBotToken looks up the bot a bot token belongs to.
+func (s *service) validateBotToken(ctx context.Context, token string) (*entities.User, error) {
+	botToken, err := s.tokenStorage.GetBotToken(ctx, entities.HashBotToken(token))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid bot token")
+	}
+
+	bot, err := s.userStorage.GetUserByID(ctx, botToken.BotID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get bot")
+	}
+
+	return bot, nil
+}
+
 func (s *service) Revoke(ctx context.Context, userID uuid.UUID) error {
 	return s.tokenStorage.DeleteTokensByUserID(ctx, userID)
 }
@@ -18,6 +18,7 @@ type TokenStorage interface {
 	GetToken(ctx context.Context, tokenString string) (*entities.Token, error)
 	DeleteToken(ctx context.Context, tokenString string) error
 	DeleteTokensByUserID(ctx context.Context, userID uuid.UUID) error
+	GetBotToken(ctx context.Context, hash string) (*entities.BotToken, error)
 }
 
 type Deps struct {
@@ -17,6 +17,14 @@ type Token struct {
 	UpdatedAt time.Time        `gorm:"autoUpdateTime;column:updated_at"`
 }
 
+// BotToken is the hashed credential of a bot; a bot has at most one.
+type BotToken struct {
+	Hash      string           `gorm:"primaryKey;column:hash;type:char(64)"`
+	BotID     uuid.UUID        `gorm:"column:bot_id;type:uuid;not null;uniqueIndex"`
+	Bot       userstorage.User `gorm:"foreignKey:BotID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	CreatedAt time.Time        `gorm:"column:created_at"`
+}
+
 func dtoToEntity(dto *Token) *entities.Token {
 	return &entities.Token{
 		Token:     dto.Token,
@@ -10,5 +10,8 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&storage.Token{}); err != nil {
 		return errors.Wrap(err, "failed to migrate TokenDTO")
 	}
+	if err := db.AutoMigrate(&storage.BotToken{}); err != nil {
+		return errors.Wrap(err, "failed to migrate BotTokenDTO")
+	}
 	return nil
 }
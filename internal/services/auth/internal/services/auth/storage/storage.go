@@ -14,6 +14,8 @@ type Storage interface {
 	GetToken(ctx context.Context, tokenString string) (*entities.Token, error)
 	DeleteToken(ctx context.Context, tokenString string) error
 	DeleteTokensByUserID(ctx context.Context, userID uuid.UUID) error
+	SetBotToken(ctx context.Context, token *entities.BotToken) error
+	GetBotToken(ctx context.Context, hash string) (*entities.BotToken, error)
 }
 
 type storage struct {
@@ -66,3 +68,37 @@ func (s *storage) DeleteTokensByUserID(ctx context.Context, userID uuid.UUID) er
 	}
 	return nil
 }
+
+// SetBotToken stores the token of a bot, replacing its previous token.
+func (s *storage) SetBotToken(ctx context.Context, token *entities.BotToken) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bot_id = ?", token.BotID).Delete(&BotToken{}).Error; err != nil {
+			return errors.Wrap(err, "failed to delete previous bot token")
+		}
+
+		dto := BotToken{
+			Hash:      token.Hash,
+			BotID:     token.BotID,
+			CreatedAt: token.CreatedAt,
+		}
+		if err := tx.Create(&dto).Error; err != nil {
+			return errors.Wrap(err, "failed to create bot token")
+		}
+		return nil
+	})
+}
+
+func (s *storage) GetBotToken(ctx context.Context, hash string) (*entities.BotToken, error) {
+	var dto BotToken
+	if err := s.db.WithContext(ctx).First(&dto, "hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrTokenNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get bot token")
+	}
+	return &entities.BotToken{
+		Hash:      dto.Hash,
+		BotID:     dto.BotID,
+		CreatedAt: dto.CreatedAt,
+	}, nil
+}
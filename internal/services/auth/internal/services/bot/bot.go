@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Service manages bot accounts: users owned by a regular user that
+// authenticate with a long-lived bot token instead of a password.
+type Service interface {
+	// CreateBot creates a bot owned by the user and returns its token.
+	CreateBot(ctx context.Context, ownerID uuid.UUID, bot *entities.User) (string, error)
+	// GetBots returns the bots created by the user.
+	GetBots(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error)
+	// RotateBotToken replaces the token of a bot and returns the new one.
+	RotateBotToken(ctx context.Context, requesterID, botID uuid.UUID) (*entities.User, string, error)
+	// DeleteBot deletes a bot together with its token.
+	DeleteBot(ctx context.Context, requesterID, botID uuid.UUID) error
+}
+
+type service struct {
+	userStorage  UserStorage
+	tokenStorage TokenStorage
+}
+
+func NewService(deps Deps) Service {
+	return &service{
+		userStorage:  deps.UserStorage,
+		tokenStorage: deps.TokenStorage,
+	}
+}
+
+func (s *service) CreateBot(ctx context.Context, ownerID uuid.UUID, bot *entities.User) (string, error) {
+	owner, err := s.userStorage.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get owner")
+	}
+	if owner.IsBot() {
+		return "", errors.Wrap(entities.ErrPermissionDenied, "bots cannot create bots")
+	}
+
+	if name := utf8.RuneCountInString(bot.Username); name < 1 || name > entities.MaxBotNameLength {
+		return "", errors.Wrapf(entities.ErrInvalidBot, "username must be between 1 and %d characters", entities.MaxBotNameLength)
+	}
+
+	bots, err := s.userStorage.GetBotsByOwner(ctx, ownerID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to count bots")
+	}
+	if len(bots) >= entities.MaxBotsPerOwner {
+		return "", entities.ErrBotLimitReached
+	}
+
+	now := time.Now()
+	bot.ID = uuid.New()
+	bot.Email = entities.BotEmail(bot.ID)
+	bot.Password = ""
+	bot.Type = entities.UserTypeBot
+	bot.OwnerID = &ownerID
+	bot.Permissions = []entities.Permission{entities.PermissionRead, entities.PermissionWrite}
+	bot.CreatedAt = now
+	bot.UpdatedAt = now
+
+	if err := s.userStorage.CreateUser(ctx, bot); err != nil {
+		return "", errors.Wrap(err, "failed to create bot")
+	}
+
+	return s.issueToken(ctx, bot.ID)
+}
+
+func (s *service) GetBots(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error) {
+	bots, err := s.userStorage.GetBotsByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get bots")
+	}
+	return bots, nil
+}
+
+func (s *service) RotateBotToken(ctx context.Context, requesterID, botID uuid.UUID) (*entities.User, string, error) {
+	bot, err := s.getManagedBot(ctx, requesterID, botID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.issueToken(ctx, bot.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return bot, token, nil
+}
+
+func (s *service) DeleteBot(ctx context.Context, requesterID, botID uuid.UUID) error {
+	if _, err := s.getManagedBot(ctx, requesterID, botID); err != nil {
+		return err
+	}
+
+	if err := s.userStorage.DeleteUser(ctx, botID); err != nil {
+		return errors.Wrap(err, "failed to delete bot")
+	}
+	return nil
+}
+
+// getManagedBot returns a bot the requester may manage: its owner or an admin.
+func (s *service) getManagedBot(ctx context.Context, requesterID, botID uuid.UUID) (*entities.User, error) {
+	bot, err := s.userStorage.GetUserByID(ctx, botID)
+	if err != nil {
+		if errors.Is(err, entities.ErrUserNotFound) {
+			return nil, entities.ErrBotNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get bot")
+	}
+	if !bot.IsBot() {
+		return nil, entities.ErrBotNotFound
+	}
+
+	if bot.OwnerID != nil && *bot.OwnerID == requesterID {
+		return bot, nil
+	}
+
+	requester, err := s.userStorage.GetUserByID(ctx, requesterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get requester")
+	}
+	for _, perm := range requester.Permissions {
+		if perm == entities.PermissionAdmin {
+			return bot, nil
+		}
+	}
+
+	return nil, errors.Wrapf(entities.ErrPermissionDenied, "bot %s belongs to another user", botID)
+}
+
+// issueToken generates a token for a bot and stores its hash, replacing the
+// previous token of the bot.
+func (s *service) issueToken(ctx context.Context, botID uuid.UUID) (string, error) {
+	token, err := generateBotToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.tokenStorage.SetBotToken(ctx, &entities.BotToken{
+		Hash:      entities.HashBotToken(token),
+		BotID:     botID,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return "", errors.Wrap(err, "failed to store bot token")
+	}
+
+	return token, nil
+}
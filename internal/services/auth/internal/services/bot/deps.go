@@ -0,0 +1,24 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+)
+
+type UserStorage interface {
+	CreateUser(ctx context.Context, user *entities.User) error
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*entities.User, error)
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	GetBotsByOwner(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error)
+}
+
+type TokenStorage interface {
+	SetBotToken(ctx context.Context, token *entities.BotToken) error
+}
+
+type Deps struct {
+	UserStorage  UserStorage
+	TokenStorage TokenStorage
+}
@@ -0,0 +1,18 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// generateBotToken returns a new random bot token.
+func generateBotToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "failed to generate bot token")
+	}
+	return entities.BotTokenPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
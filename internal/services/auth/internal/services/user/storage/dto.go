@@ -15,6 +15,8 @@ type User struct {
 	Age         int           `gorm:"column:age"`
 	Bio         string        `gorm:"column:bio"`
 	Permissions []*Permission `gorm:"many2many:user_permissions;"`
+	Type        string        `gorm:"column:type;type:varchar(16);not null;default:user"`
+	OwnerID     *uuid.UUID    `gorm:"column:owner_id;type:uuid;index"`
 	CreatedAt   time.Time     `gorm:"autoCreateTime;column:created_at"`
 	UpdatedAt   time.Time     `gorm:"autoUpdateTime;column:updated_at"`
 }
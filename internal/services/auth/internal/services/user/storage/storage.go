@@ -16,6 +16,7 @@ type Storage interface {
 	UpdateUser(ctx context.Context, user *entities.User) error
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
 	GetUsers(ctx context.Context, limit, offset int) ([]*entities.User, error)
+	GetBotsByOwner(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error)
 }
 
 type storage struct {
@@ -36,6 +37,11 @@ func (s *storage) CreateUser(ctx context.Context, user *entities.User) error {
 		permissions = append(permissions, permDTO)
 	}
 
+	userType := user.Type
+	if userType == "" {
+		userType = entities.UserTypeRegular
+	}
+
 	dto := User{
 		ID:          user.ID,
 		Email:       user.Email,
@@ -45,6 +51,8 @@ func (s *storage) CreateUser(ctx context.Context, user *entities.User) error {
 		Age:         user.Age,
 		Bio:         user.Bio,
 		Permissions: permissions,
+		Type:        string(userType),
+		OwnerID:     user.OwnerID,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
 	}
@@ -119,11 +127,34 @@ func (s *storage) UpdateUser(ctx context.Context, user *entities.User) error {
 	return nil
 }
 
+// DeleteUser deletes a user together with the bots the user created.
 func (s *storage) DeleteUser(ctx context.Context, userID uuid.UUID) error {
-	if err := s.db.WithContext(ctx).Delete(&User{}, "id = ?", userID).Error; err != nil {
-		return errors.Wrap(err, "failed to delete user")
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&User{}, "owner_id = ?", userID).Error; err != nil {
+			return errors.Wrap(err, "failed to delete bots of user")
+		}
+		if err := tx.Delete(&User{}, "id = ?", userID).Error; err != nil {
+			return errors.Wrap(err, "failed to delete user")
+		}
+		return nil
+	})
+}
+
+func (s *storage) GetBotsByOwner(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error) {
+	var dtos []User
+	if err := s.db.WithContext(ctx).
+		Preload("Permissions").
+		Where("type = ? AND owner_id = ?", string(entities.UserTypeBot), ownerID).
+		Order("created_at").
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get bots")
 	}
-	return nil
+
+	bots := make([]*entities.User, len(dtos))
+	for i := range dtos {
+		bots[i] = dtoToEntity(&dtos[i])
+	}
+	return bots, nil
 }
 
 func dtoToEntity(dto *User) *entities.User {
@@ -140,6 +171,8 @@ func dtoToEntity(dto *User) *entities.User {
 		Age:         dto.Age,
 		Bio:         dto.Bio,
 		Permissions: permissions,
+		Type:        entities.UserType(dto.Type),
+		OwnerID:     dto.OwnerID,
 		CreatedAt:   dto.CreatedAt,
 		UpdatedAt:   dto.UpdatedAt,
 	}
@@ -147,7 +180,9 @@ func dtoToEntity(dto *User) *entities.User {
 
 func (s *storage) GetUsers(ctx context.Context, limit, offset int) ([]*entities.User, error) {
 	var users []*entities.User
+	// A stable order keeps pages from overlapping or skipping users.
 	err := s.db.WithContext(ctx).
+		Order("created_at, id").
 		Limit(limit).
 		Offset(offset).
 		Find(&users).Error
@@ -0,0 +1,16 @@
+package createbot
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+)
+
+type BotService interface {
+	CreateBot(ctx context.Context, ownerID uuid.UUID, bot *entities.User) (string, error)
+}
+
+type Deps struct {
+	BotService BotService
+}
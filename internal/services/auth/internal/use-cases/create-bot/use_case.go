@@ -0,0 +1,30 @@
+package createbot
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	botService BotService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		botService: deps.BotService,
+	}
+}
+
+// Execute creates a bot owned by the user and returns it with its token. The
+// token is not stored in clear and cannot be retrieved later.
+func (uc *UseCase) Execute(ctx context.Context, ownerID uuid.UUID, bot *entities.User) (*entities.User, string, error) {
+	token, err := uc.botService.CreateBot(ctx, ownerID, bot)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create bot")
+	}
+
+	return bot, token, nil
+}
@@ -0,0 +1,15 @@
+package deletebot
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type BotService interface {
+	DeleteBot(ctx context.Context, requesterID, botID uuid.UUID) error
+}
+
+type Deps struct {
+	BotService BotService
+}
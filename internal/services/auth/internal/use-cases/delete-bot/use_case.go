@@ -0,0 +1,26 @@
+package deletebot
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	botService BotService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		botService: deps.BotService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, requesterID, botID uuid.UUID) error {
+	if err := uc.botService.DeleteBot(ctx, requesterID, botID); err != nil {
+		return errors.Wrap(err, "failed to delete bot")
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package getbots
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+)
+
+type BotService interface {
+	GetBots(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error)
+}
+
+type Deps struct {
+	BotService BotService
+}
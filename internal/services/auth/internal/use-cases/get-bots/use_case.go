@@ -0,0 +1,28 @@
+package getbots
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	botService BotService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		botService: deps.BotService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, ownerID uuid.UUID) ([]*entities.User, error) {
+	bots, err := uc.botService.GetBots(ctx, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get bots")
+	}
+
+	return bots, nil
+}
@@ -0,0 +1,16 @@
+package rotatebottoken
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+)
+
+type BotService interface {
+	RotateBotToken(ctx context.Context, requesterID, botID uuid.UUID) (*entities.User, string, error)
+}
+
+type Deps struct {
+	BotService BotService
+}
@@ -0,0 +1,29 @@
+package rotatebottoken
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/auth/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	botService BotService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		botService: deps.BotService,
+	}
+}
+
+// Execute issues a new token for the bot; its previous token stops working.
+func (uc *UseCase) Execute(ctx context.Context, requesterID, botID uuid.UUID) (*entities.User, string, error) {
+	bot, token, err := uc.botService.RotateBotToken(ctx, requesterID, botID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to rotate bot token")
+	}
+
+	return bot, token, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/website"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/config"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/export"
+	chatstorage "github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/storage"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// export writes the history of a room to a file or to stdout. It reads the
+// storage database directly and skips the participant check of the HTTP
+// endpoint, so it is meant for operators archiving rooms.
+func main() {
+	configPath := flag.String("config", "../../configs/config.prod.yaml", "Path to the configuration file")
+	roomFlag := flag.String("room", "", "ID of the room to export")
+	formatFlag := flag.String("format", "jsonl", "Export format: jsonl, html or text")
+	outPath := flag.String("out", "", "File to write the export to; stdout when empty")
+	flag.Parse()
+
+	roomID, err := uuid.Parse(*roomFlag)
+	if err != nil {
+		log.Fatalf("Invalid room ID %q: %v", *roomFlag, err)
+	}
+	format, err := entities.ParseExportFormat(*formatFlag)
+	if err != nil {
+		log.Fatalf("Invalid format %q: use jsonl, html or text", *formatFlag)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Logs go to stderr, so that the export can be written to stdout.
+	level, err := zapcore.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		log.Fatalf("Invalid log level: %v", err)
+	}
+	logConfig := zap.NewProductionConfig()
+	logConfig.Level = zap.NewAtomicLevelAt(level)
+	logConfig.Encoding = "console"
+	logConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	logConfig.OutputPaths = []string{"stderr"}
+
+	logger, err := logConfig.Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	db, err := gorm.Open(postgres.Open(cfg.Engines.Storage.URL), &gorm.Config{})
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	authClient, err := auth.NewClient(logger, cfg.AuthService.Address, cfg.AuthService.ServiceToken)
+	if err != nil {
+		logger.Fatal("Failed to create auth client", zap.Error(err))
+	}
+	defer authClient.Close()
+
+	websiteClient, err := website.NewClient(logger, cfg.WebsiteService.Address, cfg.WebsiteService.ServiceToken)
+	if err != nil {
+		logger.Fatal("Failed to create website client", zap.Error(err))
+	}
+	defer websiteClient.Close()
+
+	exporter := export.New(export.Deps{
+		Storage: chatstorage.NewStorage(db),
+		Users:   authClient,
+		Rooms:   websiteClient,
+	}, logger)
+
+	out := os.Stdout
+	if *outPath != "" {
+		out, err = os.Create(*outPath)
+		if err != nil {
+			logger.Fatal("Failed to create output file", zap.Error(err))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exportErr := exporter.Export(ctx, roomID, format, out)
+	if err := out.Close(); err != nil && exportErr == nil {
+		exportErr = err
+	}
+	if exportErr != nil {
+		logger.Fatal("Failed to export room", zap.Error(exportErr), zap.String("room_id", roomID.String()))
+	}
+}
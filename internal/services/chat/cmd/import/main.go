@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/HexArch/go-chat/internal/pkg/logger"
+	"github.com/HexArch/go-chat/internal/pkg/slackexport"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/config"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/slackimport"
+	chatstorage "github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/storage"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// import stores the messages of a Slack export in the rooms created by the
+// Website Service import, whose mapping it reads. It can be run again on the
+// same export: messages already imported are skipped.
+func main() {
+	configPath := flag.String("config", "../../configs/config.prod.yaml", "Path to the configuration file")
+	archivePath := flag.String("archive", "", "Slack export zip to import")
+	mappingPath := flag.String("mapping", "slack-mapping.json", "Mapping written by the Website Service import")
+	batchSize := flag.Int("batch-size", 500, "Messages stored per insert")
+	flag.Parse()
+
+	if *archivePath == "" {
+		log.Fatal("Missing -archive")
+	}
+	if *batchSize <= 0 {
+		log.Fatal("-batch-size must be positive")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger, err := logger.NewLogger(cfg.Logging.Level)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	mapping, err := slackexport.LoadMapping(*mappingPath)
+	if err != nil {
+		logger.Fatal("Failed to load mapping", zap.Error(err))
+	}
+	if len(mapping.Rooms) == 0 {
+		logger.Fatal("Mapping has no rooms; run the Website Service import first", zap.String("mapping", *mappingPath))
+	}
+
+	archive, err := slackexport.Open(*archivePath)
+	if err != nil {
+		logger.Fatal("Failed to open Slack export", zap.Error(err))
+	}
+	defer archive.Close()
+
+	db, err := gorm.Open(postgres.Open(cfg.Engines.Storage.URL), &gorm.Config{})
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	importer := slackimport.New(slackimport.Deps{
+		Storage: chatstorage.NewStorage(db),
+	}, slackimport.Config{
+		BatchSize: *batchSize,
+	}, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := importer.Import(ctx, archive, mapping)
+	if err != nil {
+		logger.Fatal("Failed to import Slack export", zap.Error(err))
+	}
+
+	logger.Info("Slack export imported",
+		zap.Int("rooms", result.Rooms),
+		zap.Int("messages", result.Messages),
+		zap.Int("existing", result.Existing),
+		zap.Int("skipped", result.Skipped),
+	)
+}
@@ -0,0 +1,44 @@
+// Command script-worker runs room script hooks for the chat service. It reads
+// one JSON request per line from stdin and writes one JSON result per line to
+// stdout. The process limits its own memory before running anything, so a
+// script that allocates too much kills the worker instead of the chat service.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/HexArch/go-chat/internal/pkg/roomscript"
+)
+
+func main() {
+	memoryLimit := flag.Int64("memory-limit", 128<<20, "Bytes of memory the worker may use")
+	flag.Parse()
+
+	// RLIMIT_DATA caps the heap the Go runtime can map; going over it is fatal.
+	// The soft limit below it makes the GC work harder before that happens.
+	limit := uint64(*memoryLimit)
+	if err := syscall.Setrlimit(syscall.RLIMIT_DATA, &syscall.Rlimit{Cur: limit, Max: limit}); err != nil {
+		log.Fatalf("Failed to set memory limit: %v", err)
+	}
+	debug.SetMemoryLimit(*memoryLimit / 4 * 3)
+
+	requests := json.NewDecoder(bufio.NewReader(os.Stdin))
+	results := json.NewEncoder(os.Stdout)
+
+	for {
+		var req roomscript.Request
+		if err := requests.Decode(&req); err != nil {
+			return
+		}
+
+		if err := results.Encode(roomscript.Run(&req)); err != nil {
+			return
+		}
+	}
+}
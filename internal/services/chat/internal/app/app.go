@@ -2,18 +2,48 @@ package app
 
 import (
 	"context"
+	"database/sql"
 
 	graceful "github.com/HexArch/go-chat/internal/pkg/graceful-shutdown"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/website"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/config"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/controllers"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/metrics"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/attachments"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/backplane"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/blobstore"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/commands"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/export"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/ratelimit"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/retention"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/sandbox"
 	chatstorage "github.com/HexArch/go-chat/internal/services/chat/internal/services/chat/storage"
+	addreactionuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/add-reaction"
 	connectuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/connect"
+	deletemessageuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/delete-message"
 	disconnectuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/disconnect"
+	downloadattachmentuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/download-attachment"
+	editmessageuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/edit-message"
+	exportroomuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/export-room"
+	getcommands "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-commands"
 	getmessages "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-messages"
+	getpresenceuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-presence"
+	getroomparticipants "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-room-participants"
+	getthread "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-thread"
+	getunreadcounts "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-unread-counts"
+	markreaduc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/mark-read"
+	postincomingwebhookuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/post-incoming-webhook"
+	removereactionuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/remove-reaction"
+	runcommanduc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/run-command"
+	searchmessagesuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/search-messages"
 	sendmessageuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/send-message"
+	setpresenceuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/set-presence"
+	setslowmodeuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/set-slow-mode"
+	subscribepresenceuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/subscribe-presence"
+	typinguc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/typing"
+	uploadattachmentuc "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/upload-attachment"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
@@ -21,10 +51,20 @@ import (
 )
 
 type App struct {
-	cfg        *config.Config
-	logger     *zap.Logger
-	grShutdown *graceful.Shutdown
-	server     *controllers.Server
+	cfg         *config.Config
+	logger      *zap.Logger
+	grShutdown  *graceful.Shutdown
+	server      *controllers.Server
+	chat        *chat.Service
+	backplane   closableBackplane
+	sandbox     *sandbox.Runner
+	retention   *retention.Purger
+	attachments *attachments.Service
+}
+
+type closableBackplane interface {
+	chat.Backplane
+	Close() error
 }
 
 func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App, error) {
@@ -52,9 +92,67 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 		return nil, errors.Wrap(err, "failed to create website client")
 	}
 
+	chatBackplane, err := newBackplane(cfg.Engines, sqlDB, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create backplane")
+	}
+
+	// Room scripts only run with a sandbox to run them in.
+	var (
+		scriptSandbox *sandbox.Runner
+		scripts       chat.ScriptRunner
+	)
+	if cfg.Scripting.Enabled {
+		scriptSandbox = sandbox.New(sandbox.Config{
+			WorkerPath:  cfg.Scripting.WorkerPath,
+			Workers:     cfg.Scripting.Workers,
+			MemoryLimit: cfg.Scripting.MemoryLimitMB << 20,
+			MaxSteps:    cfg.Scripting.MaxSteps,
+			Timeout:     cfg.Scripting.Timeout,
+		}, logger.Named("sandbox"))
+		scripts = scriptSandbox
+	}
+
+	blobs, err := newBlobStore(cfg.Engines.Blobs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create blob store")
+	}
+
 	messageStorage := chatstorage.NewStorage(db)
-	chatService := chat.NewService(chat.Deps{
+	attachmentService := attachments.New(attachments.Deps{
 		Storage: messageStorage,
+		Blobs:   blobs,
+	}, attachments.Config{
+		MaxSize:       cfg.Attachments.MaxSizeMB << 20,
+		ThumbnailSize: cfg.Attachments.ThumbnailSize,
+		URLSecret:     cfg.Attachments.URLSecret,
+		URLTTL:        cfg.Attachments.URLTTL,
+		OrphanTTL:     cfg.Attachments.OrphanTTL,
+		SweepInterval: cfg.Attachments.SweepInterval,
+	}, logger.Named("attachments"))
+
+	// Expired messages are only purged by instances with the job enabled.
+	var purger *retention.Purger
+	if cfg.Retention.Enabled {
+		purger = retention.New(retention.Deps{
+			Policies:    websiteClient,
+			Storage:     messageStorage,
+			Attachments: attachmentService,
+			Metrics:     metrics.NewChatMetrics("chat_service"),
+		}, retention.Config{
+			Interval:   cfg.Retention.Interval,
+			BatchSize:  cfg.Retention.BatchSize,
+			BatchPause: cfg.Retention.BatchPause,
+			Archive:    cfg.Retention.Archive,
+		}, logger.Named("retention"))
+	}
+
+	chatService := chat.NewService(chat.Deps{
+		Storage:        messageStorage,
+		WebsiteService: websiteClient,
+		Backplane:      chatBackplane,
+		Scripts:        scripts,
+		Attachments:    attachmentService,
 	}, logger)
 
 	connectUC := connectuc.New(connectuc.Deps{
@@ -66,21 +164,202 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 		ChatService: chatService,
 	})
 
+	commandRegistry := commands.NewRegistry(websiteClient)
+	if err := commands.RegisterBuiltins(commandRegistry, chatService); err != nil {
+		return nil, errors.Wrap(err, "failed to register commands")
+	}
+
+	// Commands count against the same limits as messages.
+	messageLimiter := ratelimit.New(ratelimit.Config{
+		User: ratelimit.Rate{PerSecond: cfg.RateLimit.User.Rate, Burst: cfg.RateLimit.User.Burst},
+		Room: ratelimit.Rate{PerSecond: cfg.RateLimit.Room.Rate, Burst: cfg.RateLimit.Room.Burst},
+		IP:   ratelimit.Rate{PerSecond: cfg.RateLimit.IP.Rate, Burst: cfg.RateLimit.IP.Burst},
+	})
+
 	sendMessageUC := sendmessageuc.New(sendmessageuc.Deps{
 		ChatService: chatService,
+		RateLimiter: messageLimiter,
+	})
+
+	postIncomingWebhookUC := postincomingwebhookuc.New(postincomingwebhookuc.Deps{
+		Integrations: websiteClient,
+		ChatService:  chatService,
+		RateLimiter:  messageLimiter,
+	})
+
+	uploadAttachmentUC := uploadattachmentuc.New(uploadattachmentuc.Deps{
+		Attachments: attachmentService,
+		RateLimiter: messageLimiter,
+	})
+
+	downloadAttachmentUC := downloadattachmentuc.New(downloadattachmentuc.Deps{
+		Attachments: attachmentService,
+	})
+
+	exportRoomUC := exportroomuc.New(exportroomuc.Deps{
+		Storage: messageStorage,
+		Exporter: export.New(export.Deps{
+			Storage: messageStorage,
+			Users:   authClient,
+			Rooms:   websiteClient,
+		}, logger.Named("export")),
+	})
+
+	runCommandUC := runcommanduc.New(runcommanduc.Deps{
+		Commands:    commandRegistry,
+		RateLimiter: messageLimiter,
+	})
+
+	getCommandsUC := getcommands.New(getcommands.Deps{
+		Commands: commandRegistry,
 	})
 
 	getMessagesUC := getmessages.New(getmessages.Deps{
 		ChatService: chatService,
 	})
 
+	getThreadUC := getthread.New(getthread.Deps{
+		ChatService: chatService,
+	})
+
+	editMessageUC := editmessageuc.New(editmessageuc.Deps{
+		ChatService: chatService,
+	})
+
+	deleteMessageUC := deletemessageuc.New(deletemessageuc.Deps{
+		ChatService: chatService,
+	})
+
+	addReactionUC := addreactionuc.New(addreactionuc.Deps{
+		ChatService: chatService,
+	})
+
+	removeReactionUC := removereactionuc.New(removereactionuc.Deps{
+		ChatService: chatService,
+	})
+
+	typingUC := typinguc.New(typinguc.Deps{
+		ChatService: chatService,
+	})
+
+	markReadUC := markreaduc.New(markreaduc.Deps{
+		ChatService: chatService,
+	})
+
+	getUnreadUC := getunreadcounts.New(getunreadcounts.Deps{
+		ChatService: chatService,
+	})
+
+	searchMessagesUC := searchmessagesuc.New(searchmessagesuc.Deps{
+		ChatService: chatService,
+	})
+
+	setPresenceUC := setpresenceuc.New(setpresenceuc.Deps{
+		ChatService: chatService,
+	})
+
+	subscribePresenceUC := subscribepresenceuc.New(subscribepresenceuc.Deps{
+		ChatService: chatService,
+	})
+
+	getPresenceUC := getpresenceuc.New(getpresenceuc.Deps{
+		ChatService: chatService,
+	})
+
+	setSlowModeUC := setslowmodeuc.New(setslowmodeuc.Deps{
+		ChatService: chatService,
+	})
+
+	getParticipantsUC := getroomparticipants.New(getroomparticipants.Deps{
+		Storage:        messageStorage,
+		WebsiteService: websiteClient,
+	})
+
 	wsHandler := controllers.NewWebSocketHandler(
 		logger,
+		cfg.WebSocket,
 		connectUC,
 		disconnectUC,
 		sendMessageUC,
 		getMessagesUC,
+		getThreadUC,
+		editMessageUC,
+		deleteMessageUC,
+		addReactionUC,
+		removeReactionUC,
+		typingUC,
+		markReadUC,
+		getUnreadUC,
+		searchMessagesUC,
+		setPresenceUC,
+		subscribePresenceUC,
+		setSlowModeUC,
+		runCommandUC,
+		getCommandsUC,
 		authClient,
+		cfg.RateLimit.TrustProxyHeaders,
+	)
+
+	httpHandler := controllers.NewHTTPHandler(
+		logger,
+		getUnreadUC,
+		searchMessagesUC,
+		getPresenceUC,
+		getCommandsUC,
+		authClient,
+	)
+
+	fallbackHandler := controllers.NewFallbackHandler(
+		logger,
+		wsHandler,
+		connectUC,
+		disconnectUC,
+		authClient,
+	)
+
+	hooksHandler := controllers.NewIncomingWebhookHandler(
+		logger,
+		postIncomingWebhookUC,
+		cfg.WebSocket.MaxMessageSize,
+		cfg.RateLimit.TrustProxyHeaders,
+	)
+
+	attachmentHandler := controllers.NewAttachmentHandler(
+		logger,
+		uploadAttachmentUC,
+		downloadAttachmentUC,
+		authClient,
+		cfg.Attachments.MaxSizeMB<<20,
+		cfg.RateLimit.TrustProxyHeaders,
+	)
+
+	exportHandler := controllers.NewExportHandler(
+		logger,
+		exportRoomUC,
+		authClient,
+	)
+
+	chatServer := controllers.NewChatServiceServer(
+		logger,
+		connectUC,
+		disconnectUC,
+		sendMessageUC,
+		getMessagesUC,
+		getParticipantsUC,
+		editMessageUC,
+		deleteMessageUC,
+		addReactionUC,
+		removeReactionUC,
+		typingUC,
+		markReadUC,
+		setPresenceUC,
+		subscribePresenceUC,
+		getPresenceUC,
+		setSlowModeUC,
+		runCommandUC,
+		getCommandsUC,
+		authClient,
+		cfg.RateLimit.TrustProxyHeaders,
 	)
 
 	grShutdown := graceful.NewShutdown(logger)
@@ -89,17 +368,69 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 		logger,
 		cfg,
 		wsHandler,
+		httpHandler,
+		fallbackHandler,
+		hooksHandler,
+		attachmentHandler,
+		exportHandler,
+		chatServer,
 	)
 
 	return &App{
-		cfg:        cfg,
-		logger:     logger,
-		grShutdown: grShutdown,
-		server:     server,
+		cfg:         cfg,
+		logger:      logger,
+		grShutdown:  grShutdown,
+		server:      server,
+		chat:        chatService,
+		backplane:   chatBackplane,
+		sandbox:     scriptSandbox,
+		retention:   purger,
+		attachments: attachmentService,
 	}, nil
 }
 
+func newBackplane(cfg config.EnginesConfig, db *sql.DB, logger *zap.Logger) (closableBackplane, error) {
+	switch cfg.Backplane.Driver {
+	case "memory":
+		return backplane.NewMemory(), nil
+	case "postgres":
+		return backplane.NewPostgres(db, backplane.PostgresConfig{
+			DSN:                  cfg.Storage.URL,
+			Channel:              cfg.Backplane.Channel,
+			MinReconnectInterval: cfg.Backplane.MinReconnectInterval,
+			MaxReconnectInterval: cfg.Backplane.MaxReconnectInterval,
+		}, logger)
+	default:
+		return nil, errors.Errorf("unknown backplane driver %q", cfg.Backplane.Driver)
+	}
+}
+
+func newBlobStore(cfg config.BlobsConfig) (attachments.BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return blobstore.NewLocal(cfg.Dir)
+	case "s3":
+		return blobstore.NewS3(blobstore.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			Timeout:         cfg.S3.Timeout,
+		})
+	default:
+		return nil, errors.Errorf("unknown blob store driver %q", cfg.Driver)
+	}
+}
+
 func (a *App) Start(ctx context.Context) {
+	if a.retention != nil {
+		a.retention.Start()
+	}
+	if a.cfg.Attachments.SweepInterval > 0 {
+		a.attachments.Start()
+	}
+
 	go func() {
 		if err := a.server.Start(ctx); err != nil {
 			a.logger.Fatal("Failed to start server", zap.Error(err))
@@ -114,5 +445,21 @@ func (a *App) Start(ctx context.Context) {
 }
 
 func (a *App) Stop(ctx context.Context) error {
-	return a.server.Stop(ctx)
+	if a.retention != nil {
+		a.retention.Stop()
+	}
+	a.attachments.Stop()
+
+	if err := a.server.Stop(ctx); err != nil {
+		return err
+	}
+
+	// Marks the users of this instance offline before the backplane goes away.
+	a.chat.Cleanup()
+
+	if a.sandbox != nil {
+		a.sandbox.Close()
+	}
+
+	return a.backplane.Close()
 }
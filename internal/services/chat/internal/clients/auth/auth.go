@@ -10,8 +10,10 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type ValidateResponse struct {
@@ -104,3 +106,29 @@ func (c *Client) CheckUserExists(ctx context.Context, userID uuid.UUID) (bool, e
 
 	return true, nil
 }
+
+// GetUsername returns the username of a user using service token. The second
+// result is false when no user has the ID, e.g. for IDs of scripts and bots.
+func (c *Client) GetUsername(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + c.serviceToken,
+	}))
+
+	user, err := c.client.GetUser(ctx, &auth.GetUserRequest{
+		UserId: userID.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", false, nil
+		}
+		return "", false, errors.Wrap(err, "failed to get user")
+	}
+
+	return user.Username, true, nil
+}
@@ -5,12 +5,16 @@ import (
 	"time"
 
 	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/website"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Client provides access to the website service.
@@ -49,18 +53,19 @@ func (c *Client) Close() error {
 }
 
 // createServiceContext creates a context with service token.
-func (c *Client) createServiceContext(ctx context.Context) context.Context {
+// The returned cancel function must be called once the request is done.
+func (c *Client) createServiceContext(ctx context.Context) (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
 
 	return metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
 		"authorization": "Bearer " + c.serviceToken,
-	}))
+	})), cancel
 }
 
 // RoomExists checks if a room exists.
 func (c *Client) RoomExists(ctx context.Context, roomID uuid.UUID) (bool, error) {
-	ctx = c.createServiceContext(ctx)
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
 
 	_, err := c.client.GetRoom(ctx, &website.GetRoomRequest{
 		RoomId: roomID.String(),
@@ -71,3 +76,199 @@ func (c *Client) RoomExists(ctx context.Context, roomID uuid.UUID) (bool, error)
 
 	return true, nil
 }
+
+// GetRoomOwner returns the ID of the user who owns a room.
+func (c *Client) GetRoomOwner(ctx context.Context, roomID uuid.UUID) (uuid.UUID, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	room, err := c.client.GetRoom(ctx, &website.GetRoomRequest{
+		RoomId: roomID.String(),
+	})
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "failed to get room")
+	}
+
+	ownerID, err := uuid.Parse(room.OwnerId)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "invalid owner ID format")
+	}
+
+	return ownerID, nil
+}
+
+// GetRoomName returns the name of a room.
+func (c *Client) GetRoomName(ctx context.Context, roomID uuid.UUID) (string, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	room, err := c.client.GetRoom(ctx, &website.GetRoomRequest{
+		RoomId: roomID.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", entities.ErrRoomNotFound
+		}
+		return "", errors.Wrap(err, "failed to get room")
+	}
+
+	return room.Name, nil
+}
+
+// CanJoinRoom reports whether the user may connect to a room. Public rooms are
+// open to everyone, direct and group rooms only to their members.
+func (c *Client) CanJoinRoom(ctx context.Context, roomID, userID uuid.UUID) (bool, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	room, err := c.client.GetRoom(ctx, &website.GetRoomRequest{
+		RoomId: roomID.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, entities.ErrRoomNotFound
+		}
+		return false, errors.Wrap(err, "failed to get room")
+	}
+
+	if room.Kind == website.RoomKind_ROOM_KIND_PUBLIC {
+		return true, nil
+	}
+
+	for _, memberID := range room.MemberIds {
+		if memberID == userID.String() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetRoomScripts returns the enabled scripts of a room.
+func (c *Client) GetRoomScripts(ctx context.Context, roomID uuid.UUID) ([]*entities.RoomScript, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.GetEnabledScripts(ctx, &website.GetEnabledScriptsRequest{
+		RoomId: roomID.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room scripts")
+	}
+
+	return scriptsFromProto(resp.Scripts)
+}
+
+// GetScheduledScripts returns the enabled scripts with a schedule of every room.
+func (c *Client) GetScheduledScripts(ctx context.Context) ([]*entities.RoomScript, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.GetEnabledScripts(ctx, &website.GetEnabledScriptsRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get scheduled scripts")
+	}
+
+	return scriptsFromProto(resp.Scripts)
+}
+
+// GetRetentionPolicies returns the retention policies of every room that
+// does not keep its messages forever.
+func (c *Client) GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.GetRetentionPolicies(ctx, &website.GetRetentionPoliciesRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retention policies")
+	}
+
+	policies := make([]*entities.RetentionPolicy, 0, len(resp.Policies))
+	for _, pbPolicy := range resp.Policies {
+		roomID, err := uuid.Parse(pbPolicy.RoomId)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid room ID format")
+		}
+
+		policies = append(policies, &entities.RetentionPolicy{
+			RoomID:   roomID,
+			Days:     int(pbPolicy.Days),
+			Messages: int(pbPolicy.Messages),
+		})
+	}
+	return policies, nil
+}
+
+// PublishRoomEvent queues an event of a room for its outgoing webhooks and
+// reports whether the room has enabled webhooks.
+func (c *Client) PublishRoomEvent(ctx context.Context, event *entities.WebhookEvent) (bool, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.PublishRoomEvent(ctx, &website.PublishRoomEventRequest{
+		RoomId:     event.RoomID.String(),
+		Event:      event.Type,
+		EventId:    event.ID.String(),
+		Data:       string(event.Data),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to publish room event")
+	}
+
+	return resp.Subscribed, nil
+}
+
+// ResolveIncomingWebhook returns the incoming webhook a token belongs to.
+func (c *Client) ResolveIncomingWebhook(ctx context.Context, token string) (*entities.Integration, error) {
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.ResolveIncomingWebhook(ctx, &website.ResolveIncomingWebhookRequest{
+		Token: token,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, entities.ErrIntegrationNotFound
+		}
+		return nil, errors.Wrap(err, "failed to resolve incoming webhook")
+	}
+
+	webhookID, err := uuid.Parse(resp.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid webhook ID format")
+	}
+	roomID, err := uuid.Parse(resp.RoomId)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid room ID format")
+	}
+
+	return &entities.Integration{
+		ID:     webhookID,
+		RoomID: roomID,
+		Name:   resp.Name,
+	}, nil
+}
+
+func scriptsFromProto(pbScripts []*website.RoomScript) ([]*entities.RoomScript, error) {
+	scripts := make([]*entities.RoomScript, 0, len(pbScripts))
+	for _, pbScript := range pbScripts {
+		scriptID, err := uuid.Parse(pbScript.Id)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid script ID format")
+		}
+		roomID, err := uuid.Parse(pbScript.RoomId)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid room ID format")
+		}
+
+		scripts = append(scripts, &entities.RoomScript{
+			ID:               scriptID,
+			RoomID:           roomID,
+			Name:             pbScript.Name,
+			Source:           pbScript.Source,
+			ScheduleInterval: time.Duration(pbScript.ScheduleSeconds) * time.Second,
+		})
+	}
+	return scripts, nil
+}
@@ -26,10 +26,17 @@ type Config struct {
 	Vault            VaultConfig       `koanf:"vault"`
 	GracefulShutdown time.Duration     `koanf:"graceful_shutdown"`
 	WebSocket        WebSocketConfig   `koanf:"websocket"`
+	RateLimit        RateLimitConfig   `koanf:"rate_limit"`
+	Scripting        ScriptingConfig   `koanf:"scripting"`
+	Attachments      AttachmentsConfig `koanf:"attachments"`
+	Retention        RetentionConfig   `koanf:"retention"`
 }
 
 type EnginesConfig struct {
-	Storage StorageConfig `koanf:"storage"`
+	Storage   StorageConfig   `koanf:"storage"`
+	Backplane BackplaneConfig `koanf:"backplane"`
+	Blobs     BlobsConfig     `koanf:"blobs"`
+	Metrics   MetricsConfig   `koanf:"metrics"`
 }
 
 type StorageConfig struct {
@@ -39,6 +46,37 @@ type StorageConfig struct {
 	ConnMaxLifetime time.Duration `koanf:"conn_max_lifetime"`
 }
 
+// BackplaneConfig selects how events are shared between chat instances.
+// Driver is either "postgres" (LISTEN/NOTIFY over the storage database) or "memory" (single instance only).
+type BackplaneConfig struct {
+	Driver               string        `koanf:"driver"`
+	Channel              string        `koanf:"channel"`
+	MinReconnectInterval time.Duration `koanf:"min_reconnect_interval"`
+	MaxReconnectInterval time.Duration `koanf:"max_reconnect_interval"`
+}
+
+// BlobsConfig selects where attachment contents are kept. Driver is either
+// "local" (files under Dir, which instances must share) or "s3" (a bucket of an
+// S3-compatible store).
+type BlobsConfig struct {
+	Driver string   `koanf:"driver"`
+	Dir    string   `koanf:"dir"`
+	S3     S3Config `koanf:"s3"`
+}
+
+type S3Config struct {
+	Endpoint        string        `koanf:"endpoint"`
+	Region          string        `koanf:"region"`
+	Bucket          string        `koanf:"bucket"`
+	AccessKeyID     string        `koanf:"access_key_id"`
+	SecretAccessKey string        `koanf:"secret_access_key"`
+	Timeout         time.Duration `koanf:"timeout"`
+}
+
+type MetricsConfig struct {
+	Address string `koanf:"address"`
+}
+
 type LoggingConfig struct {
 	Level string `koanf:"level"`
 }
@@ -78,6 +116,59 @@ type WebSocketConfig struct {
 	MessageQueueSize int           `koanf:"message_queue_size"`
 }
 
+// RateLimitConfig bounds how fast messages are accepted per user, per room and per
+// client IP. The client IP is only read from the X-Real-IP header when the service
+// runs behind a proxy that sets it.
+type RateLimitConfig struct {
+	User              RateConfig `koanf:"user"`
+	Room              RateConfig `koanf:"room"`
+	IP                RateConfig `koanf:"ip"`
+	TrustProxyHeaders bool       `koanf:"trust_proxy_headers"`
+}
+
+// RateConfig is a token bucket: Rate messages per second with bursts of up to
+// Burst messages. A zero rate disables the limit.
+type RateConfig struct {
+	Rate  float64 `koanf:"rate"`
+	Burst int     `koanf:"burst"`
+}
+
+// ScriptingConfig bounds the room scripts run by the service. Each hook runs in
+// a script-worker process limited to MemoryLimitMB of memory, MaxSteps Starlark
+// steps and Timeout of wall-clock time.
+type ScriptingConfig struct {
+	Enabled       bool          `koanf:"enabled"`
+	WorkerPath    string        `koanf:"worker_path"`
+	Workers       int           `koanf:"workers"`
+	MemoryLimitMB int64         `koanf:"memory_limit_mb"`
+	MaxSteps      uint64        `koanf:"max_steps"`
+	Timeout       time.Duration `koanf:"timeout"`
+}
+
+// AttachmentsConfig bounds uploads and signs their download URLs. Without a
+// URL secret the website service token is used, so that every instance signs
+// alike. Uploads that no message references within OrphanTTL are removed
+// every SweepInterval; a zero SweepInterval keeps them.
+type AttachmentsConfig struct {
+	MaxSizeMB     int64         `koanf:"max_size_mb"`
+	ThumbnailSize int           `koanf:"thumbnail_size"`
+	URLSecret     string        `koanf:"url_secret"`
+	URLTTL        time.Duration `koanf:"url_ttl"`
+	OrphanTTL     time.Duration `koanf:"orphan_ttl"`
+	SweepInterval time.Duration `koanf:"sweep_interval"`
+}
+
+// RetentionConfig paces the job purging the messages that room retention
+// policies expire. Messages are purged at most Interval after they expire.
+// With Archive, they are moved to an archive table instead of being deleted.
+type RetentionConfig struct {
+	Enabled    bool          `koanf:"enabled"`
+	Interval   time.Duration `koanf:"interval"`
+	BatchSize  int           `koanf:"batch_size"`
+	BatchPause time.Duration `koanf:"batch_pause"`
+	Archive    bool          `koanf:"archive"`
+}
+
 type VaultConfig struct {
 	Address string        `koanf:"address"`
 	Token   string        `koanf:"token"`
@@ -119,6 +210,12 @@ func LoadConfig(configPath string) (*Config, error) {
 			k.Set("auth_service.service_token", serviceToken)
 			k.Set("website_service.service_token", serviceToken)
 		}
+		if secretKey := k.String("vault.data.s3_secret_access_key"); secretKey != "" {
+			k.Set("engines.blobs.s3.secret_access_key", secretKey)
+		}
+		if urlSecret := k.String("vault.data.attachment_url_secret"); urlSecret != "" {
+			k.Set("attachments.url_secret", urlSecret)
+		}
 	}
 
 	var config Config
@@ -133,31 +230,65 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.WebsiteService.ServiceToken == "" {
 		return nil, errors.New("website service token is not set after loading")
 	}
+	if config.Attachments.URLSecret == "" {
+		config.Attachments.URLSecret = config.WebsiteService.ServiceToken
+	}
 
 	return &config, nil
 }
 
 func loadDefaults() error {
 	defaults := map[string]interface{}{
-		"engines.storage.max_open_conns":    10,
-		"engines.storage.max_idle_conns":    5,
-		"engines.storage.conn_max_lifetime": time.Hour,
-		"logging.level":                     "info",
-		"handlers.http.read_timeout":        10 * time.Second,
-		"handlers.http.write_timeout":       10 * time.Second,
-		"handlers.http.address":             "localhost",
-		"handlers.http.port":                "8082",
-		"handlers.grpc.address":             "localhost",
-		"handlers.grpc.port":                "9092",
-		"auth_service.address":              "localhost:9090",
-		"website_service.address":           "localhost:9091",
-		"websocket.ping_interval":           30 * time.Second,
-		"websocket.pong_wait":               60 * time.Second,
-		"websocket.max_message_size":        4096, // 4KB
-		"websocket.write_wait":              10 * time.Second,
-		"websocket.message_queue_size":      256,
-		"vault.timeout":                     5 * time.Minute,
-		"graceful_shutdown":                 15 * time.Second,
+		"engines.storage.max_open_conns":           10,
+		"engines.storage.max_idle_conns":           5,
+		"engines.storage.conn_max_lifetime":        time.Hour,
+		"engines.backplane.driver":                 "postgres",
+		"engines.backplane.channel":                "chat_events",
+		"engines.backplane.min_reconnect_interval": time.Second,
+		"engines.backplane.max_reconnect_interval": time.Minute,
+		"engines.blobs.driver":                     "local",
+		"engines.blobs.dir":                        "./data/attachments",
+		"engines.blobs.s3.region":                  "us-east-1",
+		"engines.blobs.s3.timeout":                 time.Minute,
+		"engines.metrics.address":                  ":9102",
+		"logging.level":                            "info",
+		"handlers.http.read_timeout":               10 * time.Second,
+		"handlers.http.write_timeout":              10 * time.Second,
+		"handlers.http.address":                    "localhost",
+		"handlers.http.port":                       "8082",
+		"handlers.grpc.address":                    "localhost",
+		"handlers.grpc.port":                       "9092",
+		"auth_service.address":                     "localhost:9090",
+		"website_service.address":                  "localhost:9091",
+		"websocket.ping_interval":                  30 * time.Second,
+		"websocket.pong_wait":                      60 * time.Second,
+		"websocket.max_message_size":               4096, // 4KB
+		"websocket.write_wait":                     10 * time.Second,
+		"websocket.message_queue_size":             256,
+		"rate_limit.user.rate":                     1,
+		"rate_limit.user.burst":                    5,
+		"rate_limit.room.rate":                     20,
+		"rate_limit.room.burst":                    50,
+		"rate_limit.ip.rate":                       5,
+		"rate_limit.ip.burst":                      20,
+		"scripting.enabled":                        true,
+		"scripting.worker_path":                    "./script-worker",
+		"scripting.workers":                        4,
+		"scripting.memory_limit_mb":                128,
+		"scripting.max_steps":                      100000,
+		"scripting.timeout":                        time.Second,
+		"attachments.max_size_mb":                  10,
+		"attachments.thumbnail_size":               320,
+		"attachments.url_ttl":                      24 * time.Hour,
+		"attachments.orphan_ttl":                   24 * time.Hour,
+		"attachments.sweep_interval":               time.Hour,
+		"retention.enabled":                        true,
+		"retention.interval":                       10 * time.Minute,
+		"retention.batch_size":                     500,
+		"retention.batch_pause":                    100 * time.Millisecond,
+		"retention.archive":                        false,
+		"vault.timeout":                            5 * time.Minute,
+		"graceful_shutdown":                        15 * time.Second,
 	}
 
 	return k.Load(confmap.Provider(defaults, "."), nil)
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	downloadattachment "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/download-attachment"
+	uploadattachment "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/upload-attachment"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead is allowed on top of the file size for the boundaries and
+// headers of an upload.
+const multipartOverhead = 64 << 10
+
+// AttachmentHandler serves the uploads and downloads of attachments.
+type AttachmentHandler struct {
+	logger            *zap.Logger
+	uploadUC          *uploadattachment.UseCase
+	downloadUC        *downloadattachment.UseCase
+	authClient        *auth.Client
+	maxSize           int64
+	trustProxyHeaders bool
+}
+
+func NewAttachmentHandler(
+	logger *zap.Logger,
+	uploadUC *uploadattachment.UseCase,
+	downloadUC *downloadattachment.UseCase,
+	authClient *auth.Client,
+	maxSize int64,
+	trustProxyHeaders bool,
+) *AttachmentHandler {
+	return &AttachmentHandler{
+		logger:            logger,
+		uploadUC:          uploadUC,
+		downloadUC:        downloadUC,
+		authClient:        authClient,
+		maxSize:           maxSize,
+		trustProxyHeaders: trustProxyHeaders,
+	}
+}
+
+// Upload stores the "file" part of a multipart/form-data request as an
+// attachment of the room. The part is streamed, not buffered on disk.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := authenticateRequest(w, r, h.authClient)
+	if !ok {
+		return
+	}
+
+	roomID, err := uuid.Parse(mux.Vars(r)["roomID"])
+	if err != nil {
+		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeJSON(h.logger, w, http.StatusBadRequest, entities.ErrorPayload{Error: "Expected a multipart/form-data upload"})
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			h.writeError(w, errors.Wrap(entities.ErrInvalidAttachment, "missing file part"))
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.uploadUC.Execute(r.Context(), uploadattachment.UploadInput{
+			RoomID:   roomID,
+			UserID:   userInfo.UserID,
+			FileName: part.FileName(),
+			Content:  part,
+			ClientIP: clientIP(r, h.trustProxyHeaders),
+		})
+		part.Close()
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		writeJSON(h.logger, w, http.StatusCreated, attachment)
+		return
+	}
+}
+
+// Download serves the content of an attachment, or of its thumbnail, for a
+// signed URL. No access token is needed: the signature authorizes the request.
+// Content is served with its sniffed type in a sandbox, so that uploaded HTML
+// or SVG cannot run scripts on the origin of the chat service.
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	attachmentID, err := uuid.Parse(mux.Vars(r)["attachmentID"])
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+	thumbnail := mux.Vars(r)["variant"] == "thumbnail"
+
+	attachment, content, err := h.downloadUC.Execute(r.Context(), downloadattachment.DownloadInput{
+		AttachmentID: attachmentID,
+		Thumbnail:    thumbnail,
+		Expires:      expires,
+		Signature:    query.Get("sig"),
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	defer content.Close()
+
+	contentType, disposition := attachment.ContentType, "attachment"
+	if thumbnail {
+		contentType = "image/jpeg"
+	}
+	if attachment.IsImage() {
+		disposition = "inline"
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", contentType)
+	if !thumbnail {
+		header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "sandbox")
+	header.Set("Cache-Control", "private, max-age=3600")
+
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Debug("Failed to write attachment",
+			zap.Error(err),
+			zap.String("attachment_id", attachmentID.String()),
+		)
+	}
+}
+
+func (h *AttachmentHandler) writeError(w http.ResponseWriter, err error) {
+	payload := clientErrorPayload(err, "Failed to process attachment")
+	if payload.RetryAfterMs > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(float64(payload.RetryAfterMs)/1000)), 10))
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	var status int
+	switch {
+	case errors.As(err, &maxBytesErr), errors.Is(err, entities.ErrAttachmentTooLarge):
+		status = http.StatusRequestEntityTooLarge
+		payload.Error = "File is too large"
+	case errors.Is(err, entities.ErrInvalidAttachment):
+		status = http.StatusBadRequest
+	case errors.Is(err, entities.ErrAttachmentNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, entities.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, entities.ErrRateLimited):
+		status = http.StatusTooManyRequests
+	default:
+		h.logger.Error("Failed to process attachment", zap.Error(err))
+		status = http.StatusInternalServerError
+	}
+
+	writeJSON(h.logger, w, status, payload)
+}
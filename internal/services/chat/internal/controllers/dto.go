@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"sync"
+	"time"
 
+	"github.com/HexArch/go-chat/internal/services/chat/internal/config"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -10,55 +12,109 @@ import (
 )
 
 type WebSocketMessage struct {
-	Type    string         `json:"type"`
-	Content string         `json:"content,omitempty"`
-	Limit   int            `json:"limit,omitempty"`
-	Offset  int            `json:"offset,omitempty"`
-	Data    map[string]any `json:"data,omitempty"`
+	Type          string         `json:"type"`
+	Content       string         `json:"content,omitempty"`
+	ClientMsgID   string         `json:"client_msg_id,omitempty"`
+	MessageID     string         `json:"message_id,omitempty"`
+	ParentID      string         `json:"parent_id,omitempty"`
+	Emoji         string         `json:"emoji,omitempty"`
+	Query         string         `json:"query,omitempty"`
+	AuthorID      string         `json:"author_id,omitempty"`
+	From          string         `json:"from,omitempty"`
+	To            string         `json:"to,omitempty"`
+	Limit         int            `json:"limit,omitempty"`
+	Offset        int            `json:"offset,omitempty"`
+	Before        string         `json:"before,omitempty"`
+	After         string         `json:"after,omitempty"`
+	UserIDs       []string       `json:"user_ids,omitempty"`
+	AttachmentIDs []string       `json:"attachment_ids,omitempty"`
+	Status        string         `json:"status,omitempty"`
+	Seconds       int            `json:"seconds,omitempty"`
+	Data          map[string]any `json:"data,omitempty"`
 }
 
+// WebSocketConnection owns a WebSocket. Outbound events go through a bounded
+// queue drained by a single writer goroutine, which also sends pings; a client
+// whose queue overflows is disconnected instead of stalling broadcasts.
 type WebSocketConnection struct {
+	id        uuid.UUID
 	conn      *websocket.Conn
 	logger    *zap.Logger
+	config    config.WebSocketConfig
 	userID    uuid.UUID
 	roomID    uuid.UUID
+	send      chan []byte
 	mu        sync.Mutex
 	closed    bool
 	closeChan chan struct{}
 }
 
-func NewWebSocketConnection(conn *websocket.Conn, logger *zap.Logger, userID, roomID uuid.UUID) *WebSocketConnection {
-	return &WebSocketConnection{
+func NewWebSocketConnection(conn *websocket.Conn, logger *zap.Logger, cfg config.WebSocketConfig, userID, roomID uuid.UUID) *WebSocketConnection {
+	c := &WebSocketConnection{
+		id:        uuid.New(),
 		conn:      conn,
 		logger:    logger,
+		config:    cfg,
 		userID:    userID,
 		roomID:    roomID,
+		send:      make(chan []byte, cfg.MessageQueueSize),
 		closeChan: make(chan struct{}),
 	}
+
+	conn.SetReadLimit(cfg.MaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	})
+
+	go c.writePump()
+
+	return c
 }
 
+// Send queues a message for the writer goroutine without blocking.
 func (c *WebSocketConnection) Send(message []byte) error {
-	if c.IsClosed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
 		return entities.ErrConnectionClosed
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	select {
+	case c.send <- message:
+		return nil
+	default:
+		c.logger.Warn("WebSocket send queue is full, closing slow connection",
+			zap.String("room_id", c.roomID.String()),
+			zap.String("user_id", c.userID.String()),
+		)
+		c.closeLocked()
+		return entities.ErrConnectionClosed
+	}
+}
 
-	return c.conn.WriteMessage(websocket.TextMessage, message)
+// ReadMessage reads the next client frame. Read deadlines are extended by pongs.
+func (c *WebSocketConnection) ReadMessage() (int, []byte, error) {
+	return c.conn.ReadMessage()
 }
 
+// Close stops the writer, which sends a close frame and closes the socket.
 func (c *WebSocketConnection) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.closeLocked()
+	return nil
+}
+
+func (c *WebSocketConnection) closeLocked() {
 	if c.closed {
-		return nil
+		return
 	}
 
 	c.closed = true
 	close(c.closeChan)
-	return c.conn.Close()
 }
 
 func (c *WebSocketConnection) IsClosed() bool {
@@ -72,5 +128,48 @@ func (c *WebSocketConnection) Type() entities.ConnectionType {
 }
 
 func (c *WebSocketConnection) ID() uuid.UUID {
-	return c.userID
+	return c.id
+}
+
+// writePump is the only goroutine writing to the socket.
+func (c *WebSocketConnection) writePump() {
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.Close()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				c.logger.Debug("WebSocket write failed",
+					zap.Error(err),
+					zap.String("room_id", c.roomID.String()),
+					zap.String("user_id", c.userID.String()),
+				)
+				return
+			}
+
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.config.WriteWait)); err != nil {
+				c.logger.Debug("WebSocket ping failed",
+					zap.Error(err),
+					zap.String("room_id", c.roomID.String()),
+					zap.String("user_id", c.userID.String()),
+				)
+				return
+			}
+
+		case <-c.closeChan:
+			c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(c.config.WriteWait),
+			)
+			return
+		}
+	}
 }
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	exportroom "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/export-room"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ExportHandler serves the history of rooms as downloads.
+type ExportHandler struct {
+	logger     *zap.Logger
+	exportUC   *exportroom.UseCase
+	authClient *auth.Client
+}
+
+func NewExportHandler(
+	logger *zap.Logger,
+	exportUC *exportroom.UseCase,
+	authClient *auth.Client,
+) *ExportHandler {
+	return &ExportHandler{
+		logger:     logger,
+		exportUC:   exportUC,
+		authClient: authClient,
+	}
+}
+
+// Export streams the whole history of a room to one of its participants, in
+// the format named by the "format" query parameter.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := authenticateRequest(w, r, h.authClient)
+	if !ok {
+		return
+	}
+
+	roomID, err := uuid.Parse(mux.Vars(r)["roomID"])
+	if err != nil {
+		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		return
+	}
+
+	format, err := entities.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	// Exports of large rooms outlive the server-wide write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug("Failed to clear write deadline", zap.Error(err))
+	}
+
+	fileName := "room-" + roomID.String() + "-" + time.Now().UTC().Format("20060102") + format.Extension()
+	header := w.Header()
+	header.Set("Content-Type", format.ContentType())
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Cache-Control", "no-store")
+
+	out := &trackingWriter{ResponseWriter: w}
+	err = h.exportUC.Execute(r.Context(), exportroom.ExportInput{
+		RoomID: roomID,
+		UserID: userInfo.UserID,
+		Format: format,
+	}, out)
+	if err == nil {
+		return
+	}
+
+	// Once the export started, the status is sent: the download is cut short.
+	if out.written {
+		h.logger.Error("Export interrupted",
+			zap.Error(err),
+			zap.String("room_id", roomID.String()),
+		)
+		return
+	}
+
+	header.Del("Content-Disposition")
+	h.writeError(w, err)
+}
+
+func (h *ExportHandler) writeError(w http.ResponseWriter, err error) {
+	payload := clientErrorPayload(err, "Failed to export room")
+
+	var status int
+	switch {
+	case errors.Is(err, entities.ErrInvalidExportFormat):
+		status = http.StatusBadRequest
+	case errors.Is(err, entities.ErrRoomNotFound):
+		status = http.StatusNotFound
+		payload.Error = "Room not found"
+	case errors.Is(err, entities.ErrForbidden):
+		status = http.StatusForbidden
+	default:
+		h.logger.Error("Failed to export room", zap.Error(err))
+		status = http.StatusInternalServerError
+	}
+
+	writeJSON(h.logger, w, status, payload)
+}
+
+// trackingWriter records whether a response has started.
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *trackingWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
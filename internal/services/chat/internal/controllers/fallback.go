@@ -0,0 +1,441 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/connect"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/disconnect"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// sseHeartbeatInterval keeps proxies from closing idle SSE streams.
+	sseHeartbeatInterval = 15 * time.Second
+	// defaultPollTimeout and maxPollTimeout bound how long a poll request waits for events.
+	defaultPollTimeout = 25 * time.Second
+	maxPollTimeout     = 30 * time.Second
+	// sessionIdleTimeout is how long a long-poll session survives without being polled.
+	sessionIdleTimeout = time.Minute
+)
+
+// sessionConnection is a connection of an HTTP fallback transport. Such
+// connections are addressed by session ID when the client sends frames.
+type sessionConnection interface {
+	entities.Connection
+	SessionID() uuid.UUID
+	Done() <-chan struct{}
+}
+
+type fallbackSession struct {
+	conn        sessionConnection
+	roomID      uuid.UUID
+	userID      uuid.UUID
+	permissions []string // Permissions of the user when the session was opened.
+}
+
+// FallbackHandler serves the transports used when WebSocket upgrades are not
+// available: Server-Sent Events and long polling for server to client events,
+// and a plain POST endpoint for client frames.
+type FallbackHandler struct {
+	logger       *zap.Logger
+	wsHandler    *WebSocketHandler
+	connectUC    *connect.UseCase
+	disconnectUC *disconnect.UseCase
+	authClient   *auth.Client
+	sessions     map[uuid.UUID]*fallbackSession
+	mu           sync.RWMutex
+	cleanupTick  *time.Ticker
+}
+
+func NewFallbackHandler(
+	logger *zap.Logger,
+	wsHandler *WebSocketHandler,
+	connectUC *connect.UseCase,
+	disconnectUC *disconnect.UseCase,
+	authClient *auth.Client,
+) *FallbackHandler {
+	h := &FallbackHandler{
+		logger:       logger,
+		wsHandler:    wsHandler,
+		connectUC:    connectUC,
+		disconnectUC: disconnectUC,
+		authClient:   authClient,
+		sessions:     make(map[uuid.UUID]*fallbackSession),
+	}
+
+	h.startCleanupTicker()
+	return h
+}
+
+// Transports lists the available transports in order of preference so that
+// clients can fall back when a transport cannot be established.
+func (h *FallbackHandler) Transports(w http.ResponseWriter, r *http.Request) {
+	writeJSON(h.logger, w, http.StatusOK, map[string]interface{}{
+		"transports": []map[string]string{
+			{
+				"name":    "websocket",
+				"connect": "/ws/chat/{room_id}",
+			},
+			{
+				"name":    "sse",
+				"connect": "/sse/chat/{room_id}",
+				"send":    "/api/v1/chat/sessions/{session_id}/messages",
+			},
+			{
+				"name":    "long_polling",
+				"connect": "/api/v1/chat/rooms/{room_id}/poll",
+				"poll":    "/api/v1/chat/sessions/{session_id}/events",
+				"send":    "/api/v1/chat/sessions/{session_id}/messages",
+			},
+		},
+	})
+}
+
+// ServeSSE joins the room and streams its events until the client goes away.
+// The first event is a "session" event carrying the session ID used to send frames.
+func (h *FallbackHandler) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := authenticateRequest(w, r, h.authClient)
+	if !ok {
+		return
+	}
+
+	roomID, err := uuid.Parse(mux.Vars(r)["roomID"])
+	if err != nil {
+		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// The stream outlives the server-wide write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug("Failed to clear write deadline", zap.Error(err))
+	}
+
+	sinceSeq, err := parseSinceSeq(r)
+	if err != nil {
+		http.Error(w, "Invalid since_seq", http.StatusBadRequest)
+		return
+	}
+
+	conn := NewSSEConnection(h.logger, userInfo.UserID, roomID)
+	if err := h.openSession(r.Context(), conn, roomID, userInfo, sinceSeq); err != nil {
+		h.writeConnectError(w, err)
+		return
+	}
+	defer conn.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "event: session\ndata: {\"session_id\":%q}\n\n", conn.SessionID().String())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-conn.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-conn.events:
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// OpenLongPoll joins the room with a long-poll session and returns its ID.
+func (h *FallbackHandler) OpenLongPoll(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := authenticateRequest(w, r, h.authClient)
+	if !ok {
+		return
+	}
+
+	roomID, err := uuid.Parse(mux.Vars(r)["roomID"])
+	if err != nil {
+		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		return
+	}
+
+	sinceSeq, err := parseSinceSeq(r)
+	if err != nil {
+		http.Error(w, "Invalid since_seq", http.StatusBadRequest)
+		return
+	}
+
+	conn := NewLongPollConnection(h.logger, userInfo.UserID, roomID)
+	if err := h.openSession(r.Context(), conn, roomID, userInfo, sinceSeq); err != nil {
+		h.writeConnectError(w, err)
+		return
+	}
+
+	writeJSON(h.logger, w, http.StatusCreated, map[string]string{
+		"session_id": conn.SessionID().String(),
+	})
+}
+
+// Poll waits for events of a long-poll session. The optional timeout query
+// parameter is in seconds. A client passing the cursor of its previous poll
+// acknowledges the events of that poll only then, so that events of a response
+// it did not receive are sent again; otherwise events are acknowledged once the
+// response is written.
+func (h *FallbackHandler) Poll(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.lookupSession(w, r)
+	if !ok {
+		return
+	}
+
+	conn, ok := session.conn.(*LongPollConnection)
+	if !ok {
+		http.Error(w, "Session is not a long-poll session", http.StatusBadRequest)
+		return
+	}
+
+	timeout := defaultPollTimeout
+	if seconds, err := strconv.Atoi(r.URL.Query().Get("timeout")); err == nil && seconds >= 0 {
+		timeout = min(time.Duration(seconds)*time.Second, maxPollTimeout)
+	}
+
+	var ack *int64
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		cursor, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || cursor < 0 {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		ack = &cursor
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + 5*time.Second)); err != nil {
+		h.logger.Debug("Failed to extend write deadline", zap.Error(err))
+	}
+
+	events, cursor, err := conn.Poll(r.Context(), timeout, ack)
+	if err != nil {
+		http.Error(w, "Session closed", http.StatusGone)
+		return
+	}
+
+	rawEvents := make([]json.RawMessage, len(events))
+	for i, event := range events {
+		rawEvents[i] = event
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"events": rawEvents,
+		"cursor": cursor,
+	})
+	if err != nil {
+		h.logger.Error("Failed to marshal poll response", zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		// The events stay queued for the next poll.
+		h.logger.Debug("Failed to write poll response", zap.Error(err))
+		return
+	}
+	if ack == nil {
+		conn.Ack(cursor)
+	}
+}
+
+// SendMessage accepts a client frame in the WebSocket message format. Responses
+// such as history or errors are delivered through the session's event stream.
+func (h *FallbackHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.lookupSession(w, r)
+	if !ok {
+		return
+	}
+
+	var msg WebSocketMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.wsHandler.config.MaxMessageSize)).Decode(&msg); err != nil {
+		http.Error(w, "Invalid message", http.StatusBadRequest)
+		return
+	}
+
+	h.wsHandler.handleClientMessage(session.conn, session.roomID, session.userID, session.permissions, clientIP(r, h.wsHandler.trustProxyHeaders), msg)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// CloseSession leaves the room of an SSE or long-poll session.
+func (h *FallbackHandler) CloseSession(w http.ResponseWriter, r *http.Request) {
+	session, ok := h.lookupSession(w, r)
+	if !ok {
+		return
+	}
+
+	session.conn.Close()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Close ends every fallback session.
+func (h *FallbackHandler) Close() {
+	if h.cleanupTick != nil {
+		h.cleanupTick.Stop()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, session := range h.sessions {
+		session.conn.Close()
+	}
+}
+
+func (h *FallbackHandler) openSession(ctx context.Context, conn sessionConnection, roomID uuid.UUID, userInfo *auth.ValidateResponse, sinceSeq *int64) error {
+	userID := userInfo.UserID
+	if err := h.connectUC.Execute(ctx, connect.ConnectInput{
+		RoomID:     roomID,
+		UserID:     userID,
+		Connection: conn,
+		Event: &entities.Event{
+			Type:      entities.EventUserConnected,
+			RoomID:    roomID,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		},
+		SinceSeq: sinceSeq,
+	}); err != nil {
+		return err
+	}
+
+	session := &fallbackSession{
+		conn:        conn,
+		roomID:      roomID,
+		userID:      userID,
+		permissions: userInfo.Permissions,
+	}
+
+	h.mu.Lock()
+	h.sessions[conn.SessionID()] = session
+	h.mu.Unlock()
+
+	go h.watchSession(session)
+
+	return nil
+}
+
+// writeConnectError reports why a session could not join its room.
+func (h *FallbackHandler) writeConnectError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entities.ErrRoomNotFound):
+		http.Error(w, "Room not found", http.StatusNotFound)
+	case errors.Is(err, entities.ErrForbidden):
+		http.Error(w, "Not a member of this room", http.StatusForbidden)
+	default:
+		h.logger.Error("Failed to connect to room", zap.Error(err))
+		http.Error(w, "Failed to connect to room", http.StatusInternalServerError)
+	}
+}
+
+// watchSession leaves the room once the session connection is closed, whichever side closed it.
+func (h *FallbackHandler) watchSession(session *fallbackSession) {
+	<-session.conn.Done()
+
+	h.mu.Lock()
+	delete(h.sessions, session.conn.SessionID())
+	h.mu.Unlock()
+
+	if err := h.disconnectUC.Execute(context.Background(), disconnect.DisconnectInput{
+		RoomID:       session.roomID,
+		UserID:       session.userID,
+		ConnectionID: session.conn.ID(),
+		Event: &entities.Event{
+			Type:      entities.EventUserDisconnected,
+			RoomID:    session.roomID,
+			UserID:    session.userID,
+			Timestamp: time.Now(),
+		},
+	}); err != nil {
+		h.logger.Error("Failed to disconnect", zap.Error(err))
+	}
+}
+
+// lookupSession authenticates the request and resolves the session it addresses.
+// Sessions of other users are reported as missing.
+func (h *FallbackHandler) lookupSession(w http.ResponseWriter, r *http.Request) (*fallbackSession, bool) {
+	userInfo, ok := authenticateRequest(w, r, h.authClient)
+	if !ok {
+		return nil, false
+	}
+
+	sessionID, err := uuid.Parse(mux.Vars(r)["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	h.mu.RLock()
+	session, exists := h.sessions[sessionID]
+	h.mu.RUnlock()
+
+	if !exists || session.userID != userInfo.UserID || session.conn.IsClosed() {
+		http.Error(w, "Session not found", http.StatusGone)
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (h *FallbackHandler) startCleanupTicker() {
+	h.cleanupTick = time.NewTicker(sessionIdleTimeout / 2)
+	go func() {
+		for range h.cleanupTick.C {
+			h.closeIdleSessions()
+		}
+	}()
+}
+
+// closeIdleSessions closes long-poll sessions whose client stopped polling.
+func (h *FallbackHandler) closeIdleSessions() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, session := range h.sessions {
+		conn, ok := session.conn.(*LongPollConnection)
+		if !ok {
+			continue
+		}
+
+		if time.Since(conn.IdleSince()) > sessionIdleTimeout {
+			h.logger.Debug("Closing idle long-poll session",
+				zap.String("room_id", session.roomID.String()),
+				zap.String("user_id", session.userID.String()),
+			)
+			conn.Close()
+		}
+	}
+}
@@ -0,0 +1,822 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/chat"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	addreaction "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/add-reaction"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/connect"
+	deletemessage "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/delete-message"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/disconnect"
+	editmessage "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/edit-message"
+	getcommands "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-commands"
+	getmessages "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-messages"
+	getpresence "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-presence"
+	getroomparticipants "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-room-participants"
+	markread "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/mark-read"
+	removereaction "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/remove-reaction"
+	runcommand "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/run-command"
+	sendmessage "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/send-message"
+	setpresence "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/set-presence"
+	setslowmode "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/set-slow-mode"
+	subscribepresence "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/subscribe-presence"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/typing"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ChatServiceServer exposes the chat over gRPC: a bidirectional Connect stream
+// with the same semantics as the WebSocket endpoint plus unary history, participant and presence lookups.
+type ChatServiceServer struct {
+	chat.UnimplementedChatServiceServer
+	logger         *zap.Logger
+	connectUC      *connect.UseCase
+	disconnectUC   *disconnect.UseCase
+	messageUC      *sendmessage.UseCase
+	getMessagesUC  *getmessages.UseCase
+	participantsUC *getroomparticipants.UseCase
+	editUC         *editmessage.UseCase
+	deleteUC       *deletemessage.UseCase
+	addReactUC     *addreaction.UseCase
+	removeReactUC  *removereaction.UseCase
+	typingUC       *typing.UseCase
+	markReadUC     *markread.UseCase
+	presenceUC     *setpresence.UseCase
+	subscribeUC    *subscribepresence.UseCase
+	getPresenceUC  *getpresence.UseCase
+	slowModeUC     *setslowmode.UseCase
+	commandUC      *runcommand.UseCase
+	commandsUC     *getcommands.UseCase
+	authClient     *auth.Client
+
+	// trustProxyHeaders reads client IPs from the x-real-ip metadata of the proxy.
+	trustProxyHeaders bool
+}
+
+func NewChatServiceServer(
+	logger *zap.Logger,
+	connectUC *connect.UseCase,
+	disconnectUC *disconnect.UseCase,
+	messageUC *sendmessage.UseCase,
+	getMessagesUC *getmessages.UseCase,
+	participantsUC *getroomparticipants.UseCase,
+	editUC *editmessage.UseCase,
+	deleteUC *deletemessage.UseCase,
+	addReactUC *addreaction.UseCase,
+	removeReactUC *removereaction.UseCase,
+	typingUC *typing.UseCase,
+	markReadUC *markread.UseCase,
+	presenceUC *setpresence.UseCase,
+	subscribeUC *subscribepresence.UseCase,
+	getPresenceUC *getpresence.UseCase,
+	slowModeUC *setslowmode.UseCase,
+	commandUC *runcommand.UseCase,
+	commandsUC *getcommands.UseCase,
+	authClient *auth.Client,
+	trustProxyHeaders bool,
+) *ChatServiceServer {
+	return &ChatServiceServer{
+		logger:         logger,
+		connectUC:      connectUC,
+		disconnectUC:   disconnectUC,
+		messageUC:      messageUC,
+		getMessagesUC:  getMessagesUC,
+		participantsUC: participantsUC,
+		editUC:         editUC,
+		deleteUC:       deleteUC,
+		addReactUC:     addReactUC,
+		removeReactUC:  removeReactUC,
+		typingUC:       typingUC,
+		markReadUC:     markReadUC,
+		presenceUC:     presenceUC,
+		subscribeUC:    subscribeUC,
+		getPresenceUC:  getPresenceUC,
+		slowModeUC:     slowModeUC,
+		commandUC:      commandUC,
+		commandsUC:     commandsUC,
+		authClient:     authClient,
+
+		trustProxyHeaders: trustProxyHeaders,
+	}
+}
+
+// Connect joins the room named by the first JoinRoom frame and streams its events
+// until the client closes the stream or the connection is replaced.
+func (s *ChatServiceServer) Connect(stream chat.ChatService_ConnectServer) error {
+	userInfo, err := s.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	frame, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	join := frame.GetJoin()
+	if join == nil {
+		return status.Error(codes.InvalidArgument, "first frame must join a room")
+	}
+
+	roomID, err := uuid.Parse(join.RoomId)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid room ID")
+	}
+
+	conn := NewGRPCConnection(s.logger, userInfo.UserID, roomID)
+
+	if err := s.connectUC.Execute(stream.Context(), connect.ConnectInput{
+		RoomID:     roomID,
+		UserID:     userInfo.UserID,
+		Connection: conn,
+		Event: &entities.Event{
+			Type:      entities.EventUserConnected,
+			RoomID:    roomID,
+			UserID:    userInfo.UserID,
+			Timestamp: time.Now(),
+		},
+		SinceSeq: join.SinceSeq,
+	}); err != nil {
+		return s.toStatusError(err, "failed to connect to room")
+	}
+
+	go s.handleFrames(stream, conn, roomID, userInfo.UserID, userInfo.Permissions, s.clientIP(stream.Context()))
+
+	defer func() {
+		if err := s.disconnectUC.Execute(context.Background(), disconnect.DisconnectInput{
+			RoomID:       roomID,
+			UserID:       userInfo.UserID,
+			ConnectionID: conn.ID(),
+			Event: &entities.Event{
+				Type:      entities.EventUserDisconnected,
+				RoomID:    roomID,
+				UserID:    userInfo.UserID,
+				Timestamp: time.Now(),
+			},
+		}); err != nil {
+			s.logger.Error("Failed to disconnect", zap.Error(err))
+		}
+
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case <-conn.closeChan:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case event := <-conn.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *ChatServiceServer) handleFrames(stream chat.ChatService_ConnectServer, conn *GRPCConnection, roomID, userID uuid.UUID, permissions []string, clientIP string) {
+	defer conn.Close()
+
+	for {
+		frame, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF && status.Code(err) != codes.Canceled {
+				s.logger.Error("gRPC stream read error",
+					zap.Error(err),
+					zap.String("room_id", roomID.String()),
+					zap.String("user_id", userID.String()),
+				)
+			}
+			return
+		}
+
+		if err := s.handleFrame(conn, roomID, userID, permissions, clientIP, frame); err != nil {
+			if isRefusal(err) || isCommandError(err) {
+				s.logger.Debug("gRPC frame refused",
+					zap.Error(err),
+					zap.String("room_id", roomID.String()),
+					zap.String("user_id", userID.String()),
+				)
+			} else {
+				s.logger.Error("Failed to handle gRPC frame",
+					zap.Error(err),
+					zap.String("room_id", roomID.String()),
+					zap.String("user_id", userID.String()),
+				)
+			}
+			s.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to process request"))
+		}
+	}
+}
+
+func (s *ChatServiceServer) handleFrame(conn *GRPCConnection, roomID, userID uuid.UUID, permissions []string, clientIP string, frame *chat.ClientFrame) error {
+	ctx := context.Background()
+
+	switch f := frame.Frame.(type) {
+	case *chat.ClientFrame_SendMessage:
+		if len(f.SendMessage.AttachmentIds) == 0 && runcommand.IsCommand(f.SendMessage.Content) {
+			return s.handleCommand(ctx, conn, roomID, userID, permissions, clientIP, f.SendMessage)
+		}
+		return s.handleSendMessage(ctx, conn, roomID, userID, clientIP, f.SendMessage)
+
+	case *chat.ClientFrame_GetHistory:
+		history, err := s.getHistory(ctx, roomID, userID, f.GetHistory.Limit, f.GetHistory.Before, f.GetHistory.After)
+		if err != nil {
+			return err
+		}
+
+		return conn.SendEvent(&chat.Event{
+			Type:      string(entities.EventMessageHistory),
+			RoomId:    roomID.String(),
+			UserId:    userID.String(),
+			Timestamp: timestamppb.Now(),
+			Body:      &chat.Event_History{History: history},
+		})
+
+	case *chat.ClientFrame_EditMessage:
+		messageID, err := uuid.Parse(f.EditMessage.MessageId)
+		if err != nil {
+			return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+		}
+
+		_, err = s.editUC.Execute(ctx, editmessage.EditInput{
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: messageID,
+			Content:   f.EditMessage.Content,
+		})
+		return err
+
+	case *chat.ClientFrame_DeleteMessage:
+		messageID, err := uuid.Parse(f.DeleteMessage.MessageId)
+		if err != nil {
+			return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+		}
+
+		return s.deleteUC.Execute(ctx, deletemessage.DeleteInput{
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: messageID,
+		})
+
+	case *chat.ClientFrame_AddReaction:
+		messageID, err := uuid.Parse(f.AddReaction.MessageId)
+		if err != nil {
+			return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+		}
+
+		return s.addReactUC.Execute(ctx, addreaction.ReactionInput{
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: messageID,
+			Emoji:     f.AddReaction.Emoji,
+		})
+
+	case *chat.ClientFrame_RemoveReaction:
+		messageID, err := uuid.Parse(f.RemoveReaction.MessageId)
+		if err != nil {
+			return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+		}
+
+		return s.removeReactUC.Execute(ctx, removereaction.ReactionInput{
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: messageID,
+			Emoji:     f.RemoveReaction.Emoji,
+		})
+
+	case *chat.ClientFrame_Typing:
+		// Typing indicators are best-effort: failures are only logged.
+		if err := s.typingUC.Execute(ctx, typing.TypingInput{
+			RoomID: roomID,
+			UserID: userID,
+			Typing: f.Typing.Typing,
+		}); err != nil {
+			s.logger.Debug("Failed to handle typing indicator",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+		}
+		return nil
+
+	case *chat.ClientFrame_MarkRead:
+		messageID, err := uuid.Parse(f.MarkRead.MessageId)
+		if err != nil {
+			return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+		}
+
+		return s.markReadUC.Execute(ctx, markread.MarkReadInput{
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: messageID,
+		})
+
+	case *chat.ClientFrame_Presence:
+		return s.presenceUC.Execute(ctx, setpresence.PresenceInput{
+			UserID:       userID,
+			ConnectionID: conn.ID(),
+			Status:       entities.PresenceStatus(f.Presence.Status),
+		})
+
+	case *chat.ClientFrame_SubscribePresence:
+		return s.updatePresenceSubscription(ctx, conn, roomID, f.SubscribePresence.UserIds, false)
+
+	case *chat.ClientFrame_UnsubscribePresence:
+		return s.updatePresenceSubscription(ctx, conn, roomID, f.UnsubscribePresence.UserIds, true)
+
+	case *chat.ClientFrame_SlowMode:
+		return s.slowModeUC.Execute(ctx, setslowmode.SlowModeInput{
+			RoomID:  roomID,
+			UserID:  userID,
+			Seconds: int(f.SlowMode.Seconds),
+		})
+
+	case *chat.ClientFrame_GetCommands:
+		response, err := s.commandsUC.Execute(ctx, getcommands.CommandsInput{
+			RoomID:      &roomID,
+			UserID:      userID,
+			Permissions: permissions,
+		})
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(response)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal commands")
+		}
+
+		return conn.SendEvent(&chat.Event{
+			Type:      string(entities.EventCommands),
+			RoomId:    roomID.String(),
+			UserId:    userID.String(),
+			Timestamp: timestamppb.Now(),
+			Body:      &chat.Event_Payload{Payload: payload},
+		})
+
+	case *chat.ClientFrame_Join:
+		return errors.Wrap(entities.ErrForbidden, "already joined a room")
+
+	default:
+		return nil
+	}
+}
+
+// GetMessages returns a page of room history to a participant of the room.
+func (s *ChatServiceServer) GetMessages(ctx context.Context, req *chat.GetMessagesRequest) (*chat.MessageHistory, error) {
+	userInfo, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID")
+	}
+
+	history, err := s.getHistory(ctx, roomID, userInfo.UserID, req.Limit, req.Before, req.After)
+	if err != nil {
+		return nil, s.toStatusError(err, "failed to get messages")
+	}
+
+	return history, nil
+}
+
+// GetParticipants lists the participants of a room the caller has joined.
+func (s *ChatServiceServer) GetParticipants(ctx context.Context, req *chat.GetParticipantsRequest) (*chat.ParticipantsResponse, error) {
+	userInfo, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID")
+	}
+
+	participants, err := s.participantsUC.Execute(ctx, roomID, userInfo.UserID)
+	if err != nil {
+		return nil, s.toStatusError(err, "failed to get participants")
+	}
+
+	userIDs := make([]string, len(participants))
+	for i, id := range participants {
+		userIDs[i] = id.String()
+	}
+
+	return &chat.ParticipantsResponse{UserIds: userIDs}, nil
+}
+
+// GetPresence returns the presence of a batch of users.
+func (s *ChatServiceServer) GetPresence(ctx context.Context, req *chat.GetPresenceRequest) (*chat.PresenceResponse, error) {
+	if _, err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := parseIDs(req.UserIds)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	response, err := s.getPresenceUC.Execute(ctx, userIDs)
+	if err != nil {
+		return nil, s.toStatusError(err, "failed to get presence")
+	}
+
+	pbResponse := &chat.PresenceResponse{
+		Presence: make([]*chat.Presence, len(response.Presence)),
+	}
+	for i, p := range response.Presence {
+		pbResponse.Presence[i] = presenceToProto(p)
+	}
+
+	return pbResponse, nil
+}
+
+// updatePresenceSubscription updates the presence subscriptions of the stream.
+// Subscribing replies with one presence event per requested user.
+func (s *ChatServiceServer) updatePresenceSubscription(ctx context.Context, conn *GRPCConnection, roomID uuid.UUID, rawUserIDs []string, unsubscribe bool) error {
+	userIDs, err := parseIDs(rawUserIDs)
+	if err != nil {
+		return errors.Wrap(entities.ErrInvalidPresence, "invalid user ID")
+	}
+
+	presence, err := s.subscribeUC.Execute(ctx, subscribepresence.SubscribeInput{
+		Connection:  conn,
+		UserIDs:     userIDs,
+		Unsubscribe: unsubscribe,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range presence {
+		payload, err := json.Marshal(p)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal presence")
+		}
+
+		if err := conn.SendEvent(&chat.Event{
+			Type:      string(entities.EventPresence),
+			RoomId:    roomID.String(),
+			UserId:    p.UserID.String(),
+			Timestamp: timestamppb.Now(),
+			Body:      &chat.Event_Payload{Payload: payload},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ChatServiceServer) getHistory(ctx context.Context, roomID, userID uuid.UUID, limit int32, rawBefore, rawAfter string) (*chat.MessageHistory, error) {
+	before, err := entities.ParseMessageCursor(rawBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := entities.ParseMessageCursor(rawAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.getMessagesUC.Execute(ctx, getmessages.MessagesInput{
+		RoomID: roomID,
+		UserID: userID,
+		Limit:  int(limit),
+		Before: before,
+		After:  after,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get messages history")
+	}
+
+	history := &chat.MessageHistory{
+		Messages:   make([]*chat.Message, len(response.Messages)),
+		PrevCursor: response.PrevCursor,
+		NextCursor: response.NextCursor,
+		LastSeq:    response.LastSeq,
+	}
+	for i, msg := range response.Messages {
+		history.Messages[i] = messageToProto(msg)
+	}
+
+	return history, nil
+}
+
+// authenticate validates the bearer token from the "authorization" metadata.
+func (s *ChatServiceServer) authenticate(ctx context.Context) (*auth.ValidateResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	userInfo, err := s.authClient.ValidateToken(ctx, strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return userInfo, nil
+}
+
+// handleSendMessage stores a message and acknowledges it to the sender. Failures of
+// frames with a client message ID are reported through a failed ack.
+func (s *ChatServiceServer) handleSendMessage(ctx context.Context, conn *GRPCConnection, roomID, userID uuid.UUID, clientIP string, frame *chat.SendMessage) error {
+	msg, err := s.sendMessage(ctx, roomID, userID, clientIP, frame)
+	if err != nil {
+		if frame.ClientMsgId == "" {
+			return err
+		}
+
+		if !isRefusal(err) {
+			s.logger.Error("Failed to handle message",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+		}
+
+		payload := clientErrorPayload(err, "Failed to send message")
+		return s.sendAck(conn, roomID, userID, &chat.MessageAck{
+			ClientMsgId:  frame.ClientMsgId,
+			Error:        payload.Error,
+			RetryAfterMs: payload.RetryAfterMs,
+		})
+	}
+
+	return s.sendAck(conn, roomID, userID, &chat.MessageAck{
+		ClientMsgId: frame.ClientMsgId,
+		MessageId:   msg.ID.String(),
+		Timestamp:   timestamppb.New(msg.Timestamp),
+	})
+}
+
+// handleCommand runs the slash command of a SendMessage frame, sends its reply
+// to the stream and acknowledges the frame like a message.
+func (s *ChatServiceServer) handleCommand(ctx context.Context, conn *GRPCConnection, roomID, userID uuid.UUID, permissions []string, clientIP string, frame *chat.SendMessage) error {
+	result, err := s.runCommand(ctx, roomID, userID, permissions, clientIP, frame)
+	if err != nil {
+		if frame.ClientMsgId == "" {
+			return err
+		}
+
+		if !isRefusal(err) && !isCommandError(err) {
+			s.logger.Error("Failed to run command",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+		}
+
+		payload := clientErrorPayload(err, "Failed to run command")
+		return s.sendAck(conn, roomID, userID, &chat.MessageAck{
+			ClientMsgId:  frame.ClientMsgId,
+			Error:        payload.Error,
+			RetryAfterMs: payload.RetryAfterMs,
+		})
+	}
+
+	if result.Reply != "" {
+		payload, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal command result")
+		}
+
+		if err := conn.SendEvent(&chat.Event{
+			Type:      string(entities.EventCommandResult),
+			RoomId:    roomID.String(),
+			UserId:    userID.String(),
+			Timestamp: timestamppb.Now(),
+			Body:      &chat.Event_Payload{Payload: payload},
+		}); err != nil {
+			return err
+		}
+	}
+
+	ack := &chat.MessageAck{ClientMsgId: frame.ClientMsgId}
+	if result.Message != nil {
+		ack.MessageId = result.Message.ID.String()
+		ack.Timestamp = timestamppb.New(result.Message.Timestamp)
+	}
+	return s.sendAck(conn, roomID, userID, ack)
+}
+
+func (s *ChatServiceServer) runCommand(ctx context.Context, roomID, userID uuid.UUID, permissions []string, clientIP string, frame *chat.SendMessage) (*entities.CommandResult, error) {
+	parentID, err := parseOptionalID(frame.ParentId)
+	if err != nil {
+		return nil, errors.Wrap(entities.ErrInvalidParent, "invalid parent message ID")
+	}
+
+	return s.commandUC.Execute(ctx, runcommand.CommandInput{
+		RoomID:      roomID,
+		UserID:      userID,
+		Permissions: permissions,
+		Content:     frame.Content,
+		ParentID:    parentID,
+		ClientMsgID: frame.ClientMsgId,
+		ClientIP:    clientIP,
+	})
+}
+
+func (s *ChatServiceServer) sendMessage(ctx context.Context, roomID, userID uuid.UUID, clientIP string, frame *chat.SendMessage) (*entities.Message, error) {
+	parentID, err := parseOptionalID(frame.ParentId)
+	if err != nil {
+		return nil, errors.Wrap(entities.ErrInvalidParent, "invalid parent message ID")
+	}
+
+	attachmentIDs, err := parseIDs(frame.AttachmentIds)
+	if err != nil {
+		return nil, errors.Wrap(entities.ErrInvalidAttachment, "invalid attachment ID")
+	}
+
+	return s.messageUC.Execute(ctx, sendmessage.MessageInput{
+		RoomID:        roomID,
+		UserID:        userID,
+		Content:       frame.Content,
+		ParentID:      parentID,
+		AttachmentIDs: attachmentIDs,
+		ClientMsgID:   frame.ClientMsgId,
+		ClientIP:      clientIP,
+	})
+}
+
+func (s *ChatServiceServer) sendAck(conn *GRPCConnection, roomID, userID uuid.UUID, ack *chat.MessageAck) error {
+	return conn.SendEvent(&chat.Event{
+		Type:      string(entities.EventMessageAck),
+		RoomId:    roomID.String(),
+		UserId:    userID.String(),
+		Timestamp: timestamppb.Now(),
+		Body:      &chat.Event_Ack{Ack: ack},
+	})
+}
+
+// sendError notifies the stream about a failed request.
+func (s *ChatServiceServer) sendError(conn *GRPCConnection, roomID, userID uuid.UUID, message string) {
+	if err := conn.SendEvent(&chat.Event{
+		Type:      string(entities.EventError),
+		RoomId:    roomID.String(),
+		UserId:    userID.String(),
+		Timestamp: timestamppb.Now(),
+		Body:      &chat.Event_Error{Error: message},
+	}); err != nil {
+		s.logger.Error("Failed to send error event", zap.Error(err))
+	}
+}
+
+// clientIP returns the IP address of the client: the x-real-ip metadata set by a
+// trusted proxy, or the remote address of the connection.
+func (s *ChatServiceServer) clientIP(ctx context.Context) string {
+	if s.trustProxyHeaders {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("x-real-ip"); len(values) > 0 && values[0] != "" {
+				return values[0]
+			}
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// toStatusError maps domain errors to gRPC status codes.
+func (s *ChatServiceServer) toStatusError(err error, fallback string) error {
+	switch {
+	case errors.Is(err, entities.ErrRoomNotFound), errors.Is(err, entities.ErrMessageNotFound):
+		return status.Error(codes.NotFound, clientErrorMessage(err, "Room not found"))
+	case errors.Is(err, entities.ErrForbidden):
+		return status.Error(codes.PermissionDenied, clientErrorMessage(err, fallback))
+	case errors.Is(err, entities.ErrInvalidCursor), errors.Is(err, entities.ErrInvalidPresence), errors.Is(err, entities.ErrInvalidSlowMode):
+		return status.Error(codes.InvalidArgument, clientErrorMessage(err, fallback))
+	default:
+		s.logger.Error(fallback, zap.Error(err))
+		return status.Error(codes.Internal, fallback)
+	}
+}
+
+// eventToProto converts a broadcast event into its protobuf form.
+func eventToProto(event *entities.Event) (*chat.Event, error) {
+	pbEvent := &chat.Event{
+		Type:      string(event.Type),
+		RoomId:    event.RoomID.String(),
+		UserId:    event.UserID.String(),
+		Timestamp: timestamppb.New(event.Timestamp),
+		Seq:       event.Seq,
+	}
+
+	switch event.Type {
+	case entities.EventNewMessage, entities.EventMessageEdited, entities.EventMessageDeleted:
+		var msg entities.Message
+		if err := json.Unmarshal(event.Payload, &msg); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal message payload")
+		}
+		pbEvent.Body = &chat.Event_Message{Message: messageToProto(&msg)}
+
+	case entities.EventError:
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal error payload")
+		}
+		pbEvent.Body = &chat.Event_Error{Error: payload.Error}
+
+	default:
+		if len(event.Payload) > 0 {
+			pbEvent.Body = &chat.Event_Payload{Payload: event.Payload}
+		}
+	}
+
+	return pbEvent, nil
+}
+
+func presenceToProto(p entities.Presence) *chat.Presence {
+	pbPresence := &chat.Presence{
+		UserId: p.UserID.String(),
+		Status: string(p.Status),
+	}
+
+	if p.LastSeen != nil {
+		pbPresence.LastSeen = timestamppb.New(*p.LastSeen)
+	}
+
+	return pbPresence
+}
+
+func messageToProto(msg *entities.Message) *chat.Message {
+	pbMsg := &chat.Message{
+		Id:          msg.ID.String(),
+		RoomId:      msg.RoomID.String(),
+		UserId:      msg.UserID.String(),
+		Content:     msg.Content,
+		Timestamp:   timestamppb.New(msg.Timestamp),
+		ReplyCount:  int32(msg.ReplyCount),
+		ClientMsgId: msg.ClientMsgID,
+		Kind:        msg.Kind,
+		AuthorName:  msg.AuthorName,
+	}
+
+	if msg.ParentID != nil {
+		pbMsg.ParentId = msg.ParentID.String()
+	}
+	if msg.EditedAt != nil {
+		pbMsg.EditedAt = timestamppb.New(*msg.EditedAt)
+	}
+	if msg.DeletedAt != nil {
+		pbMsg.DeletedAt = timestamppb.New(*msg.DeletedAt)
+	}
+
+	for _, reaction := range msg.Reactions {
+		userIDs := make([]string, len(reaction.UserIDs))
+		for i, id := range reaction.UserIDs {
+			userIDs[i] = id.String()
+		}
+		pbMsg.Reactions = append(pbMsg.Reactions, &chat.Reaction{
+			Emoji:   reaction.Emoji,
+			Count:   int32(reaction.Count),
+			UserIds: userIDs,
+		})
+	}
+
+	for _, attachment := range msg.Attachments {
+		pbMsg.Attachments = append(pbMsg.Attachments, &chat.Attachment{
+			Id:           attachment.ID.String(),
+			FileName:     attachment.FileName,
+			ContentType:  attachment.ContentType,
+			Size:         attachment.Size,
+			Width:        int32(attachment.Width),
+			Height:       int32(attachment.Height),
+			Url:          attachment.URL,
+			ThumbnailUrl: attachment.ThumbnailURL,
+		})
+	}
+
+	return pbMsg
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/chat"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// grpcQueueSize is the number of events buffered for a gRPC client before it is disconnected.
+const grpcQueueSize = 256
+
+// GRPCConnection delivers room events over a ChatService.Connect stream. Events
+// are queued and written by the goroutine serving the stream, the only one allowed to send on it.
+type GRPCConnection struct {
+	id        uuid.UUID
+	logger    *zap.Logger
+	userID    uuid.UUID
+	roomID    uuid.UUID
+	events    chan *chat.Event
+	mu        sync.Mutex
+	closed    bool
+	closeChan chan struct{}
+}
+
+func NewGRPCConnection(logger *zap.Logger, userID, roomID uuid.UUID) *GRPCConnection {
+	return &GRPCConnection{
+		id:        uuid.New(),
+		logger:    logger,
+		userID:    userID,
+		roomID:    roomID,
+		events:    make(chan *chat.Event, grpcQueueSize),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Send converts a JSON encoded event into its protobuf form and queues it.
+func (c *GRPCConnection) Send(message []byte) error {
+	var event entities.Event
+	if err := json.Unmarshal(message, &event); err != nil {
+		return errors.Wrap(err, "failed to unmarshal event")
+	}
+
+	pbEvent, err := eventToProto(&event)
+	if err != nil {
+		return err
+	}
+
+	return c.SendEvent(pbEvent)
+}
+
+// SendEvent queues an already converted event. A client that does not keep up is disconnected.
+func (c *GRPCConnection) SendEvent(event *chat.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return entities.ErrConnectionClosed
+	}
+
+	select {
+	case c.events <- event:
+		return nil
+	default:
+		c.logger.Warn("gRPC client is too slow, closing connection",
+			zap.String("room_id", c.roomID.String()),
+			zap.String("user_id", c.userID.String()),
+		)
+		c.closeLocked()
+		return entities.ErrConnectionClosed
+	}
+}
+
+// Close marks the connection as closed. The stream itself ends when the Connect handler returns.
+func (c *GRPCConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeLocked()
+	return nil
+}
+
+func (c *GRPCConnection) closeLocked() {
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	close(c.closeChan)
+}
+
+func (c *GRPCConnection) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *GRPCConnection) Type() entities.ConnectionType {
+	return entities.GRPC
+}
+
+func (c *GRPCConnection) ID() uuid.UUID {
+	return c.id
+}
@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	getcommands "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-commands"
+	getpresence "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-presence"
+	getunreadcounts "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-unread-counts"
+	searchmessages "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/search-messages"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// HTTPHandler serves the plain HTTP endpoints of the chat service.
+type HTTPHandler struct {
+	logger        *zap.Logger
+	getUnreadUC   *getunreadcounts.UseCase
+	searchUC      *searchmessages.UseCase
+	getPresenceUC *getpresence.UseCase
+	commandsUC    *getcommands.UseCase
+	authClient    *auth.Client
+}
+
+func NewHTTPHandler(
+	logger *zap.Logger,
+	getUnreadUC *getunreadcounts.UseCase,
+	searchUC *searchmessages.UseCase,
+	getPresenceUC *getpresence.UseCase,
+	commandsUC *getcommands.UseCase,
+	authClient *auth.Client,
+) *HTTPHandler {
+	return &HTTPHandler{
+		logger:        logger,
+		getUnreadUC:   getUnreadUC,
+		searchUC:      searchUC,
+		getPresenceUC: getPresenceUC,
+		commandsUC:    commandsUC,
+		authClient:    authClient,
+	}
+}
+
+// GetUnreadCounts returns the unread message counts of the authenticated user.
+func (h *HTTPHandler) GetUnreadCounts(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.getUnreadUC.Execute(r.Context(), userInfo.UserID)
+	if err != nil {
+		h.logger.Error("Failed to get unread counts",
+			zap.Error(err),
+			zap.String("user_id", userInfo.UserID.String()),
+		)
+		http.Error(w, "Failed to get unread counts", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// SearchMessages runs a full-text search over the rooms the authenticated user has joined.
+// Supported query parameters: q, room_id, author_id, from, to (RFC 3339), limit and offset.
+func (h *HTTPHandler) SearchMessages(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	roomID, err := parseOptionalID(query.Get("room_id"))
+	if err != nil {
+		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		return
+	}
+
+	authorID, err := parseOptionalID(query.Get("author_id"))
+	if err != nil {
+		http.Error(w, "Invalid author ID", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseOptionalTime(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseOptionalTime(query.Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+
+	response, err := h.searchUC.Execute(r.Context(), searchmessages.SearchInput{
+		UserID:   userInfo.UserID,
+		Query:    query.Get("q"),
+		RoomID:   roomID,
+		AuthorID: authorID,
+		From:     from,
+		To:       to,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidSearch):
+			http.Error(w, "Invalid search request", http.StatusBadRequest)
+		case errors.Is(err, entities.ErrForbidden):
+			http.Error(w, "Not a participant of this room", http.StatusForbidden)
+		default:
+			h.logger.Error("Failed to search messages",
+				zap.Error(err),
+				zap.String("user_id", userInfo.UserID.String()),
+			)
+			http.Error(w, "Failed to search messages", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// GetPresence returns the presence of the users listed in the comma-separated user_ids query parameter.
+func (h *HTTPHandler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	var rawIDs []string
+	if raw := r.URL.Query().Get("user_ids"); raw != "" {
+		rawIDs = strings.Split(raw, ",")
+	}
+
+	userIDs, err := parseIDs(rawIDs)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.getPresenceUC.Execute(r.Context(), userIDs)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidPresence) {
+			http.Error(w, "Invalid presence request", http.StatusBadRequest)
+			return
+		}
+
+		h.logger.Error("Failed to get presence",
+			zap.Error(err),
+			zap.String("user_id", userInfo.UserID.String()),
+		)
+		http.Error(w, "Failed to get presence", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// GetCommands lists the slash commands the authenticated user may run, for help
+// and autocomplete. With the optional room_id query parameter, the commands a
+// room owner may run in their room are included.
+func (h *HTTPHandler) GetCommands(w http.ResponseWriter, r *http.Request) {
+	userInfo, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	roomID, err := parseOptionalID(r.URL.Query().Get("room_id"))
+	if err != nil {
+		http.Error(w, "Invalid room ID", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.commandsUC.Execute(r.Context(), getcommands.CommandsInput{
+		RoomID:      roomID,
+		UserID:      userInfo.UserID,
+		Permissions: userInfo.Permissions,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get commands",
+			zap.Error(err),
+			zap.String("user_id", userInfo.UserID.String()),
+		)
+		http.Error(w, "Failed to get commands", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *HTTPHandler) authenticate(w http.ResponseWriter, r *http.Request) (*auth.ValidateResponse, bool) {
+	return authenticateRequest(w, r, h.authClient)
+}
+
+func (h *HTTPHandler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	writeJSON(h.logger, w, status, v)
+}
+
+// authenticateRequest validates the access token of the request and writes an error response on failure.
+func authenticateRequest(w http.ResponseWriter, r *http.Request, authClient *auth.Client) (*auth.ValidateResponse, bool) {
+	token := extractToken(r)
+	if token == "" {
+		http.Error(w, "Authorization required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	userInfo, err := authClient.ValidateToken(r.Context(), token)
+	if err != nil {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return userInfo, true
+}
+
+func writeJSON(logger *zap.Logger, w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("Failed to write response", zap.Error(err))
+	}
+}
+
+// parseSinceSeq reads the optional since_seq query parameter of a resuming client.
+func parseSinceSeq(r *http.Request) (*int64, error) {
+	raw := r.URL.Query().Get("since_seq")
+	if raw == "" {
+		return nil, nil
+	}
+
+	sinceSeq, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || sinceSeq < 0 {
+		return nil, errors.New("invalid since_seq")
+	}
+
+	return &sinceSeq, nil
+}
+
+// clientIP returns the IP address of the client: the X-Real-IP header set by a
+// trusted proxy, or the remote address of the connection.
+func clientIP(r *http.Request, trustProxyHeaders bool) string {
+	if trustProxyHeaders {
+		if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// extractToken reads the access token from the Authorization header or the token query parameter.
+func extractToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	postincomingwebhook "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/post-incoming-webhook"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// IncomingWebhookRequest is the body POSTed to an incoming webhook. Text is
+// accepted in place of content so that payloads written for other chat
+// services work unchanged.
+type IncomingWebhookRequest struct {
+	Content     string `json:"content"`
+	Text        string `json:"text"`
+	ClientMsgID string `json:"client_msg_id"`
+}
+
+// IncomingWebhookResponse tells an integration which message was stored.
+type IncomingWebhookResponse struct {
+	MessageID string    `json:"message_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IncomingWebhookHandler lets integrations post messages to a room with the
+// secret token of an incoming webhook instead of an access token.
+type IncomingWebhookHandler struct {
+	logger            *zap.Logger
+	postUC            *postincomingwebhook.UseCase
+	maxBodySize       int64
+	trustProxyHeaders bool
+}
+
+func NewIncomingWebhookHandler(
+	logger *zap.Logger,
+	postUC *postincomingwebhook.UseCase,
+	maxBodySize int64,
+	trustProxyHeaders bool,
+) *IncomingWebhookHandler {
+	return &IncomingWebhookHandler{
+		logger:            logger,
+		postUC:            postUC,
+		maxBodySize:       maxBodySize,
+		trustProxyHeaders: trustProxyHeaders,
+	}
+}
+
+// PostMessage posts the message of an integration to the room of the webhook
+// its token belongs to. Errors are answered with the payload of WebSocket
+// error events; rejections with a retry delay also set Retry-After.
+func (h *IncomingWebhookHandler) PostMessage(w http.ResponseWriter, r *http.Request) {
+	var req IncomingWebhookRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodySize)).Decode(&req); err != nil {
+		writeJSON(h.logger, w, http.StatusBadRequest, entities.ErrorPayload{Error: "Invalid message"})
+		return
+	}
+
+	content := req.Content
+	if content == "" {
+		content = req.Text
+	}
+
+	msg, err := h.postUC.Execute(r.Context(), postincomingwebhook.IncomingWebhookInput{
+		Token:       mux.Vars(r)["token"],
+		Content:     content,
+		ClientMsgID: req.ClientMsgID,
+		ClientIP:    clientIP(r, h.trustProxyHeaders),
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	writeJSON(h.logger, w, http.StatusOK, IncomingWebhookResponse{
+		MessageID: msg.ID.String(),
+		Timestamp: msg.Timestamp,
+	})
+}
+
+func (h *IncomingWebhookHandler) writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, entities.ErrIntegrationNotFound) {
+		writeJSON(h.logger, w, http.StatusNotFound, entities.ErrorPayload{Error: "Incoming webhook not found"})
+		return
+	}
+
+	payload := clientErrorPayload(err, "Failed to post message")
+	if payload.RetryAfterMs > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(float64(payload.RetryAfterMs)/1000)), 10))
+	}
+
+	var status int
+	switch {
+	case errors.Is(err, entities.ErrRateLimited):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, entities.ErrMuted), errors.Is(err, entities.ErrMessageRejected):
+		status = http.StatusForbidden
+	case errors.Is(err, entities.ErrInvalidMessage):
+		status = http.StatusBadRequest
+	default:
+		h.logger.Error("Failed to post incoming webhook message", zap.Error(err))
+		status = http.StatusInternalServerError
+	}
+
+	writeJSON(h.logger, w, status, payload)
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// longPollQueueSize is the number of events kept for a long-poll client until it
+// acknowledges them.
+const longPollQueueSize = 256
+
+// LongPollConnection buffers room events until the client acknowledges them.
+// Events are numbered from 0 in the order they were queued; a poll returns the
+// unacknowledged events and the number of the event after them, its cursor.
+type LongPollConnection struct {
+	sessionID uuid.UUID
+	logger    *zap.Logger
+	userID    uuid.UUID
+	roomID    uuid.UUID
+	mu        sync.Mutex
+	queue     [][]byte
+	acked     int64 // Number of the first event of queue.
+	notify    chan struct{}
+	lastPoll  time.Time
+	closed    bool
+	closeChan chan struct{}
+}
+
+func NewLongPollConnection(logger *zap.Logger, userID, roomID uuid.UUID) *LongPollConnection {
+	return &LongPollConnection{
+		sessionID: uuid.New(),
+		logger:    logger,
+		userID:    userID,
+		roomID:    roomID,
+		notify:    make(chan struct{}, 1),
+		lastPoll:  time.Now(),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Send queues an event for the next poll. A client that lets the queue
+// overflow is disconnected and has to reload history after reconnecting.
+func (c *LongPollConnection) Send(message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return entities.ErrConnectionClosed
+	}
+
+	if len(c.queue) >= longPollQueueSize {
+		c.logger.Warn("Long-poll queue overflow, closing connection",
+			zap.String("room_id", c.roomID.String()),
+			zap.String("user_id", c.userID.String()),
+		)
+		c.closeLocked()
+		return entities.ErrConnectionClosed
+	}
+
+	c.queue = append(c.queue, message)
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Poll acknowledges the events before ack, when given, then waits until an
+// event is queued, the timeout elapses or the connection is closed. It returns
+// the unacknowledged events, which stay queued until acknowledged, and the
+// cursor that acknowledges them.
+func (c *LongPollConnection) Poll(ctx context.Context, timeout time.Duration, ack *int64) ([][]byte, int64, error) {
+	c.touch()
+	defer c.touch()
+
+	if ack != nil {
+		c.Ack(*ack)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		events, cursor := c.pending()
+		if len(events) > 0 {
+			return events, cursor, nil
+		}
+
+		select {
+		case <-c.notify:
+		case <-timer.C:
+			return nil, cursor, nil
+		case <-ctx.Done():
+			return nil, cursor, nil
+		case <-c.closeChan:
+			return nil, cursor, entities.ErrConnectionClosed
+		}
+	}
+}
+
+// Ack drops the queued events before cursor.
+func (c *LongPollConnection) Ack(cursor int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := min(cursor-c.acked, int64(len(c.queue)))
+	if n <= 0 {
+		return
+	}
+	c.queue = c.queue[n:]
+	c.acked += n
+}
+
+// IdleSince reports when the client last polled.
+func (c *LongPollConnection) IdleSince() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastPoll
+}
+
+func (c *LongPollConnection) pending() ([][]byte, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events := append([][]byte(nil), c.queue...)
+	return events, c.acked + int64(len(events))
+}
+
+func (c *LongPollConnection) touch() {
+	c.mu.Lock()
+	c.lastPoll = time.Now()
+	c.mu.Unlock()
+}
+
+func (c *LongPollConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeLocked()
+	return nil
+}
+
+func (c *LongPollConnection) closeLocked() {
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	close(c.closeChan)
+}
+
+func (c *LongPollConnection) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *LongPollConnection) Type() entities.ConnectionType {
+	return entities.LongPoll
+}
+
+func (c *LongPollConnection) ID() uuid.UUID {
+	return c.sessionID
+}
+
+func (c *LongPollConnection) SessionID() uuid.UUID {
+	return c.sessionID
+}
+
+func (c *LongPollConnection) Done() <-chan struct{} {
+	return c.closeChan
+}
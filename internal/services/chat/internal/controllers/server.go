@@ -2,36 +2,84 @@ package controllers
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/chat"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/config"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 type Server struct {
-	logger     *zap.Logger
-	config     *config.Config
-	httpServer *http.Server
-	wsHandler  *WebSocketHandler
+	logger        *zap.Logger
+	config        *config.Config
+	httpServer    *http.Server
+	grpcServer    *grpc.Server
+	metricsServer *http.Server
+	wsHandler     *WebSocketHandler
+	httpHandler   *HTTPHandler
+	fallback      *FallbackHandler
+	hooks         *IncomingWebhookHandler
+	attachments   *AttachmentHandler
+	export        *ExportHandler
+	chatServer    *ChatServiceServer
 }
 
 func NewServer(
 	logger *zap.Logger,
 	config *config.Config,
 	wsHandler *WebSocketHandler,
+	httpHandler *HTTPHandler,
+	fallback *FallbackHandler,
+	hooks *IncomingWebhookHandler,
+	attachments *AttachmentHandler,
+	export *ExportHandler,
+	chatServer *ChatServiceServer,
 ) *Server {
 	return &Server{
-		logger:    logger,
-		config:    config,
-		wsHandler: wsHandler,
+		logger:      logger,
+		config:      config,
+		wsHandler:   wsHandler,
+		httpHandler: httpHandler,
+		fallback:    fallback,
+		hooks:       hooks,
+		attachments: attachments,
+		export:      export,
+		chatServer:  chatServer,
 	}
 }
 
 func (s *Server) Start(ctx context.Context) error {
 	router := mux.NewRouter()
 	router.HandleFunc("/ws/chat/{roomID}", s.wsHandler.ServeWS)
+	router.HandleFunc("/api/v1/chat/unread", s.httpHandler.GetUnreadCounts).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/chat/search", s.httpHandler.SearchMessages).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/chat/presence", s.httpHandler.GetPresence).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/chat/commands", s.httpHandler.GetCommands).Methods(http.MethodGet)
+
+	// Fallback transports for clients that cannot open a WebSocket.
+	router.HandleFunc("/api/v1/chat/transports", s.fallback.Transports).Methods(http.MethodGet)
+	router.HandleFunc("/sse/chat/{roomID}", s.fallback.ServeSSE).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/chat/rooms/{roomID}/poll", s.fallback.OpenLongPoll).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/chat/sessions/{sessionID}/events", s.fallback.Poll).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/chat/sessions/{sessionID}/messages", s.fallback.SendMessage).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/chat/sessions/{sessionID}", s.fallback.CloseSession).Methods(http.MethodDelete)
+
+	// Incoming webhooks authenticate with the token in their URL.
+	router.HandleFunc("/api/v1/chat/hooks/{token}", s.hooks.PostMessage).Methods(http.MethodPost)
+
+	// Attachments are downloaded through signed URLs, without an access token.
+	router.HandleFunc("/api/v1/chat/rooms/{roomID}/attachments", s.attachments.Upload).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/chat/attachments/{attachmentID}", s.attachments.Download).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/chat/attachments/{attachmentID}/{variant:thumbnail}", s.attachments.Download).Methods(http.MethodGet)
+
+	router.HandleFunc("/api/v1/chat/rooms/{roomID}/export", s.export.Export).Methods(http.MethodGet)
 
 	s.httpServer = &http.Server{
 		Addr:         s.config.Handlers.HTTP.FullAddress(),
@@ -47,9 +95,74 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
+	s.startMetricsServer()
+
+	return s.startGRPCServer()
+}
+
+func (s *Server) startMetricsServer() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	s.metricsServer = &http.Server{
+		Addr:    s.config.Engines.Metrics.Address,
+		Handler: mux,
+	}
+
+	go func() {
+		s.logger.Info("Starting metrics server", zap.String("address", s.config.Engines.Metrics.Address))
+		if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Metrics server error", zap.Error(err))
+		}
+	}()
+}
+
+func (s *Server) startGRPCServer() error {
+	addr := s.config.Handlers.GRPC.FullAddress()
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to create gRPC listener")
+	}
+
+	// Chat streams are long-lived, so only idle connections are recycled. Client
+	// frames are bounded like WebSocket messages.
+	s.grpcServer = grpc.NewServer(
+		grpc.MaxRecvMsgSize(int(s.config.WebSocket.MaxMessageSize)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: 5 * time.Minute,
+			Time:              30 * time.Second,
+			Timeout:           10 * time.Second,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+	)
+	chat.RegisterChatServiceServer(s.grpcServer, s.chatServer)
+
+	go func() {
+		s.logger.Info("Starting gRPC server", zap.String("address", addr))
+		if err := s.grpcServer.Serve(listener); err != nil {
+			s.logger.Fatal("gRPC server error", zap.Error(err))
+		}
+	}()
+
 	return nil
 }
 
 func (s *Server) Stop(ctx context.Context) error {
+	// GracefulStop would wait for every Connect stream to be closed by its client.
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+
+	s.fallback.Close()
+
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			s.logger.Error("Failed to shutdown metrics server", zap.Error(err))
+		}
+	}
+
 	return s.httpServer.Shutdown(ctx)
 }
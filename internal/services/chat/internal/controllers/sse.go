@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"sync"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// sseQueueSize is the number of events buffered for an SSE client before it is disconnected.
+const sseQueueSize = 64
+
+// SSEConnection delivers room events as Server-Sent Events. Events are queued
+// and written by the goroutine serving the SSE request, which owns the response writer.
+type SSEConnection struct {
+	sessionID uuid.UUID
+	logger    *zap.Logger
+	userID    uuid.UUID
+	roomID    uuid.UUID
+	events    chan []byte
+	mu        sync.Mutex
+	closed    bool
+	closeChan chan struct{}
+}
+
+func NewSSEConnection(logger *zap.Logger, userID, roomID uuid.UUID) *SSEConnection {
+	return &SSEConnection{
+		sessionID: uuid.New(),
+		logger:    logger,
+		userID:    userID,
+		roomID:    roomID,
+		events:    make(chan []byte, sseQueueSize),
+		closeChan: make(chan struct{}),
+	}
+}
+
+// Send queues an event. A client that does not keep up is disconnected
+// rather than blocking the broadcast.
+func (c *SSEConnection) Send(message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return entities.ErrConnectionClosed
+	}
+
+	select {
+	case c.events <- message:
+		return nil
+	default:
+		c.logger.Warn("SSE client is too slow, closing connection",
+			zap.String("room_id", c.roomID.String()),
+			zap.String("user_id", c.userID.String()),
+		)
+		c.closeLocked()
+		return entities.ErrConnectionClosed
+	}
+}
+
+func (c *SSEConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeLocked()
+	return nil
+}
+
+func (c *SSEConnection) closeLocked() {
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+	close(c.closeChan)
+}
+
+func (c *SSEConnection) IsClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *SSEConnection) Type() entities.ConnectionType {
+	return entities.SSE
+}
+
+func (c *SSEConnection) ID() uuid.UUID {
+	return c.sessionID
+}
+
+func (c *SSEConnection) SessionID() uuid.UUID {
+	return c.sessionID
+}
+
+func (c *SSEConnection) Done() <-chan struct{} {
+	return c.closeChan
+}
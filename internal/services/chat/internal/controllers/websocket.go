@@ -7,11 +7,26 @@ import (
 	"time"
 
 	"github.com/HexArch/go-chat/internal/services/chat/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/config"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	addreaction "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/add-reaction"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/connect"
+	deletemessage "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/delete-message"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/disconnect"
+	editmessage "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/edit-message"
+	getcommands "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-commands"
 	getmessages "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-messages"
+	getthread "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-thread"
+	getunreadcounts "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/get-unread-counts"
+	markread "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/mark-read"
+	removereaction "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/remove-reaction"
+	runcommand "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/run-command"
+	searchmessages "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/search-messages"
 	sendmessage "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/send-message"
+	setpresence "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/set-presence"
+	setslowmode "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/set-slow-mode"
+	subscribepresence "github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/subscribe-presence"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/use-cases/typing"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -22,28 +37,77 @@ import (
 // WebSocketHandler handles WebSocket connections
 type WebSocketHandler struct {
 	logger        *zap.Logger
+	config        config.WebSocketConfig
 	connectUC     *connect.UseCase
 	disconnectUC  *disconnect.UseCase
 	messageUC     *sendmessage.UseCase
 	getMessagesUC *getmessages.UseCase
+	getThreadUC   *getthread.UseCase
+	editUC        *editmessage.UseCase
+	deleteUC      *deletemessage.UseCase
+	addReactUC    *addreaction.UseCase
+	removeReactUC *removereaction.UseCase
+	typingUC      *typing.UseCase
+	markReadUC    *markread.UseCase
+	getUnreadUC   *getunreadcounts.UseCase
+	searchUC      *searchmessages.UseCase
+	presenceUC    *setpresence.UseCase
+	subscribeUC   *subscribepresence.UseCase
+	slowModeUC    *setslowmode.UseCase
+	commandUC     *runcommand.UseCase
+	commandsUC    *getcommands.UseCase
 	authClient    *auth.Client
 	upgrader      websocket.Upgrader
+
+	// trustProxyHeaders reads client IPs from the X-Real-IP header of the proxy.
+	trustProxyHeaders bool
 }
 
 func NewWebSocketHandler(
 	logger *zap.Logger,
+	cfg config.WebSocketConfig,
 	connectUC *connect.UseCase,
 	disconnectUC *disconnect.UseCase,
 	messageUC *sendmessage.UseCase,
 	getMessagesUC *getmessages.UseCase,
+	getThreadUC *getthread.UseCase,
+	editUC *editmessage.UseCase,
+	deleteUC *deletemessage.UseCase,
+	addReactUC *addreaction.UseCase,
+	removeReactUC *removereaction.UseCase,
+	typingUC *typing.UseCase,
+	markReadUC *markread.UseCase,
+	getUnreadUC *getunreadcounts.UseCase,
+	searchUC *searchmessages.UseCase,
+	presenceUC *setpresence.UseCase,
+	subscribeUC *subscribepresence.UseCase,
+	slowModeUC *setslowmode.UseCase,
+	commandUC *runcommand.UseCase,
+	commandsUC *getcommands.UseCase,
 	authClient *auth.Client,
+	trustProxyHeaders bool,
 ) *WebSocketHandler {
 	return &WebSocketHandler{
 		logger:        logger,
+		config:        cfg,
 		connectUC:     connectUC,
 		disconnectUC:  disconnectUC,
 		messageUC:     messageUC,
 		getMessagesUC: getMessagesUC,
+		getThreadUC:   getThreadUC,
+		editUC:        editUC,
+		deleteUC:      deleteUC,
+		addReactUC:    addReactUC,
+		removeReactUC: removeReactUC,
+		typingUC:      typingUC,
+		markReadUC:    markReadUC,
+		getUnreadUC:   getUnreadUC,
+		searchUC:      searchUC,
+		presenceUC:    presenceUC,
+		subscribeUC:   subscribeUC,
+		slowModeUC:    slowModeUC,
+		commandUC:     commandUC,
+		commandsUC:    commandsUC,
 		authClient:    authClient,
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
@@ -52,6 +116,7 @@ func NewWebSocketHandler(
 				return true
 			},
 		},
+		trustProxyHeaders: trustProxyHeaders,
 	}
 }
 
@@ -78,6 +143,12 @@ func (h *WebSocketHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sinceSeq, err := parseSinceSeq(r)
+	if err != nil {
+		http.Error(w, "Invalid since_seq", http.StatusBadRequest)
+		return
+	}
+
 	h.logger.Debug("Processing connection",
 		zap.String("room_id", roomID.String()),
 		zap.String("user_id", userInfo.UserID.String()),
@@ -89,7 +160,7 @@ func (h *WebSocketHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn := NewWebSocketConnection(wsConn, h.logger, userInfo.UserID, roomID)
+	conn := NewWebSocketConnection(wsConn, h.logger, h.config, userInfo.UserID, roomID)
 
 	connectEvent := &entities.Event{
 		Type:      entities.EventUserConnected,
@@ -103,13 +174,22 @@ func (h *WebSocketHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		UserID:     userInfo.UserID,
 		Connection: conn,
 		Event:      connectEvent,
+		SinceSeq:   sinceSeq,
 	}); err != nil {
-		h.logger.Error("Failed to connect to room", zap.Error(err))
+		if errors.Is(err, entities.ErrForbidden) || errors.Is(err, entities.ErrRoomNotFound) {
+			h.logger.Debug("Connection to room rejected",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userInfo.UserID.String()),
+			)
+		} else {
+			h.logger.Error("Failed to connect to room", zap.Error(err))
+		}
 		conn.Close()
 		return
 	}
 
-	go h.handleMessages(conn, roomID, userInfo.UserID)
+	go h.handleMessages(conn, roomID, userInfo.UserID, userInfo.Permissions, clientIP(r, h.trustProxyHeaders))
 
 	defer func() {
 		disconnectEvent := &entities.Event{
@@ -120,9 +200,10 @@ func (h *WebSocketHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if err := h.disconnectUC.Execute(context.Background(), disconnect.DisconnectInput{
-			RoomID: roomID,
-			UserID: userInfo.UserID,
-			Event:  disconnectEvent,
+			RoomID:       roomID,
+			UserID:       userInfo.UserID,
+			ConnectionID: conn.ID(),
+			Event:        disconnectEvent,
 		}); err != nil {
 			h.logger.Error("Failed to disconnect", zap.Error(err))
 		}
@@ -133,7 +214,7 @@ func (h *WebSocketHandler) ServeWS(w http.ResponseWriter, r *http.Request) {
 	<-conn.closeChan
 }
 
-func (h *WebSocketHandler) handleMessages(conn *WebSocketConnection, roomID, userID uuid.UUID) {
+func (h *WebSocketHandler) handleMessages(conn *WebSocketConnection, roomID, userID uuid.UUID, permissions []string, clientIP string) {
 	defer conn.Close()
 
 	for {
@@ -141,7 +222,7 @@ func (h *WebSocketHandler) handleMessages(conn *WebSocketConnection, roomID, use
 		case <-conn.closeChan:
 			return
 		default:
-			messageType, message, err := conn.conn.ReadMessage()
+			messageType, message, err := conn.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					h.logger.Error("WebSocket read error",
@@ -167,79 +248,312 @@ func (h *WebSocketHandler) handleMessages(conn *WebSocketConnection, roomID, use
 				continue
 			}
 
-			switch msg.Type {
-			case "message":
-				// Создаем новое сообщение
-				newMessage := &entities.Message{
-					ID:        uuid.New(),
-					RoomID:    roomID,
-					UserID:    userID,
-					Content:   msg.Content,
-					Timestamp: time.Now(),
-				}
-
-				// Сериализуем сообщение для payload
-				messageJSON, err := json.Marshal(newMessage)
-				if err != nil {
-					h.logger.Error("Failed to marshal message", zap.Error(err))
-					continue
-				}
+			h.handleClientMessage(conn, roomID, userID, permissions, clientIP, msg)
+		}
+	}
+}
 
-				// Создаем событие нового сообщения
-				messageEvent := &entities.Event{
-					Type:      entities.EventNewMessage,
-					RoomID:    roomID,
-					UserID:    userID,
-					Payload:   messageJSON,
-					Timestamp: time.Now(),
-				}
+// handleClientMessage dispatches a client frame. It is shared by every transport
+// that speaks the WebSocket message format. The permissions of the user decide
+// which slash commands they may run; the client IP feeds the per-IP rate limit.
+func (h *WebSocketHandler) handleClientMessage(conn entities.Connection, roomID, userID uuid.UUID, permissions []string, clientIP string, msg WebSocketMessage) {
+	switch msg.Type {
+	case "message":
+		parentID, err := parseOptionalID(msg.ParentID)
+		if err != nil {
+			h.sendMessageError(conn, roomID, userID, msg.ClientMsgID, entities.ErrorPayload{Error: "Invalid parent message ID"})
+			return
+		}
 
-				// Отправляем сообщение через use case с событием
-				if err := h.messageUC.Execute(context.Background(), sendmessage.MessageInput{
-					RoomID:  roomID,
-					UserID:  userID,
-					Content: msg.Content,
-					Event:   messageEvent,
-				}); err != nil {
-					h.logger.Error("Failed to handle message",
-						zap.Error(err),
-						zap.String("room_id", roomID.String()),
-						zap.String("user_id", userID.String()),
-					)
+		attachmentIDs, err := parseIDs(msg.AttachmentIDs)
+		if err != nil {
+			h.sendMessageError(conn, roomID, userID, msg.ClientMsgID, entities.ErrorPayload{Error: "Invalid attachment ID"})
+			return
+		}
 
-					// Отправляем уведомление об ошибке отправителю
-					errorEvent := &entities.Event{
-						Type:      entities.EventError,
-						RoomID:    roomID,
-						UserID:    userID,
-						Timestamp: time.Now(),
-						Payload:   []byte(`{"error":"Failed to send message"}`),
-					}
-
-					if err := conn.Send(mustMarshal(errorEvent)); err != nil {
-						h.logger.Error("Failed to send error event", zap.Error(err))
-					}
-					continue
-				}
+		if len(attachmentIDs) == 0 && runcommand.IsCommand(msg.Content) {
+			h.handleCommand(conn, roomID, userID, permissions, clientIP, parentID, msg)
+			return
+		}
 
-			case "get_history":
-				if err := h.handleHistoryRequest(conn, roomID, userID, msg.Limit, msg.Offset); err != nil {
-					h.logger.Error("Failed to handle history request",
-						zap.Error(err),
-						zap.String("room_id", roomID.String()),
-						zap.String("user_id", userID.String()),
-					)
-				}
+		stored, err := h.messageUC.Execute(context.Background(), sendmessage.MessageInput{
+			RoomID:        roomID,
+			UserID:        userID,
+			Content:       msg.Content,
+			ParentID:      parentID,
+			AttachmentIDs: attachmentIDs,
+			ClientMsgID:   msg.ClientMsgID,
+			ClientIP:      clientIP,
+		})
+		if err != nil {
+			if isRefusal(err) {
+				h.logger.Debug("Message refused",
+					zap.Error(err),
+					zap.String("room_id", roomID.String()),
+					zap.String("user_id", userID.String()),
+				)
+			} else {
+				h.logger.Error("Failed to handle message",
+					zap.Error(err),
+					zap.String("room_id", roomID.String()),
+					zap.String("user_id", userID.String()),
+				)
 			}
+			h.sendMessageError(conn, roomID, userID, msg.ClientMsgID, clientErrorPayload(err, "Failed to send message"))
+			return
+		}
+
+		h.sendAck(conn, roomID, userID, entities.MessageAck{
+			ClientMsgID: msg.ClientMsgID,
+			MessageID:   &stored.ID,
+			Timestamp:   &stored.Timestamp,
+		})
+
+	case "get_thread":
+		if err := h.handleThreadRequest(conn, roomID, userID, msg.ParentID, msg.Limit, msg.Offset); err != nil {
+			h.logger.Error("Failed to handle thread request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to get thread"))
+		}
+
+	case "edit_message":
+		if err := h.handleEditRequest(roomID, userID, msg.MessageID, msg.Content); err != nil {
+			h.logger.Error("Failed to handle edit request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to edit message"))
+		}
+
+	case "delete_message":
+		if err := h.handleDeleteRequest(roomID, userID, msg.MessageID); err != nil {
+			h.logger.Error("Failed to handle delete request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to delete message"))
+		}
+
+	case "typing_started", "typing_stopped":
+		// Typing indicators are best-effort: failures are only logged.
+		if err := h.typingUC.Execute(context.Background(), typing.TypingInput{
+			RoomID: roomID,
+			UserID: userID,
+			Typing: msg.Type == "typing_started",
+		}); err != nil {
+			h.logger.Debug("Failed to handle typing indicator",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+		}
+
+	case "mark_read":
+		if err := h.handleMarkReadRequest(roomID, userID, msg.MessageID); err != nil {
+			h.logger.Error("Failed to handle mark read request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to mark messages as read"))
+		}
+
+	case "get_commands":
+		if err := h.handleCommandsRequest(conn, roomID, userID, permissions); err != nil {
+			h.logger.Error("Failed to handle commands request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, "Failed to get commands")
+		}
+
+	case "get_unread_counts":
+		if err := h.handleUnreadRequest(conn, roomID, userID); err != nil {
+			h.logger.Error("Failed to handle unread counts request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, "Failed to get unread counts")
+		}
+
+	case "search":
+		if err := h.handleSearchRequest(conn, roomID, userID, msg); err != nil {
+			h.logger.Error("Failed to handle search request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to search messages"))
+		}
+
+	case "presence":
+		if err := h.presenceUC.Execute(context.Background(), setpresence.PresenceInput{
+			UserID:       userID,
+			ConnectionID: conn.ID(),
+			Status:       entities.PresenceStatus(msg.Status),
+		}); err != nil {
+			h.logger.Debug("Failed to handle presence heartbeat",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to update presence"))
+		}
+
+	case "presence_subscribe", "presence_unsubscribe":
+		if err := h.handlePresenceSubscription(conn, roomID, userID, msg); err != nil {
+			h.logger.Error("Failed to handle presence subscription",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to update presence subscription"))
+		}
+
+	case "add_reaction", "remove_reaction":
+		if err := h.handleReactionRequest(roomID, userID, msg.Type, msg.MessageID, msg.Emoji); err != nil {
+			h.logger.Error("Failed to handle reaction request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to update reaction"))
+		}
+
+	case "set_slow_mode":
+		if err := h.slowModeUC.Execute(context.Background(), setslowmode.SlowModeInput{
+			RoomID:  roomID,
+			UserID:  userID,
+			Seconds: msg.Seconds,
+		}); err != nil {
+			h.logger.Debug("Failed to set slow mode",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to set slow mode"))
+		}
+
+	case "get_history":
+		if err := h.handleHistoryRequest(conn, roomID, userID, msg.Limit, msg.Before, msg.After); err != nil {
+			h.logger.Error("Failed to handle history request",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+			h.sendError(conn, roomID, userID, clientErrorMessage(err, "Failed to get history"))
+		}
+	}
+}
+
+// handleCommand runs the slash command of a message frame. The command's reply
+// goes to this connection only; the ack carries the message the command posted, if any.
+func (h *WebSocketHandler) handleCommand(conn entities.Connection, roomID, userID uuid.UUID, permissions []string, clientIP string, parentID *uuid.UUID, msg WebSocketMessage) {
+	result, err := h.commandUC.Execute(context.Background(), runcommand.CommandInput{
+		RoomID:      roomID,
+		UserID:      userID,
+		Permissions: permissions,
+		Content:     msg.Content,
+		ParentID:    parentID,
+		ClientMsgID: msg.ClientMsgID,
+		ClientIP:    clientIP,
+	})
+	if err != nil {
+		if isRefusal(err) || isCommandError(err) {
+			h.logger.Debug("Command refused",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+		} else {
+			h.logger.Error("Failed to run command",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("user_id", userID.String()),
+			)
+		}
+		h.sendMessageError(conn, roomID, userID, msg.ClientMsgID, clientErrorPayload(err, "Failed to run command"))
+		return
+	}
+
+	if result.Reply != "" {
+		eventJSON, err := json.Marshal(&entities.Event{
+			Type:      entities.EventCommandResult,
+			RoomID:    roomID,
+			UserID:    userID,
+			Payload:   mustMarshal(result),
+			Timestamp: time.Now(),
+		})
+		if err == nil {
+			err = conn.Send(eventJSON)
 		}
+		if err != nil {
+			h.logger.Debug("Failed to send command result", zap.Error(err))
+		}
+	}
+
+	ack := entities.MessageAck{ClientMsgID: msg.ClientMsgID}
+	if result.Message != nil {
+		ack.MessageID = &result.Message.ID
+		ack.Timestamp = &result.Message.Timestamp
+	}
+	h.sendAck(conn, roomID, userID, ack)
+}
+
+// handleCommandsRequest lists the slash commands the user may run in the room.
+func (h *WebSocketHandler) handleCommandsRequest(conn entities.Connection, roomID, userID uuid.UUID, permissions []string) error {
+	response, err := h.commandsUC.Execute(context.Background(), getcommands.CommandsInput{
+		RoomID:      &roomID,
+		UserID:      userID,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return err
+	}
+
+	commandsJSON, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal commands")
+	}
+
+	eventJSON, err := json.Marshal(&entities.Event{
+		Type:      entities.EventCommands,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   commandsJSON,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal commands event")
 	}
+
+	return conn.Send(eventJSON)
 }
 
-func (h *WebSocketHandler) handleHistoryRequest(conn *WebSocketConnection, roomID, userID uuid.UUID, limit, offset int) error {
+func (h *WebSocketHandler) handleHistoryRequest(conn entities.Connection, roomID, userID uuid.UUID, limit int, rawBefore, rawAfter string) error {
+	before, err := entities.ParseMessageCursor(rawBefore)
+	if err != nil {
+		return err
+	}
+
+	after, err := entities.ParseMessageCursor(rawAfter)
+	if err != nil {
+		return err
+	}
+
 	response, err := h.getMessagesUC.Execute(context.Background(), getmessages.MessagesInput{
 		RoomID: roomID,
+		UserID: userID,
 		Limit:  limit,
-		Offset: offset,
+		Before: before,
+		After:  after,
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to get messages history")
@@ -266,6 +580,419 @@ func (h *WebSocketHandler) handleHistoryRequest(conn *WebSocketConnection, roomI
 	return conn.Send(eventJSON)
 }
 
+func (h *WebSocketHandler) handleThreadRequest(conn entities.Connection, roomID, userID uuid.UUID, rawParentID string, limit, offset int) error {
+	parentID, err := uuid.Parse(rawParentID)
+	if err != nil {
+		return errors.Wrap(entities.ErrInvalidParent, "invalid parent message ID")
+	}
+
+	response, err := h.getThreadUC.Execute(context.Background(), getthread.ThreadInput{
+		RoomID:   roomID,
+		ParentID: parentID,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get thread")
+	}
+
+	threadJSON, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal thread response")
+	}
+
+	threadEvent := &entities.Event{
+		Type:      entities.EventThreadHistory,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   threadJSON,
+		Timestamp: time.Now(),
+	}
+
+	eventJSON, err := json.Marshal(threadEvent)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal thread event")
+	}
+
+	return conn.Send(eventJSON)
+}
+
+func (h *WebSocketHandler) handleEditRequest(roomID, userID uuid.UUID, rawMessageID, content string) error {
+	messageID, err := uuid.Parse(rawMessageID)
+	if err != nil {
+		return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+	}
+
+	_, err = h.editUC.Execute(context.Background(), editmessage.EditInput{
+		RoomID:    roomID,
+		UserID:    userID,
+		MessageID: messageID,
+		Content:   content,
+	})
+	return err
+}
+
+func (h *WebSocketHandler) handleDeleteRequest(roomID, userID uuid.UUID, rawMessageID string) error {
+	messageID, err := uuid.Parse(rawMessageID)
+	if err != nil {
+		return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+	}
+
+	return h.deleteUC.Execute(context.Background(), deletemessage.DeleteInput{
+		RoomID:    roomID,
+		UserID:    userID,
+		MessageID: messageID,
+	})
+}
+
+func (h *WebSocketHandler) handleReactionRequest(roomID, userID uuid.UUID, action, rawMessageID, emoji string) error {
+	messageID, err := uuid.Parse(rawMessageID)
+	if err != nil {
+		return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+	}
+
+	if action == "remove_reaction" {
+		return h.removeReactUC.Execute(context.Background(), removereaction.ReactionInput{
+			RoomID:    roomID,
+			UserID:    userID,
+			MessageID: messageID,
+			Emoji:     emoji,
+		})
+	}
+
+	return h.addReactUC.Execute(context.Background(), addreaction.ReactionInput{
+		RoomID:    roomID,
+		UserID:    userID,
+		MessageID: messageID,
+		Emoji:     emoji,
+	})
+}
+
+func (h *WebSocketHandler) handleMarkReadRequest(roomID, userID uuid.UUID, rawMessageID string) error {
+	messageID, err := uuid.Parse(rawMessageID)
+	if err != nil {
+		return errors.Wrap(entities.ErrMessageNotFound, "invalid message ID")
+	}
+
+	return h.markReadUC.Execute(context.Background(), markread.MarkReadInput{
+		RoomID:    roomID,
+		UserID:    userID,
+		MessageID: messageID,
+	})
+}
+
+func (h *WebSocketHandler) handleUnreadRequest(conn entities.Connection, roomID, userID uuid.UUID) error {
+	response, err := h.getUnreadUC.Execute(context.Background(), userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get unread counts")
+	}
+
+	unreadJSON, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal unread counts")
+	}
+
+	eventJSON, err := json.Marshal(&entities.Event{
+		Type:      entities.EventUnreadCounts,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   unreadJSON,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal unread counts event")
+	}
+
+	return conn.Send(eventJSON)
+}
+
+// handleSearchRequest searches the messages of the room the connection belongs to.
+func (h *WebSocketHandler) handleSearchRequest(conn entities.Connection, roomID, userID uuid.UUID, msg WebSocketMessage) error {
+	authorID, err := parseOptionalID(msg.AuthorID)
+	if err != nil {
+		return errors.Wrap(entities.ErrInvalidSearch, "invalid author ID")
+	}
+
+	from, err := parseOptionalTime(msg.From)
+	if err != nil {
+		return errors.Wrap(entities.ErrInvalidSearch, "invalid from date")
+	}
+
+	to, err := parseOptionalTime(msg.To)
+	if err != nil {
+		return errors.Wrap(entities.ErrInvalidSearch, "invalid to date")
+	}
+
+	response, err := h.searchUC.Execute(context.Background(), searchmessages.SearchInput{
+		UserID:   userID,
+		Query:    msg.Query,
+		RoomID:   &roomID,
+		AuthorID: authorID,
+		From:     from,
+		To:       to,
+		Limit:    msg.Limit,
+		Offset:   msg.Offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	resultsJSON, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal search results")
+	}
+
+	eventJSON, err := json.Marshal(&entities.Event{
+		Type:      entities.EventSearchResults,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   resultsJSON,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal search event")
+	}
+
+	return conn.Send(eventJSON)
+}
+
+// handlePresenceSubscription updates the presence subscriptions of the connection.
+// Subscribing replies with one presence event per requested user.
+func (h *WebSocketHandler) handlePresenceSubscription(conn entities.Connection, roomID, userID uuid.UUID, msg WebSocketMessage) error {
+	userIDs, err := parseIDs(msg.UserIDs)
+	if err != nil {
+		return errors.Wrap(entities.ErrInvalidPresence, "invalid user ID")
+	}
+
+	presence, err := h.subscribeUC.Execute(context.Background(), subscribepresence.SubscribeInput{
+		Connection:  conn,
+		UserIDs:     userIDs,
+		Unsubscribe: msg.Type == "presence_unsubscribe",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range presence {
+		payload, err := json.Marshal(p)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal presence")
+		}
+
+		eventJSON, err := json.Marshal(&entities.Event{
+			Type:      entities.EventPresence,
+			RoomID:    roomID,
+			UserID:    p.UserID,
+			Payload:   payload,
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal presence event")
+		}
+
+		if err := conn.Send(eventJSON); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendAck answers a message frame of the sender.
+func (h *WebSocketHandler) sendAck(conn entities.Connection, roomID, userID uuid.UUID, ack entities.MessageAck) {
+	ackEvent := &entities.Event{
+		Type:      entities.EventMessageAck,
+		RoomID:    roomID,
+		UserID:    userID,
+		Timestamp: time.Now(),
+		Payload:   mustMarshal(ack),
+	}
+
+	if err := conn.Send(mustMarshal(ackEvent)); err != nil {
+		h.logger.Debug("Failed to send message ack", zap.Error(err))
+	}
+}
+
+// sendMessageError reports a failed message frame. Clients that sent a client
+// message ID get a failed ack they can match; others get a plain error event.
+func (h *WebSocketHandler) sendMessageError(conn entities.Connection, roomID, userID uuid.UUID, clientMsgID string, payload entities.ErrorPayload) {
+	if clientMsgID == "" {
+		h.sendErrorPayload(conn, roomID, userID, payload)
+		return
+	}
+
+	h.sendAck(conn, roomID, userID, entities.MessageAck{
+		ClientMsgID:  clientMsgID,
+		Error:        payload.Error,
+		RetryAfterMs: payload.RetryAfterMs,
+	})
+}
+
+// sendError notifies a single connection about a failed request.
+func (h *WebSocketHandler) sendError(conn entities.Connection, roomID, userID uuid.UUID, message string) {
+	h.sendErrorPayload(conn, roomID, userID, entities.ErrorPayload{Error: message})
+}
+
+func (h *WebSocketHandler) sendErrorPayload(conn entities.Connection, roomID, userID uuid.UUID, payload entities.ErrorPayload) {
+	errorEvent := &entities.Event{
+		Type:      entities.EventError,
+		RoomID:    roomID,
+		UserID:    userID,
+		Timestamp: time.Now(),
+		Payload:   mustMarshal(payload),
+	}
+
+	if err := conn.Send(mustMarshal(errorEvent)); err != nil {
+		h.logger.Error("Failed to send error event", zap.Error(err))
+	}
+}
+
+// clientErrorMessage maps domain errors to messages that are safe to show to clients.
+func clientErrorMessage(err error, fallback string) string {
+	switch {
+	case errors.Is(err, entities.ErrMessageNotFound):
+		return "Message not found"
+	case errors.Is(err, entities.ErrForbidden):
+		return "Action not allowed"
+	case errors.Is(err, entities.ErrInvalidParent):
+		return "Replies must target a top-level message of this room"
+	case errors.Is(err, entities.ErrInvalidReaction):
+		return "Invalid reaction"
+	case errors.Is(err, entities.ErrInvalidCursor):
+		return "Invalid cursor"
+	case errors.Is(err, entities.ErrInvalidSearch):
+		return "Invalid search request"
+	case errors.Is(err, entities.ErrInvalidPresence):
+		return "Invalid presence request"
+	case errors.Is(err, entities.ErrUnknownCommand):
+		return "Unknown command, send /help to list the commands"
+	case errors.Is(err, entities.ErrUserNotFound):
+		return "User not found in this room"
+	case errors.Is(err, entities.ErrMuted):
+		return "You are muted in this room"
+	case errors.Is(err, entities.ErrInvalidMessage):
+		var usage *entities.CommandUsageError
+		if errors.As(err, &usage) {
+			return "Usage: " + usage.Usage
+		}
+		return "Invalid message"
+	case errors.Is(err, entities.ErrInvalidSlowMode):
+		return "Invalid slow mode"
+	case errors.Is(err, entities.ErrAttachmentNotFound):
+		return "Attachment not found"
+	case errors.Is(err, entities.ErrAttachmentTooLarge):
+		return "File is too large"
+	case errors.Is(err, entities.ErrInvalidAttachment):
+		return "Invalid attachment"
+	case errors.Is(err, entities.ErrInvalidExportFormat):
+		return "Invalid export format, use jsonl, html or text"
+	case errors.Is(err, entities.ErrRateLimited):
+		var rateLimited *entities.RateLimitError
+		if errors.As(err, &rateLimited) && rateLimited.Scope == entities.RateLimitSlowMode {
+			return "Slow mode is on, wait before sending another message"
+		}
+		return "Too many messages, retry later"
+	case errors.Is(err, entities.ErrMessageRejected):
+		var rejected *entities.MessageRejectedError
+		if errors.As(err, &rejected) && rejected.Reason != "" {
+			return "Message rejected: " + rejected.Reason
+		}
+		return "Message rejected by a room script"
+	default:
+		return fallback
+	}
+}
+
+// isRefusal reports whether a message was refused by a rate limit, a mute or a
+// room script rather than failing.
+func isRefusal(err error) bool {
+	return errors.Is(err, entities.ErrRateLimited) ||
+		errors.Is(err, entities.ErrMessageRejected) ||
+		errors.Is(err, entities.ErrMuted)
+}
+
+// isCommandError reports whether a slash command failed because of how the
+// user called it rather than an internal error.
+func isCommandError(err error) bool {
+	return errors.Is(err, entities.ErrUnknownCommand) ||
+		errors.Is(err, entities.ErrInvalidMessage) ||
+		errors.Is(err, entities.ErrForbidden) ||
+		errors.Is(err, entities.ErrUserNotFound)
+}
+
+// clientErrorPayload is clientErrorMessage with a retry hint for rate-limited requests.
+func clientErrorPayload(err error, fallback string) entities.ErrorPayload {
+	payload := entities.ErrorPayload{Error: clientErrorMessage(err, fallback)}
+
+	var rateLimited *entities.RateLimitError
+	if errors.As(err, &rateLimited) {
+		payload.Code = "rate_limited"
+		if rateLimited.Scope == entities.RateLimitSlowMode {
+			payload.Code = "slow_mode"
+		}
+		payload.RetryAfterMs = rateLimited.RetryAfter.Milliseconds()
+		if payload.RetryAfterMs == 0 {
+			payload.RetryAfterMs = 1
+		}
+	}
+	if errors.Is(err, entities.ErrMessageRejected) {
+		payload.Code = "rejected"
+	}
+
+	var muted *entities.MutedError
+	if errors.As(err, &muted) {
+		payload.Code = "muted"
+		payload.RetryAfterMs = time.Until(muted.Until).Milliseconds()
+		if payload.RetryAfterMs <= 0 {
+			payload.RetryAfterMs = 1
+		}
+	}
+
+	return payload
+}
+
+// parseOptionalID parses an optional UUID coming from a client frame.
+func parseOptionalID(raw string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// parseIDs parses a list of UUIDs coming from a client.
+func parseIDs(raw []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(raw))
+	for i, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// parseOptionalTime parses an optional RFC 3339 timestamp coming from a client.
+func parseOptionalTime(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
 func mustMarshal(v interface{}) []byte {
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxMessageAttachments bounds the attachments of a single message.
+	MaxMessageAttachments = 10
+	// MaxAttachmentFileNameLength bounds the stored file name of an attachment, in bytes.
+	MaxAttachmentFileNameLength = 255
+)
+
+// Attachment is a file uploaded to a room. It is uploaded first and then
+// referenced by one message of its uploader, after which it is replayed with
+// that message. URL and ThumbnailURL are signed download URLs relative to the
+// chat service; they are set whenever an attachment is handed to a client and
+// expire after a while.
+type Attachment struct {
+	ID           uuid.UUID  `json:"id"`
+	RoomID       uuid.UUID  `json:"room_id"`
+	UploaderID   uuid.UUID  `json:"uploader_id"`
+	MessageID    *uuid.UUID `json:"-"` // Nil until a message references the attachment.
+	FileName     string     `json:"file_name"`
+	ContentType  string     `json:"content_type"` // Sniffed from the content, never taken from the client.
+	Size         int64      `json:"size"`
+	Width        int        `json:"width,omitempty"`  // Set on images.
+	Height       int        `json:"height,omitempty"` // Set on images.
+	HasThumbnail bool       `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	URL          string     `json:"url,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+}
+
+// IsImage reports whether the attachment is an image clients may show inline.
+func (a *Attachment) IsImage() bool {
+	switch a.ContentType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+var (
+	// ErrAttachmentNotFound is used when an attachment does not exist.
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrAttachmentTooLarge is used when an upload exceeds the configured size limit.
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrInvalidAttachment is used for empty uploads and for messages referencing
+	// attachments that are not unused uploads of their author in the room.
+	ErrInvalidAttachment = errors.New("invalid attachment")
+)
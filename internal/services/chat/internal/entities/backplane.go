@@ -0,0 +1,14 @@
+package entities
+
+import "github.com/google/uuid"
+
+// BackplaneMessage carries a room event between chat service instances
+// so that every instance can deliver it to its local connections.
+type BackplaneMessage struct {
+	Origin        string     `json:"origin"`
+	Event         *Event     `json:"event"`
+	ExcludeUserID *uuid.UUID `json:"exclude_user_id,omitempty"`
+	// Reference is set on a logged event too large for the backplane: its
+	// payload is left out, and receivers read it from the event log by Seq.
+	Reference bool `json:"reference,omitempty"`
+}
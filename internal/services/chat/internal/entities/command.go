@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Permissions granted by the Auth Service that slash commands check.
+const (
+	PermissionAdmin    = "admin"    // Grants every permission.
+	PermissionModerate = "moderate" // Lets a user moderate every room.
+)
+
+// HasPermission reports whether the permissions include the required one.
+// An empty requirement is always met.
+func HasPermission(permissions []string, required string) bool {
+	if required == "" {
+		return true
+	}
+	for _, p := range permissions {
+		if p == required || p == PermissionAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// MaxTopicLength bounds the topic of a room, in characters.
+	MaxTopicLength = 250
+	// DefaultMuteDuration is how long /mute silences a user when no duration is given.
+	DefaultMuteDuration = 10 * time.Minute
+	// MaxMuteDuration bounds how long a user can be muted.
+	MaxMuteDuration = 30 * 24 * time.Hour
+)
+
+// MessageKindEmote marks messages posted with /me. Regular messages have no kind.
+const MessageKindEmote = "emote"
+
+var commandNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// IsCommandName reports whether a slash command may be called name.
+func IsCommandName(name string) bool {
+	return commandNameRegexp.MatchString(name)
+}
+
+// ParseCommand splits a message into a slash command name and its arguments.
+// Messages that do not start with a slash followed by a valid name, such as
+// paths or messages escaped with a double slash, are not commands.
+func ParseCommand(content string) (name, args string, ok bool) {
+	if !strings.HasPrefix(content, "/") {
+		return "", "", false
+	}
+
+	name, args = content[1:], ""
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, args = name[:i], name[i:]
+	}
+
+	name = strings.ToLower(name)
+	if !IsCommandName(name) {
+		return "", "", false
+	}
+
+	return name, strings.TrimSpace(args), true
+}
+
+// UnescapeCommand returns the content to store for a message that is not a
+// command: a leading double slash stands for a single one.
+func UnescapeCommand(content string) string {
+	if strings.HasPrefix(content, "//") {
+		return content[1:]
+	}
+	return content
+}
+
+// CommandCall is a slash command sent by a user to a room.
+type CommandCall struct {
+	RoomID      uuid.UUID
+	UserID      uuid.UUID
+	Permissions []string // Permissions of the user, from the Auth Service.
+	Name        string   // Command name without the leading slash.
+	Args        string   // Text following the name, trimmed.
+	ParentID    *uuid.UUID
+	ClientMsgID string
+}
+
+// CommandInfo describes a slash command for help and autocomplete.
+type CommandInfo struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+	Permission  string `json:"permission,omitempty"` // Empty when every participant may run it.
+}
+
+// CommandResult is what a slash command did. Message is the message the command
+// posted, if any; Reply is shown only to the user who ran it.
+type CommandResult struct {
+	Command string   `json:"command"`
+	Reply   string   `json:"reply,omitempty"`
+	Message *Message `json:"-"`
+}
+
+// CommandsPayload is the payload of an EventCommands event.
+type CommandsPayload struct {
+	Commands []CommandInfo `json:"commands"`
+}
+
+// TopicPayload is the payload of an EventTopicUpdated event.
+type TopicPayload struct {
+	Topic string `json:"topic"` // Empty when the topic was cleared.
+}
+
+// ModerationPayload is the payload of the EventUserKicked, EventUserMuted and
+// EventUserUnmuted events. The event's UserID is the moderator.
+type ModerationPayload struct {
+	UserID uuid.UUID  `json:"user_id"`
+	Reason string     `json:"reason,omitempty"`
+	Until  *time.Time `json:"until,omitempty"` // Set on mutes only.
+}
+
+var (
+	// ErrUnknownCommand is used for a slash command no one registered.
+	ErrUnknownCommand = errors.New("unknown command")
+	// ErrMuted is matched by every MutedError.
+	ErrMuted = errors.New("user is muted")
+)
+
+// CommandUsageError reports a slash command called with invalid arguments.
+type CommandUsageError struct {
+	Usage string
+}
+
+func (e *CommandUsageError) Error() string {
+	return "usage: " + e.Usage
+}
+
+// Unwrap lets callers match every CommandUsageError with ErrInvalidMessage.
+func (e *CommandUsageError) Unwrap() error {
+	return ErrInvalidMessage
+}
+
+// MutedError refuses a message of a user muted in the room.
+type MutedError struct {
+	Until time.Time
+}
+
+func (e *MutedError) Error() string {
+	return "user is muted until " + e.Until.Format(time.RFC3339)
+}
+
+func (e *MutedError) Unwrap() error {
+	return ErrMuted
+}
@@ -7,8 +7,12 @@ type ConnectionType string
 const (
 	WebSocket ConnectionType = "websocket"
 	GRPC      ConnectionType = "grpc"
+	SSE       ConnectionType = "sse"
+	LongPoll  ConnectionType = "long_poll"
 )
 
+// Connection is a client attached to a room. Send must not block: implementations
+// queue the message and close themselves when the client cannot keep up.
 type Connection interface {
 	Send(message []byte) error
 	Close() error
@@ -7,4 +7,10 @@ var (
 	ErrUserNotFound     = errors.New("user not found")
 	ErrInvalidToken     = errors.New("invalid token")
 	ErrConnectionClosed = errors.New("connection closed")
+	ErrMessageNotFound  = errors.New("message not found")
+	ErrForbidden        = errors.New("action not allowed")
+	ErrInvalidParent    = errors.New("replies must target a top-level message of the same room")
+	ErrInvalidReaction  = errors.New("invalid reaction")
+	ErrInvalidMessage   = errors.New("invalid message")
+	ErrInvalidSlowMode  = errors.New("invalid slow mode")
 )
@@ -14,6 +14,24 @@ const (
 	EventUserDisconnected EventType = "user_disconnected"
 	EventNewMessage       EventType = "new_message"
 	EventMessageHistory   EventType = "message_history"
+	EventMessageEdited    EventType = "message_edited"
+	EventMessageDeleted   EventType = "message_deleted"
+	EventThreadHistory    EventType = "thread_history"
+	EventReactionUpdated  EventType = "reaction_updated"
+	EventUserTyping       EventType = "user_typing"
+	EventReadReceipt      EventType = "read_receipt"
+	EventUnreadCounts     EventType = "unread_counts"
+	EventSearchResults    EventType = "search_results"
+	EventPresence         EventType = "presence"
+	EventMessageAck       EventType = "message_ack"
+	EventReplay           EventType = "replay"
+	EventSlowModeUpdated  EventType = "slow_mode_updated"
+	EventTopicUpdated     EventType = "topic_updated"
+	EventUserKicked       EventType = "user_kicked"
+	EventUserMuted        EventType = "user_muted"
+	EventUserUnmuted      EventType = "user_unmuted"
+	EventCommandResult    EventType = "command_result"
+	EventCommands         EventType = "commands"
 	EventError            EventType = "error"
 )
 
@@ -23,12 +41,53 @@ type Event struct {
 	UserID    uuid.UUID       `json:"user_id"`
 	Payload   json.RawMessage `json:"payload,omitempty"`
 	Timestamp time.Time       `json:"timestamp"`
+	Seq       int64           `json:"seq,omitempty"` // Set on persisted events only.
 }
 
 type Message struct {
-	ID        uuid.UUID `json:"id"`
-	RoomID    uuid.UUID `json:"room_id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
+	ID          uuid.UUID    `json:"id"`
+	RoomID      uuid.UUID    `json:"room_id"`
+	UserID      uuid.UUID    `json:"user_id"`
+	ClientMsgID string       `json:"client_msg_id,omitempty"`
+	ParentID    *uuid.UUID   `json:"parent_id,omitempty"`
+	Kind        string       `json:"kind,omitempty"`        // MessageKindEmote, MessageKindIntegration or empty.
+	AuthorName  string       `json:"author_name,omitempty"` // Set on integration messages, whose UserID is the integration.
+	Content     string       `json:"content"`
+	Timestamp   time.Time    `json:"timestamp"`
+	EditedAt    *time.Time   `json:"edited_at,omitempty"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty"`
+	ReplyCount  int          `json:"reply_count,omitempty"`
+	Reactions   []Reaction   `json:"reactions,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+const (
+	// MaxClientMsgIDLength bounds the client message IDs stored for deduplication.
+	MaxClientMsgIDLength = 64
+	// MaxMessageLength bounds the content of a message, in characters, whichever
+	// transport it comes through.
+	MaxMessageLength = 4000
+)
+
+// MessageAck is the payload of an EventMessageAck event, sent only to the
+// sender of a message. It carries either the stored message or an error.
+type MessageAck struct {
+	ClientMsgID  string     `json:"client_msg_id,omitempty"`
+	MessageID    *uuid.UUID `json:"message_id,omitempty"`
+	Timestamp    *time.Time `json:"timestamp,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	RetryAfterMs int64      `json:"retry_after_ms,omitempty"` // Set when a rate limit rejected the message.
+}
+
+// ErrorPayload is the payload of an EventError event. Code and RetryAfterMs are
+// only set for errors clients are expected to handle, such as rate limits.
+type ErrorPayload struct {
+	Error        string `json:"error"`
+	Code         string `json:"code,omitempty"`
+	RetryAfterMs int64  `json:"retry_after_ms,omitempty"`
+}
+
+// TypingPayload is the payload of an EventUserTyping event.
+type TypingPayload struct {
+	Typing bool `json:"typing"`
 }
@@ -0,0 +1,55 @@
+package entities
+
+import "errors"
+
+// ExportFormat selects how the history of a room is exported.
+type ExportFormat string
+
+const (
+	// ExportFormatJSONL writes one JSON object per message.
+	ExportFormatJSONL ExportFormat = "jsonl"
+	// ExportFormatHTML writes a self-contained HTML transcript.
+	ExportFormatHTML ExportFormat = "html"
+	// ExportFormatText writes one line per message.
+	ExportFormatText ExportFormat = "text"
+)
+
+// ErrInvalidExportFormat is used when an export is requested in an unknown format.
+var ErrInvalidExportFormat = errors.New("invalid export format")
+
+// ParseExportFormat returns the export format with the given name. An empty
+// name selects JSON Lines.
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch format := ExportFormat(name); format {
+	case "":
+		return ExportFormatJSONL, nil
+	case ExportFormatJSONL, ExportFormatHTML, ExportFormatText:
+		return format, nil
+	default:
+		return "", ErrInvalidExportFormat
+	}
+}
+
+// ContentType returns the media type of exports in the format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatHTML:
+		return "text/html; charset=utf-8"
+	case ExportFormatText:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// Extension returns the file name extension of exports in the format.
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportFormatHTML:
+		return ".html"
+	case ExportFormatText:
+		return ".txt"
+	default:
+		return ".jsonl"
+	}
+}
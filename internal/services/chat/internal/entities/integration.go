@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// MessageKindIntegration marks messages posted through an incoming webhook.
+const MessageKindIntegration = "integration"
+
+// Integration is an incoming webhook of the Website Service: a secret token
+// that lets an external system post messages to a room. Its messages are
+// authored by its ID and shown under its name.
+type Integration struct {
+	ID     uuid.UUID
+	RoomID uuid.UUID
+	Name   string
+}
+
+// ErrIntegrationNotFound is used when no incoming webhook has the given token.
+var ErrIntegrationNotFound = errors.New("incoming webhook not found")
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// MessageCursor is a keyset position in the history of a room, ordered by (timestamp, id).
+type MessageCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf returns the cursor pointing at the given message.
+func CursorOf(msg *Message) MessageCursor {
+	return MessageCursor{
+		Timestamp: msg.Timestamp,
+		ID:        msg.ID,
+	}
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c MessageCursor) String() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixMicro(), 10) + "_" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseMessageCursor decodes a token produced by MessageCursor.String.
+// An empty token yields a nil cursor.
+func ParseMessageCursor(token string) (*MessageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	micros, id, found := strings.Cut(string(raw), "_")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	ts, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &MessageCursor{
+		Timestamp: time.UnixMicro(ts).UTC(),
+		ID:        messageID,
+	}, nil
+}
+
+// MessagePage is a page of room history in chronological order.
+// PrevCursor fetches older messages and NextCursor newer ones; they are empty
+// when there is nothing more in that direction. LastSeq is the sequence number
+// of the latest room event when the page was read, from which clients can resume.
+type MessagePage struct {
+	Messages   []*Message
+	PrevCursor string
+	NextCursor string
+	LastSeq    int64
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidPresence is returned when a presence request is malformed.
+var ErrInvalidPresence = errors.New("invalid presence request")
+
+// MaxPresenceUsers bounds the number of users of a single presence lookup or subscription.
+const MaxPresenceUsers = 200
+
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+// Presence is the global status of a user across every room and chat instance.
+// It is also the payload of an EventPresence event. LastSeen is only set for offline users.
+type Presence struct {
+	UserID   uuid.UUID      `json:"user_id"`
+	Status   PresenceStatus `json:"status"`
+	LastSeen *time.Time     `json:"last_seen,omitempty"`
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RateLimitScope names the limit that rejected a message.
+type RateLimitScope string
+
+const (
+	RateLimitUser     RateLimitScope = "user"
+	RateLimitRoom     RateLimitScope = "room"
+	RateLimitIP       RateLimitScope = "ip"
+	RateLimitSlowMode RateLimitScope = "slow_mode"
+)
+
+// MaxSlowMode bounds the interval a room owner can impose between two messages of a user.
+const MaxSlowMode = 6 * time.Hour
+
+// ErrRateLimited is matched by every RateLimitError.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError rejects a message sent too fast and tells when it may be retried.
+type RateLimitError struct {
+	Scope      RateLimitScope
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded, retry after %s", e.Scope, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// SlowModePayload is the payload of an EventSlowModeUpdated event.
+type SlowModePayload struct {
+	Seconds int `json:"seconds"`
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "github.com/google/uuid"
+
+// Reaction is the aggregated state of one emoji on a message.
+type Reaction struct {
+	Emoji   string      `json:"emoji"`
+	Count   int         `json:"count"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// ReactionUpdate is the payload of an EventReactionUpdated event.
+type ReactionUpdate struct {
+	MessageID uuid.UUID  `json:"message_id"`
+	Reactions []Reaction `json:"reactions"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadReceipt is the payload of an EventReadReceipt event.
+type ReadReceipt struct {
+	MessageID uuid.UUID `json:"message_id"`
+	ReadAt    time.Time `json:"read_at"`
+}
+
+// UnreadCount holds the number of messages a user has not read yet in a room.
+type UnreadCount struct {
+	RoomID uuid.UUID `json:"room_id"`
+	Count  int       `json:"count"`
+}
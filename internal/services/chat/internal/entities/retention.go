@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionPolicy bounds how long the messages of a room are kept. Messages
+// older than Days days, or beyond the Messages newest ones, are purged; a zero
+// limit does not apply. The owner of the room sets it on the website service.
+type RetentionPolicy struct {
+	RoomID   uuid.UUID
+	Days     int
+	Messages int
+}
+
+// Cutoff returns the time before which messages are expired by age, and false
+// when the policy keeps messages regardless of their age.
+func (p *RetentionPolicy) Cutoff(now time.Time) (time.Time, bool) {
+	if p.Days <= 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -p.Days), true
+}
+
+// PurgeResult counts what a purge removed from a room. Attachments holds the
+// attachments of the purged messages, whose contents are still to be deleted.
+type PurgeResult struct {
+	Messages    int
+	Attachments []Attachment
+}
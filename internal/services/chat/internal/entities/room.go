@@ -9,9 +9,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// Room holds the live connections of a chat room. A user may be connected
+// from several devices at once, so connections are keyed by connection ID
+// and grouped per user.
 type Room struct {
 	ID           uuid.UUID
-	connections  sync.Map // map[uuid.UUID]Connection.
+	connections  map[uuid.UUID]Connection               // connection ID -> connection.
+	users        map[uuid.UUID]map[uuid.UUID]Connection // user ID -> connection ID -> connection.
+	owners       map[uuid.UUID]uuid.UUID                // connection ID -> user ID.
 	logger       *zap.Logger
 	lastActivity time.Time
 	mu           sync.RWMutex
@@ -20,43 +25,91 @@ type Room struct {
 func NewRoom(id uuid.UUID, logger *zap.Logger) *Room {
 	return &Room{
 		ID:           id,
+		connections:  make(map[uuid.UUID]Connection),
+		users:        make(map[uuid.UUID]map[uuid.UUID]Connection),
+		owners:       make(map[uuid.UUID]uuid.UUID),
 		logger:       logger,
 		lastActivity: time.Now(),
 	}
 }
 
-func (r *Room) AddConnection(userID uuid.UUID, conn Connection) {
-	if existingConn, loaded := r.connections.LoadAndDelete(userID); loaded {
-		if existing, ok := existingConn.(Connection); ok {
-			r.logger.Debug("Closing existing connection",
-				zap.String("room_id", r.ID.String()),
-				zap.String("user_id", userID.String()),
-			)
-			existing.Close()
-		}
-	}
+// AddConnection registers a connection of the user and reports whether it is
+// the user's first connection to the room.
+func (r *Room) AddConnection(userID uuid.UUID, conn Connection) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	r.logger.Debug("Adding new connection",
 		zap.String("room_id", r.ID.String()),
 		zap.String("user_id", userID.String()),
+		zap.String("connection_id", conn.ID().String()),
 	)
-	r.connections.Store(userID, conn)
-	r.updateLastActivity()
+
+	userConns, exists := r.users[userID]
+	if !exists {
+		userConns = make(map[uuid.UUID]Connection)
+		r.users[userID] = userConns
+	}
+
+	userConns[conn.ID()] = conn
+	r.connections[conn.ID()] = conn
+	r.owners[conn.ID()] = userID
+	r.lastActivity = time.Now()
+
+	return !exists
 }
 
-func (r *Room) RemoveConnection(userID uuid.UUID) {
-	if conn, loaded := r.connections.LoadAndDelete(userID); loaded {
-		if connection, ok := conn.(Connection); ok {
-			r.logger.Debug("Removing connection",
-				zap.String("room_id", r.ID.String()),
-				zap.String("user_id", userID.String()),
-			)
-			connection.Close()
-		}
+// RemoveConnection closes and removes a connection and reports whether it
+// was the last connection of its user. Removing an unknown connection is a no-op.
+func (r *Room) RemoveConnection(userID, connectionID uuid.UUID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastActivity = time.Now()
+
+	conn, exists := r.connections[connectionID]
+	if !exists || r.owners[connectionID] != userID {
+		return false
 	}
-	r.updateLastActivity()
+
+	r.logger.Debug("Removing connection",
+		zap.String("room_id", r.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("connection_id", connectionID.String()),
+	)
+	conn.Close()
+
+	delete(r.connections, connectionID)
+	delete(r.owners, connectionID)
+
+	userConns := r.users[userID]
+	delete(userConns, connectionID)
+	if len(userConns) > 0 {
+		return false
+	}
+
+	delete(r.users, userID)
+	return true
 }
 
+// CloseUserConnections closes every connection of the user. Their transports
+// then remove them through the regular disconnect path.
+func (r *Room) CloseUserConnections(userID uuid.UUID) {
+	r.mu.RLock()
+	conns := make([]Connection, 0, len(r.users[userID]))
+	for _, conn := range r.users[userID] {
+		conns = append(conns, conn)
+	}
+	r.mu.RUnlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// BroadcastEvent sends the event to every connection of the room, skipping all
+// connections of excludeUserID. Connections that fail are closed by their
+// transport, which then removes them through the regular disconnect path.
 func (r *Room) BroadcastEvent(event *Event, excludeUserID *uuid.UUID) {
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
@@ -68,82 +121,82 @@ func (r *Room) BroadcastEvent(event *Event, excludeUserID *uuid.UUID) {
 		return
 	}
 
-	r.connections.Range(func(key, value interface{}) bool {
-		userID := key.(uuid.UUID)
-		if excludeUserID != nil && userID == *excludeUserID {
-			return true
+	r.mu.RLock()
+	recipients := make([]Connection, 0, len(r.connections))
+	for connID, conn := range r.connections {
+		if excludeUserID != nil && r.owners[connID] == *excludeUserID {
+			continue
 		}
+		recipients = append(recipients, conn)
+	}
+	r.mu.RUnlock()
+
+	// Connections queue outbound events, so sending inline never blocks the broadcast.
+	for _, conn := range recipients {
+		if err := conn.Send(eventJSON); err != nil && err != ErrConnectionClosed {
+			r.logger.Error("Failed to send event",
+				zap.Error(err),
+				zap.String("room_id", r.ID.String()),
+				zap.String("connection_id", conn.ID().String()),
+				zap.String("event_type", string(event.Type)),
+			)
+		}
+	}
 
-		conn := value.(Connection)
-		go func(c Connection, uid uuid.UUID) {
-			if err := c.Send(eventJSON); err != nil {
-				if err == ErrConnectionClosed {
-					r.logger.Debug("Removing closed connection during broadcast",
-						zap.String("room_id", r.ID.String()),
-						zap.String("user_id", uid.String()),
-					)
-					r.RemoveConnection(uid)
-				} else {
-					r.logger.Error("Failed to send event",
-						zap.Error(err),
-						zap.String("room_id", r.ID.String()),
-						zap.String("user_id", uid.String()),
-						zap.String("event_type", string(event.Type)),
-					)
-				}
-			}
-		}(conn, userID)
-		return true
-	})
 	r.updateLastActivity()
 }
 
 func (r *Room) IsEmpty() bool {
-	empty := true
-	r.connections.Range(func(key, value interface{}) bool {
-		conn := value.(Connection)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, conn := range r.connections {
 		if !conn.IsClosed() {
-			empty = false
 			return false
 		}
-		return true
-	})
-	return empty
+	}
+	return true
 }
 
 func (r *Room) CleanupConnections() {
-	r.connections.Range(func(key, value interface{}) bool {
-		userID := key.(uuid.UUID)
-		if conn, ok := value.(Connection); ok {
-			r.logger.Debug("Cleaning up connection",
-				zap.String("room_id", r.ID.String()),
-				zap.String("user_id", userID.String()),
-			)
-			conn.Close()
-		}
-		r.connections.Delete(key)
-		return true
-	})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for connID, conn := range r.connections {
+		r.logger.Debug("Cleaning up connection",
+			zap.String("room_id", r.ID.String()),
+			zap.String("connection_id", connID.String()),
+		)
+		conn.Close()
+	}
+
+	r.connections = make(map[uuid.UUID]Connection)
+	r.users = make(map[uuid.UUID]map[uuid.UUID]Connection)
+	r.owners = make(map[uuid.UUID]uuid.UUID)
 }
 
-// GetConnectionCount возвращает количество активных соединений
+// GetConnectionCount returns the number of open connections (sockets, streams and sessions).
 func (r *Room) GetConnectionCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	count := 0
-	r.connections.Range(func(key, value interface{}) bool {
-		conn := value.(Connection)
+	for _, conn := range r.connections {
 		if !conn.IsClosed() {
 			count++
 		}
-		return true
-	})
+	}
 	return count
 }
 
-// CheckConnection проверяет существование и валидность соединения
+// CheckConnection reports whether the user has at least one open connection to the room.
 func (r *Room) CheckConnection(userID uuid.UUID) bool {
-	if conn, ok := r.connections.Load(userID); ok {
-		if connection, ok := conn.(Connection); ok {
-			return !connection.IsClosed()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, conn := range r.users[userID] {
+		if !conn.IsClosed() {
+			return true
 		}
 	}
 	return false
@@ -161,15 +214,19 @@ func (r *Room) updateLastActivity() {
 	r.mu.Unlock()
 }
 
+// GetParticipants returns the distinct users with at least one open connection.
 func (r *Room) GetParticipants() []uuid.UUID {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var participants []uuid.UUID
-	r.connections.Range(func(key, value interface{}) bool {
-		userID := key.(uuid.UUID)
-		conn := value.(Connection)
-		if !conn.IsClosed() {
-			participants = append(participants, userID)
+	for userID, userConns := range r.users {
+		for _, conn := range userConns {
+			if !conn.IsClosed() {
+				participants = append(participants, userID)
+				break
+			}
 		}
-		return true
-	})
+	}
 	return participants
 }
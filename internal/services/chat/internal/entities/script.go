@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomScript is an enabled Starlark script the owner of a room attached to it.
+// Replies posted by a script are authored by the script's ID.
+type RoomScript struct {
+	ID               uuid.UUID
+	RoomID           uuid.UUID
+	Name             string
+	Source           string
+	ScheduleInterval time.Duration // How often on_schedule runs; zero never runs it.
+}
+
+// ErrMessageRejected is matched by every MessageRejectedError.
+var ErrMessageRejected = errors.New("message rejected")
+
+// MessageRejectedError refuses a message a room script rejected.
+type MessageRejectedError struct {
+	Reason string // Shown to the sender; may be empty.
+}
+
+func (e *MessageRejectedError) Error() string {
+	if e.Reason == "" {
+		return "message rejected by a room script"
+	}
+	return "message rejected by a room script: " + e.Reason
+}
+
+func (e *MessageRejectedError) Unwrap() error {
+	return ErrMessageRejected
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSearch is returned when a search request is malformed.
+var ErrInvalidSearch = errors.New("invalid search request")
+
+// SearchFilter narrows a full-text search over chat messages.
+// A nil RoomID searches every room the caller has joined.
+type SearchFilter struct {
+	Text     string
+	RoomID   *uuid.UUID
+	AuthorID *uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
+// SearchResult is a message matching a search together with its relevance and highlighted snippet.
+type SearchResult struct {
+	Message *Message `json:"message"`
+	Snippet string   `json:"snippet"`
+	Rank    float64  `json:"rank"`
+}
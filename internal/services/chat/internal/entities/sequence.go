@@ -0,0 +1,34 @@
+package entities
+
+import "github.com/google/uuid"
+
+const (
+	// MaxReplayEvents bounds how many missed events are replayed to a resuming
+	// client. Clients that missed more are asked to reload the history instead.
+	MaxReplayEvents = 1000
+	// ReplayBatchSize is the number of events carried by a single replay event.
+	ReplayBatchSize = 100
+)
+
+// Replay is the payload of an EventReplay event. A resuming client receives
+// replay events until HasMore is false; live events follow afterwards. When
+// Reset is set the missed events are not available and the client must reload
+// the history instead, continuing from LastSeq.
+type Replay struct {
+	Events  []*Event `json:"events"`
+	LastSeq int64    `json:"last_seq"`
+	HasMore bool     `json:"has_more"`
+	Reset   bool     `json:"reset,omitempty"`
+}
+
+// MessageRef stands for the message of a logged message event. Message events
+// are logged by reference and rebuilt from the message when replayed, so that
+// the replay shows its current content and fresh attachment URLs.
+type MessageRef struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// CarriesMessage reports whether events of the type have a message as payload.
+func (t EventType) CarriesMessage() bool {
+	return t == EventNewMessage || t == EventMessageEdited || t == EventMessageDeleted
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events of a room published to the Website Service for its outgoing webhooks.
+const (
+	WebhookEventMessageCreated = "message.created" // Data is the message.
+	WebhookEventUserJoined     = "user.joined"     // Data is a UserJoinedData.
+)
+
+// WebhookEvent is an event of a room published for its outgoing webhooks.
+type WebhookEvent struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Type       string
+	Data       json.RawMessage
+	OccurredAt time.Time
+}
+
+// UserJoinedData is the data of a WebhookEventUserJoined event.
+type UserJoinedData struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// QueuedWebhookEvent is a webhook event waiting in the outbox of the chat service.
+type QueuedWebhookEvent struct {
+	WebhookEvent
+	Attempts int       // Attempts to publish the event, including the current one.
+	QueuedAt time.Time // When the event was queued.
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	once     sync.Once
+	instance *ChatMetrics
+)
+
+type ChatMetrics struct {
+	RetentionPurged      *prometheus.CounterVec
+	RetentionRuns        *prometheus.CounterVec
+	RetentionRunDuration prometheus.Histogram
+	RetentionLastSuccess prometheus.Gauge
+}
+
+func NewChatMetrics(namespace string) *ChatMetrics {
+	var metrics *ChatMetrics
+
+	once.Do(func() {
+		metrics = &ChatMetrics{
+			RetentionPurged: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace: namespace,
+					Name:      "retention_purged_total",
+					Help:      "Total number of rows purged by room retention policies",
+				},
+				[]string{"kind"},
+			),
+
+			RetentionRuns: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace: namespace,
+					Name:      "retention_runs_total",
+					Help:      "Total number of retention purge runs by result",
+				},
+				[]string{"result"},
+			),
+
+			RetentionRunDuration: promauto.NewHistogram(
+				prometheus.HistogramOpts{
+					Namespace: namespace,
+					Name:      "retention_run_duration_seconds",
+					Help:      "Time spent purging expired messages in a run",
+					Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900},
+				},
+			),
+
+			RetentionLastSuccess: promauto.NewGauge(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Name:      "retention_last_success_timestamp_seconds",
+					Help:      "Unix time of the last retention purge run that completed without errors",
+				},
+			),
+		}
+
+		instance = metrics
+	})
+
+	return instance
+}
+
+func GetInstance() *ChatMetrics {
+	if instance == nil {
+		panic("metrics not initialized")
+	}
+	return instance
+}
+
+func (m *ChatMetrics) RecordRetentionPurge(kind string, count int) {
+	m.RetentionPurged.WithLabelValues(kind).Add(float64(count))
+}
+
+func (m *ChatMetrics) RecordRetentionRun(result string, duration float64) {
+	m.RetentionRuns.WithLabelValues(result).Inc()
+	m.RetentionRunDuration.Observe(duration)
+	if result == "success" {
+		m.RetentionLastSuccess.Set(float64(time.Now().Unix()))
+	}
+}
@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// getNewAttachments loads the attachments a new message of the user references.
+// They must be uploads of the user to the room that no message uses yet; storage
+// enforces the latter again when the message is saved.
+func (s *Service) getNewAttachments(ctx context.Context, roomID, userID uuid.UUID, attachmentIDs []uuid.UUID) ([]entities.Attachment, error) {
+	if len(attachmentIDs) == 0 {
+		return nil, nil
+	}
+	if len(attachmentIDs) > entities.MaxMessageAttachments {
+		return nil, errors.Wrapf(entities.ErrInvalidAttachment, "a message may have at most %d attachments", entities.MaxMessageAttachments)
+	}
+
+	found, err := s.storage.GetAttachments(ctx, attachmentIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get attachments")
+	}
+	byID := make(map[uuid.UUID]*entities.Attachment, len(found))
+	for _, attachment := range found {
+		byID[attachment.ID] = attachment
+	}
+
+	attachments := make([]entities.Attachment, 0, len(attachmentIDs))
+	seen := make(map[uuid.UUID]struct{}, len(attachmentIDs))
+	for _, id := range attachmentIDs {
+		if _, ok := seen[id]; ok {
+			return nil, errors.Wrap(entities.ErrInvalidAttachment, "duplicate attachment")
+		}
+		seen[id] = struct{}{}
+
+		attachment, ok := byID[id]
+		if !ok || attachment.RoomID != roomID || attachment.UploaderID != userID {
+			return nil, errors.Wrapf(entities.ErrAttachmentNotFound, "attachment %s", id)
+		}
+		if attachment.MessageID != nil {
+			return nil, errors.Wrapf(entities.ErrInvalidAttachment, "attachment %s is already sent", id)
+		}
+
+		s.attachments.SignURLs(attachment)
+		attachments = append(attachments, *attachment)
+	}
+
+	return attachments, nil
+}
+
+// loadAttachments sets the attachments of messages with fresh download URLs.
+// Deleted messages keep none.
+func (s *Service) loadAttachments(ctx context.Context, messages []*entities.Message) error {
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, msg := range messages {
+		if msg.DeletedAt == nil {
+			ids = append(ids, msg.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	attachments, err := s.storage.GetMessageAttachments(ctx, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to get attachments")
+	}
+
+	for _, msg := range messages {
+		if msg.DeletedAt != nil {
+			continue
+		}
+		msg.Attachments = attachments[msg.ID]
+		for i := range msg.Attachments {
+			s.attachments.SignURLs(&msg.Attachments[i])
+		}
+	}
+
+	return nil
+}
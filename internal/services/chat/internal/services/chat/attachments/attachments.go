@@ -0,0 +1,284 @@
+// Package attachments stores the files users upload to chat rooms and serves
+// them through signed, expiring download URLs, so that clients can load them
+// without sending an access token, e.g. from an <img> tag.
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// URLPrefix starts the download URLs of attachments; the chat service serves
+// them under this path.
+const URLPrefix = "/api/v1/chat/attachments/"
+
+const (
+	variantOriginal  = "original"
+	variantThumbnail = "thumbnail"
+)
+
+// Config bounds uploads and signs download URLs. URLs signed with URLSecret
+// stay valid for URLTTL, so every instance must share the secret. Uploads that
+// no message references OrphanTTL after they were made are removed by the
+// sweep running every SweepInterval.
+type Config struct {
+	MaxSize       int64 // In bytes.
+	ThumbnailSize int   // Longest side of thumbnails, in pixels.
+	URLSecret     string
+	URLTTL        time.Duration
+	OrphanTTL     time.Duration
+	SweepInterval time.Duration
+}
+
+type Service struct {
+	storage Storage
+	blobs   BlobStore
+	cfg     Config
+	logger  *zap.Logger
+
+	thumbnailSlots chan struct{} // Bounds concurrent image decodes.
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(deps Deps, cfg Config, logger *zap.Logger) *Service {
+	return &Service{
+		storage:        deps.Storage,
+		blobs:          deps.Blobs,
+		cfg:            cfg,
+		logger:         logger,
+		thumbnailSlots: make(chan struct{}, maxConcurrentThumbnails),
+	}
+}
+
+// Upload stores a file uploaded by a participant of a room. Its type is sniffed
+// from its content; images get their dimensions and, when they can be decoded,
+// a thumbnail. The attachment is returned with signed URLs and can then be
+// referenced by one message of its uploader.
+func (s *Service) Upload(ctx context.Context, roomID, userID uuid.UUID, fileName string, r io.Reader) (*entities.Attachment, error) {
+	isParticipant, err := s.storage.IsParticipant(ctx, roomID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify participant")
+	}
+	if !isParticipant {
+		return nil, entities.ErrForbidden
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read upload")
+	}
+	if int64(len(data)) > s.cfg.MaxSize {
+		return nil, errors.Wrapf(entities.ErrAttachmentTooLarge, "files are limited to %d bytes", s.cfg.MaxSize)
+	}
+	if len(data) == 0 {
+		return nil, errors.Wrap(entities.ErrInvalidAttachment, "file is empty")
+	}
+
+	attachment := &entities.Attachment{
+		ID:          uuid.New(),
+		RoomID:      roomID,
+		UploaderID:  userID,
+		FileName:    cleanFileName(fileName),
+		ContentType: sniffContentType(data),
+		Size:        int64(len(data)),
+		CreatedAt:   time.Now(),
+	}
+
+	var thumbnail []byte
+	if attachment.IsImage() {
+		if width, height, ok := imageSize(data); ok {
+			attachment.Width, attachment.Height = width, height
+
+			thumbnail, err = s.thumbnail(ctx, data, width, height)
+			if err != nil {
+				s.logger.Debug("No thumbnail for image",
+					zap.Error(err),
+					zap.String("attachment_id", attachment.ID.String()),
+				)
+			}
+		}
+	}
+
+	key := blobKey(attachment, variantOriginal)
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(data), attachment.Size, attachment.ContentType); err != nil {
+		return nil, errors.Wrap(err, "failed to store attachment")
+	}
+	keys := []string{key}
+
+	if thumbnail != nil {
+		thumbKey := blobKey(attachment, variantThumbnail)
+		if err := s.blobs.Put(ctx, thumbKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+			s.deleteBlobs(keys)
+			return nil, errors.Wrap(err, "failed to store thumbnail")
+		}
+		keys = append(keys, thumbKey)
+		attachment.HasThumbnail = true
+	}
+
+	if err := s.storage.SaveAttachment(ctx, attachment); err != nil {
+		s.deleteBlobs(keys)
+		return nil, errors.Wrap(err, "failed to save attachment")
+	}
+
+	s.SignURLs(attachment)
+	return attachment, nil
+}
+
+// thumbnail makes the thumbnail of an image once a decode slot is free.
+func (s *Service) thumbnail(ctx context.Context, data []byte, width, height int) ([]byte, error) {
+	select {
+	case s.thumbnailSlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "waiting for a thumbnail slot")
+	}
+	defer func() { <-s.thumbnailSlots }()
+
+	return makeThumbnail(data, width, height, s.cfg.ThumbnailSize)
+}
+
+// Open returns an attachment and its content, or the content of its thumbnail,
+// for a signed download URL. The caller closes the content.
+func (s *Service) Open(ctx context.Context, attachmentID uuid.UUID, thumbnail bool, expires int64, signature string) (*entities.Attachment, io.ReadCloser, error) {
+	variant := variantOriginal
+	if thumbnail {
+		variant = variantThumbnail
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(attachmentID, variant, expires))) {
+		return nil, nil, errors.Wrap(entities.ErrForbidden, "invalid download signature")
+	}
+	if time.Now().Unix() > expires {
+		return nil, nil, errors.Wrap(entities.ErrForbidden, "download URL expired")
+	}
+
+	attachment, err := s.storage.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if thumbnail && !attachment.HasThumbnail {
+		return nil, nil, entities.ErrAttachmentNotFound
+	}
+
+	content, err := s.blobs.Get(ctx, blobKey(attachment, variant))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, errors.Wrap(entities.ErrAttachmentNotFound, "content is missing")
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read attachment")
+	}
+
+	return attachment, content, nil
+}
+
+// SignURLs sets the download URLs of an attachment, valid for the configured TTL.
+func (s *Service) SignURLs(attachment *entities.Attachment) {
+	expires := time.Now().Add(s.cfg.URLTTL).Unix()
+
+	attachment.URL = s.signedURL(attachment.ID, variantOriginal, expires)
+	attachment.ThumbnailURL = ""
+	if attachment.HasThumbnail {
+		attachment.ThumbnailURL = s.signedURL(attachment.ID, variantThumbnail, expires)
+	}
+}
+
+func (s *Service) signedURL(attachmentID uuid.UUID, variant string, expires int64) string {
+	u := URLPrefix + attachmentID.String()
+	if variant == variantThumbnail {
+		u += "/thumbnail"
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sig", s.sign(attachmentID, variant, expires))
+	return u + "?" + query.Encode()
+}
+
+func (s *Service) sign(attachmentID uuid.UUID, variant string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.URLSecret))
+	mac.Write([]byte(attachmentID.String() + ":" + variant + ":" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// DeleteContents removes the stored contents and thumbnails of attachments
+// whose metadata is already gone. It returns the first failure but tries every
+// blob.
+func (s *Service) DeleteContents(ctx context.Context, attachments []entities.Attachment) error {
+	var firstErr error
+	for i := range attachments {
+		variants := []string{variantOriginal}
+		if attachments[i].HasThumbnail {
+			variants = append(variants, variantThumbnail)
+		}
+
+		for _, variant := range variants {
+			key := blobKey(&attachments[i], variant)
+			if err := s.blobs.Delete(ctx, key); err != nil {
+				s.logger.Warn("Failed to delete attachment blob", zap.Error(err), zap.String("key", key))
+				if firstErr == nil {
+					firstErr = errors.Wrap(err, "failed to delete attachment contents")
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+// deleteBlobs removes the blobs of an upload that could not be completed.
+func (s *Service) deleteBlobs(keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			s.logger.Warn("Failed to delete orphaned blob", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+// blobKey groups the blobs of an attachment under its room.
+func blobKey(attachment *entities.Attachment, variant string) string {
+	return attachment.RoomID.String() + "/" + attachment.ID.String() + "/" + variant
+}
+
+// cleanFileName keeps the last element of a client-supplied file name, without
+// control characters and within the length limit.
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	for len(name) > entities.MaxAttachmentFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
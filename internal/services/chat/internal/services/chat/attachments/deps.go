@@ -0,0 +1,31 @@
+package attachments
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Storage keeps the metadata of attachments.
+type Storage interface {
+	IsParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
+	SaveAttachment(ctx context.Context, attachment *entities.Attachment) error
+	GetAttachment(ctx context.Context, attachmentID uuid.UUID) (*entities.Attachment, error)
+	DeleteOrphanedAttachments(ctx context.Context, before time.Time, limit int) ([]entities.Attachment, error)
+}
+
+// BlobStore keeps the contents of attachments and their thumbnails. Reading a
+// missing blob fails with an error matching fs.ErrNotExist.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Deps struct {
+	Storage Storage
+	Blobs   BlobStore
+}
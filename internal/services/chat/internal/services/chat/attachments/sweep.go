@@ -0,0 +1,78 @@
+package attachments
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sweepBatchSize is the number of orphaned attachments removed per transaction.
+const sweepBatchSize = 500
+
+// Start sweeps orphaned attachments right away and then every sweep interval
+// until Stop. Every instance may sweep; rows being removed by one instance are
+// skipped by the others.
+func (s *Service) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.cfg.SweepInterval)
+		defer ticker.Stop()
+
+		for {
+			s.SweepOrphans(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrupts the current sweep, if any, and waits for it to end.
+func (s *Service) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// SweepOrphans removes the attachments that no message referenced within the
+// orphan TTL of their upload, with their contents.
+func (s *Service) SweepOrphans(ctx context.Context) {
+	before := time.Now().Add(-s.cfg.OrphanTTL)
+
+	swept := 0
+	for ctx.Err() == nil {
+		orphans, err := s.storage.DeleteOrphanedAttachments(ctx, before, sweepBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("Failed to delete orphaned attachments", zap.Error(err))
+			}
+			break
+		}
+
+		// The attachment rows are gone already, so contents failing to be
+		// deleted are only logged.
+		if err := s.DeleteContents(ctx, orphans); err != nil {
+			s.logger.Warn("Failed to delete orphaned attachment contents", zap.Error(err))
+		}
+
+		swept += len(orphans)
+		if len(orphans) < sweepBatchSize {
+			break
+		}
+	}
+
+	if swept > 0 {
+		s.logger.Info("Swept orphaned attachments", zap.Int("attachments", swept))
+	}
+}
@@ -0,0 +1,113 @@
+package attachments
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif" // Registers the GIF decoder.
+	"image/jpeg"
+	_ "image/png" // Registers the PNG decoder.
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxThumbnailSourcePixels keeps huge or maliciously crafted images from
+	// being decoded: larger images get no thumbnail. Decoding takes up to 8
+	// bytes per pixel, so 16 megapixels stay within 128 MB.
+	maxThumbnailSourcePixels = 16_000_000
+	// maxConcurrentThumbnails bounds the images decoded at once per instance;
+	// further uploads wait for a free slot.
+	maxConcurrentThumbnails = 2
+	// thumbnailSamples is the number of source pixels averaged per axis for
+	// each thumbnail pixel.
+	thumbnailSamples = 4
+	thumbnailQuality = 80
+)
+
+// sniffContentType detects the type of a file from its first bytes, following
+// the algorithm browsers use. Parameters such as the charset are dropped.
+func sniffContentType(data []byte) string {
+	contentType := http.DetectContentType(data)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = strings.TrimSpace(contentType[:i])
+	}
+	return contentType
+}
+
+// imageSize returns the dimensions of an image without decoding it.
+func imageSize(data []byte) (int, int, bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, false
+	}
+	return cfg.Width, cfg.Height, true
+}
+
+// makeThumbnail scales an image down to fit a size x size box and encodes it
+// as JPEG over a white background. Images already fitting the box are only
+// re-encoded.
+func makeThumbnail(data []byte, width, height, size int) ([]byte, error) {
+	if width <= 0 || height <= 0 || width*height > maxThumbnailSourcePixels {
+		return nil, errors.New("image dimensions out of range")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode image")
+	}
+
+	dstWidth, dstHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			dstWidth, dstHeight = size, max(1, height*size/width)
+		} else {
+			dstWidth, dstHeight = max(1, width*size/height), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	scale(dst, src)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, errors.Wrap(err, "failed to encode thumbnail")
+	}
+	return buf.Bytes(), nil
+}
+
+// scale draws src over dst, resized to the bounds of dst. Each pixel averages
+// a grid of samples of the area of src it covers, which is cheap and smooth
+// enough for thumbnails.
+func scale(dst *image.RGBA, src image.Image) {
+	sb := src.Bounds()
+	dw, dh := dst.Bounds().Dx(), dst.Bounds().Dy()
+	sw, sh := sb.Dx(), sb.Dy()
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var r, g, b, a uint32
+			for sy := 0; sy < thumbnailSamples; sy++ {
+				for sx := 0; sx < thumbnailSamples; sx++ {
+					px := sb.Min.X + (x*thumbnailSamples+sx)*sw/(dw*thumbnailSamples)
+					py := sb.Min.Y + (y*thumbnailSamples+sy)*sh/(dh*thumbnailSamples)
+					pr, pg, pb, pa := src.At(px, py).RGBA()
+					r, g, b, a = r+pr, g+pg, b+pb, a+pa
+				}
+			}
+
+			const n = thumbnailSamples * thumbnailSamples
+			// Colors are premultiplied, so blending over the white background
+			// adds the part of white the source lets through.
+			white := 0xffff - a/n
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r/n + white) >> 8),
+				G: uint8((g/n + white) >> 8),
+				B: uint8((b/n + white) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+}
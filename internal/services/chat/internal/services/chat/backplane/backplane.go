@@ -0,0 +1,11 @@
+// Package backplane fans chat room events out to every running chat service instance.
+package backplane
+
+import "github.com/pkg/errors"
+
+var (
+	// ErrClosed is returned when publishing through a closed backplane.
+	ErrClosed = errors.New("backplane is closed")
+	// ErrPayloadTooLarge is returned when an event does not fit into a single notification.
+	ErrPayloadTooLarge = errors.New("backplane payload is too large")
+)
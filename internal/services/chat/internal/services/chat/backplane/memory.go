@@ -0,0 +1,52 @@
+package backplane
+
+import (
+	"context"
+	"sync"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+)
+
+// Memory is an in-process backplane. It only connects services sharing the
+// same instance and is meant for single-node deployments and tests.
+type Memory struct {
+	handlers []func(*entities.BackplaneMessage)
+	closed   bool
+	mu       sync.RWMutex
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish synchronously delivers the message to every subscriber.
+func (m *Memory) Publish(_ context.Context, msg *entities.BackplaneMessage) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return ErrClosed
+	}
+	handlers := make([]func(*entities.BackplaneMessage), len(m.handlers))
+	copy(handlers, m.handlers)
+	m.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+
+	return nil
+}
+
+func (m *Memory) Subscribe(handler func(*entities.BackplaneMessage)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.handlers = nil
+	return nil
+}
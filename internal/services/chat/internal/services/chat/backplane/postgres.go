@@ -0,0 +1,176 @@
+package backplane
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxNotifyPayload is the largest payload accepted by Postgres NOTIFY.
+	// Larger logged events are sent by reference.
+	maxNotifyPayload = 8000
+	// listenerPingInterval is how often an idle listener connection is checked.
+	listenerPingInterval = 90 * time.Second
+)
+
+// PostgresConfig configures the Postgres LISTEN/NOTIFY backplane.
+type PostgresConfig struct {
+	DSN                  string
+	Channel              string
+	MinReconnectInterval time.Duration
+	MaxReconnectInterval time.Duration
+}
+
+// Postgres fans events out to every chat instance through Postgres LISTEN/NOTIFY.
+// Notifications are published over the shared connection pool and received on a
+// dedicated listener connection that reconnects automatically.
+type Postgres struct {
+	db       *sql.DB
+	listener *pq.Listener
+	channel  string
+	logger   *zap.Logger
+	handlers []func(*entities.BackplaneMessage)
+	mu       sync.RWMutex
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewPostgres(db *sql.DB, cfg PostgresConfig, logger *zap.Logger) (*Postgres, error) {
+	p := &Postgres{
+		db:      db,
+		channel: cfg.Channel,
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+
+	p.listener = pq.NewListener(cfg.DSN, cfg.MinReconnectInterval, cfg.MaxReconnectInterval, p.onListenerEvent)
+	if err := p.listener.Listen(cfg.Channel); err != nil {
+		p.listener.Close()
+		return nil, errors.Wrap(err, "failed to listen on backplane channel")
+	}
+
+	p.wg.Add(1)
+	go p.receive()
+
+	return p, nil
+}
+
+func (p *Postgres) Publish(ctx context.Context, msg *entities.BackplaneMessage) error {
+	select {
+	case <-p.done:
+		return ErrClosed
+	default:
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal backplane message")
+	}
+	if len(payload) >= maxNotifyPayload {
+		if msg.Event == nil || msg.Event.Seq == 0 {
+			return ErrPayloadTooLarge
+		}
+
+		// Logged events are sent by reference instead.
+		ref := *msg
+		event := *msg.Event
+		event.Payload = nil
+		ref.Event = &event
+		ref.Reference = true
+		if payload, err = json.Marshal(&ref); err != nil {
+			return errors.Wrap(err, "failed to marshal backplane message")
+		}
+	}
+
+	if _, err := p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", p.channel, string(payload)); err != nil {
+		return errors.Wrap(err, "failed to notify backplane channel")
+	}
+
+	return nil
+}
+
+func (p *Postgres) Subscribe(handler func(*entities.BackplaneMessage)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, handler)
+}
+
+// Close stops the listener and waits for the delivery loop to exit.
+func (p *Postgres) Close() error {
+	select {
+	case <-p.done:
+		return nil
+	default:
+		close(p.done)
+	}
+
+	err := p.listener.Close()
+	p.wg.Wait()
+
+	return err
+}
+
+func (p *Postgres) receive() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case notification := <-p.listener.Notify:
+			// A nil notification is sent after the listener reconnected;
+			// anything published in between is lost.
+			if notification == nil {
+				continue
+			}
+			p.dispatch(notification.Extra)
+		case <-time.After(listenerPingInterval):
+			go func() {
+				if err := p.listener.Ping(); err != nil {
+					p.logger.Warn("Backplane listener ping failed", zap.Error(err))
+				}
+			}()
+		}
+	}
+}
+
+func (p *Postgres) dispatch(payload string) {
+	var msg entities.BackplaneMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		p.logger.Error("Failed to unmarshal backplane message", zap.Error(err))
+		return
+	}
+	if msg.Event == nil {
+		return
+	}
+
+	p.mu.RLock()
+	handlers := make([]func(*entities.BackplaneMessage), len(p.handlers))
+	copy(handlers, p.handlers)
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(&msg)
+	}
+}
+
+func (p *Postgres) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnected:
+		p.logger.Info("Backplane listener connected", zap.String("channel", p.channel))
+	case pq.ListenerEventDisconnected:
+		p.logger.Warn("Backplane listener disconnected", zap.Error(err))
+	case pq.ListenerEventReconnected:
+		p.logger.Info("Backplane listener reconnected", zap.String("channel", p.channel))
+	case pq.ListenerEventConnectionAttemptFailed:
+		p.logger.Error("Backplane listener connection attempt failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,40 @@
+// Package blobstore keeps the contents of chat attachments. Blobs are addressed
+// by slash-separated keys. Whichever store holds them, reading a missing blob
+// fails with an error matching fs.ErrNotExist.
+package blobstore
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidKey is returned for keys that could escape the store, such as keys
+// with empty or dot segments.
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// validateKey accepts keys made of non-empty segments of letters, digits,
+// dashes, underscores and dots, other than "." and "..".
+func validateKey(key string) error {
+	if key == "" {
+		return ErrInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return errors.Wrap(ErrInvalidKey, key)
+		}
+		for _, r := range segment {
+			if !isKeyRune(r) {
+				return errors.Wrap(ErrInvalidKey, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isKeyRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '-' || r == '_' || r == '.'
+}
@@ -15,27 +15,79 @@ import (
 
 type Service struct {
 	storage     Storage
+	website     WebsiteService
+	backplane   Backplane
+	instanceID  string
 	logger      *zap.Logger
 	rooms       map[uuid.UUID]*entities.Room
 	mu          sync.RWMutex
 	cleanupTick *time.Ticker
+	typing      map[typingKey]*typingState
+	typingMu    sync.Mutex
+
+	presence        map[uuid.UUID]*presenceState
+	presenceSubs    map[uuid.UUID]map[uuid.UUID]entities.Connection // watched user ID -> connection ID -> connection.
+	presenceWatches map[uuid.UUID]map[uuid.UUID]struct{}            // connection ID -> watched user IDs.
+	presenceMu      sync.Mutex
+	presenceWriteMu sync.Mutex
+	presenceTick    *time.Ticker
+
+	slowModes  map[uuid.UUID]time.Duration // Slow mode intervals, kept current by slow mode events.
+	slowModeMu sync.Mutex
+
+	attachments AttachmentContents
+
+	scripts      ScriptRunner
+	scriptCache  map[uuid.UUID]*cachedScripts
+	scriptMu     sync.Mutex
+	scheduleTick *time.Ticker
+
+	webhookTick       *time.Ticker
+	webhookWake       chan struct{}
+	unsubscribedRooms map[uuid.UUID]time.Time // Room ID -> until when it is not published to.
+	webhookMu         sync.Mutex
 }
 
 func NewService(deps Deps, logger *zap.Logger) *Service {
 	s := &Service{
-		storage: deps.Storage,
-		logger:  logger,
-		rooms:   make(map[uuid.UUID]*entities.Room),
+		storage:    deps.Storage,
+		website:    deps.WebsiteService,
+		backplane:  deps.Backplane,
+		instanceID: uuid.NewString(),
+		logger:     logger,
+		rooms:      make(map[uuid.UUID]*entities.Room),
+		typing:     make(map[typingKey]*typingState),
+
+		presence:        make(map[uuid.UUID]*presenceState),
+		presenceSubs:    make(map[uuid.UUID]map[uuid.UUID]entities.Connection),
+		presenceWatches: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+
+		slowModes: make(map[uuid.UUID]time.Duration),
+
+		attachments: deps.Attachments,
+
+		scripts:     deps.Scripts,
+		scriptCache: make(map[uuid.UUID]*cachedScripts),
+
+		webhookWake:       make(chan struct{}, 1),
+		unsubscribedRooms: make(map[uuid.UUID]time.Time),
 	}
 
+	s.backplane.Subscribe(s.handleBackplaneMessage)
 	s.startCleanupTicker()
+	s.startPresenceTicker()
+	s.startScheduleTicker()
+	s.startWebhookRelay()
 	return s
 }
 
-func (s *Service) Connect(ctx context.Context, roomID, userID uuid.UUID, conn entities.Connection) error {
+// Connect adds a connection of the user to the room. With sinceSeq the client
+// resumes after a reconnect: the events it missed are replayed before live events.
+func (s *Service) Connect(ctx context.Context, roomID, userID uuid.UUID, conn entities.Connection, sinceSeq *int64) error {
 	room := s.getOrCreateRoom(roomID)
 
-	if err := s.storage.AddParticipant(ctx, roomID, userID); err != nil {
+	joined, err := s.storage.AddParticipant(ctx, roomID, userID)
+	if err != nil {
 		s.logger.Error("Failed to add participant",
 			zap.Error(err),
 			zap.String("room_id", roomID.String()),
@@ -43,107 +95,213 @@ func (s *Service) Connect(ctx context.Context, roomID, userID uuid.UUID, conn en
 		)
 		return errors.Wrap(err, "failed to add participant")
 	}
+	if joined {
+		s.publishUserJoined(roomID, userID)
+		s.runJoinHooks(roomID, userID)
+	}
 
-	room.AddConnection(userID, conn)
+	// Live events are held back until the replay is sent so that the client
+	// receives the events of the room in order.
+	var resuming *resumingConnection
+	if sinceSeq != nil {
+		resuming = newResumingConnection(conn)
+		conn = resuming
+	}
 
-	userConnectEvent := &entities.Event{
-		Type:      entities.EventUserConnected,
-		RoomID:    roomID,
-		UserID:    userID,
-		Timestamp: time.Now(),
+	// Additional devices of a user already in the room join silently.
+	if room.AddConnection(userID, conn) {
+		userConnectEvent := &entities.Event{
+			Type:      entities.EventUserConnected,
+			RoomID:    roomID,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		}
+
+		s.broadcast(ctx, userConnectEvent, &userID)
 	}
 
-	room.BroadcastEvent(userConnectEvent, &userID)
+	s.sendTopic(ctx, roomID, userID, conn)
+
+	s.trackPresence(ctx, userID, conn.ID())
+
+	if resuming != nil {
+		resuming.finishReplay(s.replay(ctx, roomID, *sinceSeq, resuming.Connection))
+	}
 
 	s.logger.Info("User connected to room",
 		zap.String("room_id", roomID.String()),
 		zap.String("user_id", userID.String()),
+		zap.String("connection_id", conn.ID().String()),
 		zap.String("connection_type", string(conn.Type())),
 	)
 
 	return nil
 }
 
-func (s *Service) Disconnect(ctx context.Context, roomID, userID uuid.UUID) error {
-	s.mu.RLock()
-	room, exists := s.rooms[roomID]
-	s.mu.RUnlock()
+// Disconnect removes one connection of the user. The room only learns that the
+// user left once the user's last connection is gone.
+func (s *Service) Disconnect(ctx context.Context, roomID, userID, connectionID uuid.UUID) error {
+	s.untrackPresence(ctx, userID, connectionID)
 
-	if !exists {
+	room := s.getRoom(roomID)
+	if room == nil {
 		return nil
 	}
 
-	disconnectEvent := &entities.Event{
-		Type:      entities.EventUserDisconnected,
-		RoomID:    roomID,
-		UserID:    userID,
-		Timestamp: time.Now(),
-	}
+	// Room membership is kept in storage after disconnecting so that
+	// unread counts can still be computed for the rooms the user has joined.
+	if room.RemoveConnection(userID, connectionID) {
+		s.clearTyping(roomID, userID)
 
-	room.BroadcastEvent(disconnectEvent, nil)
+		disconnectEvent := &entities.Event{
+			Type:      entities.EventUserDisconnected,
+			RoomID:    roomID,
+			UserID:    userID,
+			Timestamp: time.Now(),
+		}
 
-	if err := s.storage.RemoveParticipant(ctx, roomID, userID); err != nil {
-		s.logger.Error("Failed to remove participant",
-			zap.Error(err),
-			zap.String("room_id", roomID.String()),
-			zap.String("user_id", userID.String()),
-		)
+		s.broadcast(ctx, disconnectEvent, nil)
 	}
 
-	room.RemoveConnection(userID)
-
 	s.cleanupRoomIfEmpty(roomID)
 
 	s.logger.Info("User disconnected from room",
 		zap.String("room_id", roomID.String()),
 		zap.String("user_id", userID.String()),
+		zap.String("connection_id", connectionID.String()),
 	)
 
 	return nil
 }
 
-func (s *Service) HandleMessage(ctx context.Context, roomID, userID uuid.UUID, content string, event *entities.Event) error {
-	isParticipant, err := s.storage.IsParticipant(ctx, roomID, userID)
+// HandleMessage persists a message and broadcasts it to the room. Messages with a
+// client message ID are stored at most once per user: a retry returns the message
+// stored the first time without broadcasting it again. In slow mode a user may
+// only send one message per interval, and muted users may not send any. The
+// scripts of the room may change or reject the message and reply to it. The
+// message may reference attachments the user uploaded to the room and has not
+// sent yet.
+func (s *Service) HandleMessage(ctx context.Context, roomID, userID uuid.UUID, content, clientMsgID string, parentID *uuid.UUID, attachmentIDs []uuid.UUID) (*entities.Message, error) {
+	attachments, err := s.getNewAttachments(ctx, roomID, userID, attachmentIDs)
 	if err != nil {
-		return errors.Wrap(err, "failed to verify participant")
-	}
-	if !isParticipant {
-		return errors.New("user is not a participant of this room")
+		return nil, err
 	}
 
-	room := s.getRoom(roomID)
-	if room == nil {
-		return entities.ErrRoomNotFound
+	return s.sendMessage(ctx, &entities.Message{
+		ID:          uuid.New(),
+		RoomID:      roomID,
+		UserID:      userID,
+		ClientMsgID: clientMsgID,
+		ParentID:    parentID,
+		Content:     content,
+		Attachments: attachments,
+		Timestamp:   time.Now(),
+	})
+}
+
+// HandleIntegrationMessage posts a message of an incoming webhook to its room.
+// It is handled like a message of a user authored by the integration, except
+// that integrations are not participants and need no connection to the room:
+// the message reaches the connected users through the backplane. Moderators
+// may mute an integration by its ID.
+func (s *Service) HandleIntegrationMessage(ctx context.Context, integration *entities.Integration, content, clientMsgID string) (*entities.Message, error) {
+	return s.sendMessage(ctx, &entities.Message{
+		ID:          uuid.New(),
+		RoomID:      integration.RoomID,
+		UserID:      integration.ID,
+		ClientMsgID: clientMsgID,
+		Kind:        entities.MessageKindIntegration,
+		AuthorName:  integration.Name,
+		Content:     content,
+		Timestamp:   time.Now(),
+	})
+}
+
+// FindSentMessage returns the message a user or integration already stored
+// with a client message ID, or nil when the ID is new. A retried message is
+// answered with the stored one before any limit or hook applies, so that the
+// retry neither counts against the limits nor runs the hooks again.
+func (s *Service) FindSentMessage(ctx context.Context, userID uuid.UUID, clientMsgID string) (*entities.Message, error) {
+	if clientMsgID == "" {
+		return nil, nil
 	}
 
-	msg := &entities.Message{
-		ID:        uuid.New(),
-		RoomID:    roomID,
-		UserID:    userID,
-		Content:   content,
-		Timestamp: time.Now(),
+	msg, err := s.storage.GetMessageByClientMsgID(ctx, userID, clientMsgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up client message ID")
 	}
+	return msg, nil
+}
+
+// sendMessage checks and posts a message of a user or an integration, see
+// HandleMessage and HandleIntegrationMessage.
+func (s *Service) sendMessage(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+	roomID, userID := msg.RoomID, msg.UserID
 
-	if err := s.storage.SaveMessage(ctx, msg); err != nil {
-		return errors.Wrap(err, "failed to save message")
+	sent, err := s.FindSentMessage(ctx, userID, msg.ClientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	if sent != nil {
+		s.logger.Debug("Duplicate message ignored",
+			zap.String("room_id", roomID.String()),
+			zap.String("user_id", userID.String()),
+			zap.String("message_id", sent.ID.String()),
+			zap.String("client_msg_id", msg.ClientMsgID),
+		)
+		return sent, nil
 	}
 
-	if event == nil {
-		msgJSON, err := json.Marshal(msg)
+	if msg.Kind != entities.MessageKindIntegration {
+		isParticipant, err := s.storage.IsParticipant(ctx, roomID, userID)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal message")
+			return nil, errors.Wrap(err, "failed to verify participant")
+		}
+		if !isParticipant {
+			return nil, errors.New("user is not a participant of this room")
 		}
 
-		event = &entities.Event{
-			Type:      entities.EventNewMessage,
-			RoomID:    roomID,
-			UserID:    userID,
-			Payload:   msgJSON,
-			Timestamp: time.Now(),
+		if s.getRoom(roomID) == nil {
+			return nil, entities.ErrRoomNotFound
 		}
 	}
 
-	room.BroadcastEvent(event, nil)
+	if msg.ParentID != nil {
+		if _, err := s.getThreadParent(ctx, roomID, *msg.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkMute(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSlowMode(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+
+	replies, err := s.runMessageHooks(ctx, msg)
+	if err != nil {
+		s.postScriptReplies(ctx, replies)
+		return nil, err
+	}
+
+	stored, err := s.postMessage(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.ID != msg.ID {
+		s.logger.Debug("Duplicate message ignored",
+			zap.String("room_id", roomID.String()),
+			zap.String("user_id", userID.String()),
+			zap.String("message_id", stored.ID.String()),
+			zap.String("client_msg_id", msg.ClientMsgID),
+		)
+		return stored, nil
+	}
+
+	s.postScriptReplies(ctx, replies)
 
 	s.logger.Debug("Message handled",
 		zap.String("room_id", roomID.String()),
@@ -151,10 +309,71 @@ func (s *Service) HandleMessage(ctx context.Context, roomID, userID uuid.UUID, c
 		zap.String("message_id", msg.ID.String()),
 	)
 
-	return nil
+	return msg, nil
 }
 
-func (s *Service) GetRoomMessages(ctx context.Context, roomID uuid.UUID, limit, offset int) ([]*entities.Message, error) {
+// GetRoomMessages returns a page of room history. Without cursors the newest messages are returned;
+// before pages backwards in time and after pages forwards. At most one cursor may be set.
+// History is available to every participant of the room, connected or not.
+func (s *Service) GetRoomMessages(ctx context.Context, roomID, userID uuid.UUID, before, after *entities.MessageCursor, limit int) (*entities.MessagePage, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if before != nil && after != nil {
+		return nil, entities.ErrInvalidCursor
+	}
+
+	isParticipant, err := s.storage.IsParticipant(ctx, roomID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify participant")
+	}
+	if !isParticipant {
+		return nil, entities.ErrForbidden
+	}
+
+	// Read the sequence first: events racing with the page are replayed again
+	// rather than missed by a client resuming from it.
+	lastSeq, err := s.storage.GetRoomSeq(ctx, roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room sequence")
+	}
+
+	messages, hasMore, err := s.storage.GetMessagesPage(ctx, roomID, before, after, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get messages")
+	}
+
+	if err := s.enrichMessages(ctx, messages); err != nil {
+		return nil, err
+	}
+
+	page := &entities.MessagePage{Messages: messages, LastSeq: lastSeq}
+	if len(messages) == 0 {
+		return page, nil
+	}
+
+	oldest := entities.CursorOf(messages[0])
+	newest := entities.CursorOf(messages[len(messages)-1])
+
+	// Paging in one direction proves there is more history in the other one.
+	hasOlder := after != nil || hasMore
+	hasNewer := (after != nil && hasMore) || before != nil
+
+	if hasOlder {
+		page.PrevCursor = oldest.String()
+	}
+	if hasNewer {
+		page.NextCursor = newest.String()
+	}
+
+	return page, nil
+}
+
+// GetThreadMessages returns the parent message of a thread together with a page of its replies.
+func (s *Service) GetThreadMessages(ctx context.Context, roomID, parentID uuid.UUID, limit, offset int) (*entities.Message, []*entities.Message, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -165,20 +384,501 @@ func (s *Service) GetRoomMessages(ctx context.Context, roomID uuid.UUID, limit,
 		offset = 0
 	}
 
-	messages, err := s.storage.GetLastMessages(ctx, roomID, limit, offset)
+	parent, err := s.getThreadParent(ctx, roomID, parentID)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get messages")
+		return nil, nil, err
 	}
 
-	room := s.getRoom(roomID)
-	if room == nil {
-		return nil, entities.ErrRoomNotFound
+	replies, err := s.storage.GetThreadMessages(ctx, parentID, limit, offset)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get thread messages")
+	}
+
+	if err := s.enrichMessages(ctx, append([]*entities.Message{parent}, replies...)); err != nil {
+		return nil, nil, err
+	}
+
+	return parent, replies, nil
+}
+
+// EditMessage replaces the content of a message and notifies the room.
+// Only the author of the message or the room owner may edit it. The on_message
+// hooks of the room may reject or rewrite the new content.
+func (s *Service) EditMessage(ctx context.Context, roomID, userID, messageID uuid.UUID, content string) (*entities.Message, error) {
+	msg, err := s.getModifiableMessage(ctx, roomID, userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scripts see the new content like that of a new message, so that an edit
+	// cannot slip past a script that would have rejected or rewritten it.
+	edited := *msg
+	edited.Content = content
+	replies, err := s.runMessageHooks(ctx, &edited)
+	if err != nil {
+		s.postScriptReplies(ctx, replies)
+		return nil, err
+	}
+	content = edited.Content
+
+	editedAt := time.Now()
+	if err := s.storage.UpdateMessageContent(ctx, messageID, content, editedAt); err != nil {
+		return nil, errors.Wrap(err, "failed to update message")
 	}
 
-	return messages, nil
+	msg.Content = content
+	msg.EditedAt = &editedAt
+
+	if err := s.loadAttachments(ctx, []*entities.Message{msg}); err != nil {
+		return nil, err
+	}
+
+	if err := s.broadcastMessageEvent(ctx, entities.EventMessageEdited, userID, msg); err != nil {
+		return nil, err
+	}
+
+	s.postScriptReplies(ctx, replies)
+
+	s.logger.Debug("Message edited",
+		zap.String("room_id", roomID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("message_id", messageID.String()),
+	)
+
+	return msg, nil
+}
+
+// DeleteMessage soft-deletes a message and notifies the room. Its attachments
+// are removed with their contents. Only the author of the message or the room
+// owner may delete it.
+func (s *Service) DeleteMessage(ctx context.Context, roomID, userID, messageID uuid.UUID) error {
+	msg, err := s.getModifiableMessage(ctx, roomID, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	attachments, err := s.storage.SoftDeleteMessage(ctx, messageID, deletedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete message")
+	}
+	if len(attachments) > 0 {
+		// The attachment rows are gone already, so contents failing to be
+		// deleted are only logged.
+		if err := s.attachments.DeleteContents(ctx, attachments); err != nil {
+			s.logger.Warn("Failed to delete attachments of deleted message",
+				zap.Error(err),
+				zap.String("message_id", messageID.String()),
+			)
+		}
+	}
+
+	msg.Content = ""
+	msg.DeletedAt = &deletedAt
+	msg.Attachments = nil
+
+	if err := s.broadcastMessageEvent(ctx, entities.EventMessageDeleted, userID, msg); err != nil {
+		return err
+	}
+
+	s.logger.Debug("Message deleted",
+		zap.String("room_id", roomID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("message_id", messageID.String()),
+	)
+
+	return nil
+}
+
+// AddReaction adds the user's emoji reaction to a message and broadcasts the new totals.
+func (s *Service) AddReaction(ctx context.Context, roomID, userID, messageID uuid.UUID, emoji string) error {
+	if err := s.checkReactable(ctx, roomID, userID, messageID); err != nil {
+		return err
+	}
+
+	if err := s.storage.AddReaction(ctx, messageID, userID, emoji); err != nil {
+		return errors.Wrap(err, "failed to add reaction")
+	}
+
+	return s.broadcastReactions(ctx, roomID, userID, messageID)
+}
+
+// RemoveReaction removes the user's emoji reaction from a message and broadcasts the new totals.
+func (s *Service) RemoveReaction(ctx context.Context, roomID, userID, messageID uuid.UUID, emoji string) error {
+	if err := s.checkReactable(ctx, roomID, userID, messageID); err != nil {
+		return err
+	}
+
+	if err := s.storage.RemoveReaction(ctx, messageID, userID, emoji); err != nil {
+		return errors.Wrap(err, "failed to remove reaction")
+	}
+
+	return s.broadcastReactions(ctx, roomID, userID, messageID)
+}
+
+// MarkRead advances the user's read cursor in the room up to the given message
+// and broadcasts a read receipt when the cursor moved.
+func (s *Service) MarkRead(ctx context.Context, roomID, userID, messageID uuid.UUID) error {
+	msg, err := s.storage.GetMessage(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.RoomID != roomID {
+		return entities.ErrMessageNotFound
+	}
+
+	advanced, err := s.storage.AdvanceReadCursor(ctx, roomID, userID, messageID, msg.Timestamp)
+	if err != nil {
+		return errors.Wrap(err, "failed to advance read cursor")
+	}
+	if !advanced {
+		return nil
+	}
+
+	payload, err := json.Marshal(entities.ReadReceipt{
+		MessageID: messageID,
+		ReadAt:    msg.Timestamp,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal read receipt")
+	}
+
+	s.broadcast(ctx, &entities.Event{
+		Type:      entities.EventReadReceipt,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}, nil)
+
+	return nil
+}
+
+// GetUnreadCounts returns the number of unread messages in every room the user has joined.
+func (s *Service) GetUnreadCounts(ctx context.Context, userID uuid.UUID) ([]entities.UnreadCount, error) {
+	roomIDs, err := s.storage.GetUserRooms(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user rooms")
+	}
+
+	counts, err := s.storage.CountUnread(ctx, userID, roomIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count unread messages")
+	}
+
+	result := make([]entities.UnreadCount, len(roomIDs))
+	for i, roomID := range roomIDs {
+		result[i] = entities.UnreadCount{
+			RoomID: roomID,
+			Count:  counts[roomID],
+		}
+	}
+
+	return result, nil
+}
+
+// SearchMessages runs a full-text search restricted to rooms the user has joined.
+func (s *Service) SearchMessages(ctx context.Context, userID uuid.UUID, filter entities.SearchFilter) ([]entities.SearchResult, error) {
+	var roomIDs []uuid.UUID
+
+	if filter.RoomID != nil {
+		isParticipant, err := s.storage.IsParticipant(ctx, *filter.RoomID, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to verify participant")
+		}
+		if !isParticipant {
+			return nil, entities.ErrForbidden
+		}
+		roomIDs = []uuid.UUID{*filter.RoomID}
+	} else {
+		userRooms, err := s.storage.GetUserRooms(ctx, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get user rooms")
+		}
+		roomIDs = userRooms
+	}
+
+	results, err := s.storage.SearchMessages(ctx, roomIDs, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search messages")
+	}
+
+	return results, nil
 }
 
 // Internal helper methods.
+
+// checkReactable verifies that the user takes part in the room and the message is a live message of it.
+func (s *Service) checkReactable(ctx context.Context, roomID, userID, messageID uuid.UUID) error {
+	isParticipant, err := s.storage.IsParticipant(ctx, roomID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify participant")
+	}
+	if !isParticipant {
+		return entities.ErrForbidden
+	}
+
+	msg, err := s.storage.GetMessage(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.RoomID != roomID || msg.DeletedAt != nil {
+		return entities.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+func (s *Service) broadcastReactions(ctx context.Context, roomID, userID, messageID uuid.UUID) error {
+	reactions, err := s.storage.GetReactions(ctx, []uuid.UUID{messageID})
+	if err != nil {
+		return errors.Wrap(err, "failed to get reactions")
+	}
+
+	payload, err := json.Marshal(entities.ReactionUpdate{
+		MessageID: messageID,
+		Reactions: reactions[messageID],
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal reactions")
+	}
+
+	s.broadcastPersisted(ctx, &entities.Event{
+		Type:      entities.EventReactionUpdated,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
+
+// getModifiableMessage loads a live message of the room and checks that the
+// user is either its author or the owner of the room.
+func (s *Service) getModifiableMessage(ctx context.Context, roomID, userID, messageID uuid.UUID) (*entities.Message, error) {
+	msg, err := s.storage.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.RoomID != roomID || msg.DeletedAt != nil {
+		return nil, entities.ErrMessageNotFound
+	}
+
+	if msg.UserID == userID {
+		return msg, nil
+	}
+
+	ownerID, err := s.website.GetRoomOwner(ctx, roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room owner")
+	}
+	if ownerID != userID {
+		return nil, entities.ErrForbidden
+	}
+
+	return msg, nil
+}
+
+// getThreadParent loads a message that can hold a thread: a live top-level message of the room.
+func (s *Service) getThreadParent(ctx context.Context, roomID, parentID uuid.UUID) (*entities.Message, error) {
+	parent, err := s.storage.GetMessage(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, entities.ErrMessageNotFound) {
+			return nil, entities.ErrInvalidParent
+		}
+		return nil, errors.Wrap(err, "failed to get parent message")
+	}
+	if parent.RoomID != roomID || parent.ParentID != nil || parent.DeletedAt != nil {
+		return nil, entities.ErrInvalidParent
+	}
+
+	return parent, nil
+}
+
+// enrichMessages attaches reply counts, aggregated reactions and attachments to messages.
+func (s *Service) enrichMessages(ctx context.Context, messages []*entities.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+	}
+
+	counts, err := s.storage.CountReplies(ctx, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to count replies")
+	}
+
+	reactions, err := s.storage.GetReactions(ctx, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to get reactions")
+	}
+
+	for _, msg := range messages {
+		msg.ReplyCount = counts[msg.ID]
+		msg.Reactions = reactions[msg.ID]
+	}
+
+	return s.loadAttachments(ctx, messages)
+}
+
+// postMessage stores a new message and broadcasts it. A message deduplicated by
+// its client message ID is not broadcast again; the stored message is returned.
+func (s *Service) postMessage(ctx context.Context, msg *entities.Message) (*entities.Message, error) {
+	msgJSON, err := json.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal message")
+	}
+
+	event := &entities.Event{
+		Type:      entities.EventNewMessage,
+		RoomID:    msg.RoomID,
+		UserID:    msg.UserID,
+		Payload:   msgJSON,
+		Timestamp: msg.Timestamp,
+	}
+
+	stored, err := s.storage.SaveMessage(ctx, msg, event)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save message")
+	}
+
+	if stored.ID == msg.ID {
+		s.broadcast(ctx, event, nil)
+		s.publishMessageCreated(stored)
+	}
+
+	return stored, nil
+}
+
+func (s *Service) broadcastMessageEvent(ctx context.Context, eventType entities.EventType, userID uuid.UUID, msg *entities.Message) error {
+	msgJSON, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+
+	s.broadcastPersisted(ctx, &entities.Event{
+		Type:      eventType,
+		RoomID:    msg.RoomID,
+		UserID:    userID,
+		Payload:   msgJSON,
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
+
+// broadcastPersisted logs an event of a room change that is already stored and
+// broadcasts it. The change is stored already, so an event that cannot be
+// logged is broadcast either way, and a gap is left in the sequence of the room
+// in its place for resuming clients to reload the history.
+func (s *Service) broadcastPersisted(ctx context.Context, event *entities.Event) {
+	if err := s.storage.AppendEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to log room event",
+			zap.Error(err),
+			zap.String("room_id", event.RoomID.String()),
+			zap.String("event_type", string(event.Type)),
+		)
+		go s.skipRoomSeq(event.RoomID)
+	}
+
+	s.broadcast(ctx, event, nil)
+}
+
+// broadcast delivers an event to the local connections of the room and
+// publishes it to the backplane for the other chat instances.
+// Publishing failures are logged: local delivery has already happened.
+func (s *Service) broadcast(ctx context.Context, event *entities.Event, excludeUserID *uuid.UUID) {
+	s.deliver(event, excludeUserID)
+
+	err := s.backplane.Publish(ctx, &entities.BackplaneMessage{
+		Origin:        s.instanceID,
+		Event:         event,
+		ExcludeUserID: excludeUserID,
+	})
+	if err != nil {
+		s.logger.Error("Failed to publish event to backplane",
+			zap.Error(err),
+			zap.String("room_id", event.RoomID.String()),
+			zap.String("event_type", string(event.Type)),
+		)
+	}
+}
+
+// handleBackplaneMessage re-broadcasts events published by other instances to local connections.
+func (s *Service) handleBackplaneMessage(msg *entities.BackplaneMessage) {
+	if msg.Origin == s.instanceID {
+		return
+	}
+
+	event := msg.Event
+	if msg.Reference {
+		if s.getRoom(event.RoomID) == nil {
+			return
+		}
+
+		var err error
+		if event, err = s.loadLoggedEvent(event.RoomID, event.Seq); err != nil {
+			s.logger.Error("Failed to load referenced backplane event",
+				zap.Error(err),
+				zap.String("room_id", msg.Event.RoomID.String()),
+				zap.Int64("seq", msg.Event.Seq),
+			)
+			return
+		}
+		if event == nil {
+			return
+		}
+	}
+
+	s.deliver(event, msg.ExcludeUserID)
+}
+
+// loadLoggedEvent reads an event from the event log of a room, or returns nil
+// when it is gone, e.g. with its message.
+func (s *Service) loadLoggedEvent(roomID uuid.UUID, seq int64) (*entities.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := s.storage.GetRoomEvents(ctx, roomID, seq-1, seq, 1)
+	if err != nil {
+		return nil, err
+	}
+	if events, err = s.loadEventMessages(ctx, events); err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return events[0], nil
+}
+
+// deliver hands an event to the local connections interested in it: the
+// connections of its room, or the subscribers of a presence event.
+func (s *Service) deliver(event *entities.Event, excludeUserID *uuid.UUID) {
+	if event.Type == entities.EventPresence {
+		s.deliverPresence(event)
+		return
+	}
+	if event.Type == entities.EventSlowModeUpdated {
+		s.applySlowMode(event)
+	}
+
+	room := s.getRoom(event.RoomID)
+	if room == nil {
+		return
+	}
+	room.BroadcastEvent(event, excludeUserID)
+
+	if event.Type == entities.EventUserKicked {
+		s.closeKickedConnections(room, event)
+	}
+}
+
 func (s *Service) getOrCreateRoom(roomID uuid.UUID) *entities.Room {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -218,6 +918,9 @@ func (s *Service) startCleanupTicker() {
 	go func() {
 		for range s.cleanupTick.C {
 			s.cleanupInactiveRooms()
+			s.pruneSlowMode()
+			s.pruneScriptCache()
+			s.pruneUnsubscribedRooms()
 		}
 	}()
 }
@@ -242,6 +945,14 @@ func (s *Service) Cleanup() {
 	if s.cleanupTick != nil {
 		s.cleanupTick.Stop()
 	}
+	if s.scheduleTick != nil {
+		s.scheduleTick.Stop()
+	}
+	if s.webhookTick != nil {
+		s.webhookTick.Stop()
+	}
+
+	s.stopPresence()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Chat is the part of the chat service the built-in commands use.
+type Chat interface {
+	SendEmote(ctx context.Context, roomID, userID uuid.UUID, content, clientMsgID string, parentID *uuid.UUID) (*entities.Message, error)
+	SetTopic(ctx context.Context, roomID, userID uuid.UUID, topic string) error
+	KickUser(ctx context.Context, roomID, userID, targetID uuid.UUID, reason string) error
+	MuteUser(ctx context.Context, roomID, userID, targetID uuid.UUID, duration time.Duration, reason string) (time.Time, error)
+	UnmuteUser(ctx context.Context, roomID, userID, targetID uuid.UUID) error
+}
+
+// RegisterBuiltins registers the commands every chat room offers.
+func RegisterBuiltins(r *Registry, chat Chat) error {
+	builtins := []Command{
+		{
+			Name:        "me",
+			Usage:       "<action>",
+			Description: "Posts an action, e.g. /me waves.",
+			Handler: func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+				if call.Args == "" {
+					return nil, &entities.CommandUsageError{Usage: "/me <action>"}
+				}
+
+				msg, err := chat.SendEmote(ctx, call.RoomID, call.UserID, call.Args, call.ClientMsgID, call.ParentID)
+				if err != nil {
+					return nil, err
+				}
+				return &entities.CommandResult{Message: msg}, nil
+			},
+		},
+		{
+			Name:        "topic",
+			Usage:       "[text]",
+			Description: "Sets the topic of the room; without text, clears it.",
+			Permission:  entities.PermissionModerate,
+			RoomOwner:   true,
+			Handler: func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+				if err := chat.SetTopic(ctx, call.RoomID, call.UserID, call.Args); err != nil {
+					return nil, err
+				}
+
+				if call.Args == "" {
+					return &entities.CommandResult{Reply: "Topic cleared"}, nil
+				}
+				return &entities.CommandResult{Reply: "Topic updated"}, nil
+			},
+		},
+		{
+			Name:        "kick",
+			Usage:       "<user_id> [reason]",
+			Description: "Removes a user from the room. The user may join again.",
+			Permission:  entities.PermissionModerate,
+			RoomOwner:   true,
+			Handler: func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+				targetID, reason, err := parseTarget(call.Args, "/kick <user_id> [reason]")
+				if err != nil {
+					return nil, err
+				}
+
+				if err := chat.KickUser(ctx, call.RoomID, call.UserID, targetID, reason); err != nil {
+					return nil, err
+				}
+				return &entities.CommandResult{Reply: "Kicked " + targetID.String()}, nil
+			},
+		},
+		{
+			Name:        "mute",
+			Usage:       "<user_id> [duration] [reason]",
+			Description: "Stops a user from posting to the room for up to 30 days, by default for 10 minutes. Durations look like 30s, 15m, 2h or 3d.",
+			Permission:  entities.PermissionModerate,
+			RoomOwner:   true,
+			Handler: func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+				const usage = "/mute <user_id> [duration] [reason]"
+
+				targetID, rest, err := parseTarget(call.Args, usage)
+				if err != nil {
+					return nil, err
+				}
+
+				duration := entities.DefaultMuteDuration
+				reason := rest
+				if first, after := splitWord(rest); first != "" {
+					if d, ok := parseDuration(first); ok {
+						duration, reason = d, after
+					}
+				}
+				if duration > entities.MaxMuteDuration {
+					return nil, &entities.CommandUsageError{Usage: usage}
+				}
+
+				until, err := chat.MuteUser(ctx, call.RoomID, call.UserID, targetID, duration, reason)
+				if err != nil {
+					return nil, err
+				}
+				return &entities.CommandResult{
+					Reply: fmt.Sprintf("Muted %s until %s", targetID, until.UTC().Format(time.RFC3339)),
+				}, nil
+			},
+		},
+		{
+			Name:        "unmute",
+			Usage:       "<user_id>",
+			Description: "Lets a muted user post to the room again.",
+			Permission:  entities.PermissionModerate,
+			RoomOwner:   true,
+			Handler: func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+				targetID, _, err := parseTarget(call.Args, "/unmute <user_id>")
+				if err != nil {
+					return nil, err
+				}
+
+				if err := chat.UnmuteUser(ctx, call.RoomID, call.UserID, targetID); err != nil {
+					return nil, err
+				}
+				return &entities.CommandResult{Reply: "Unmuted " + targetID.String()}, nil
+			},
+		},
+		{
+			Name:        "help",
+			Usage:       "[command]",
+			Description: "Lists the commands you can run here, or describes one of them.",
+			Handler: func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+				return r.help(ctx, call)
+			},
+		},
+	}
+
+	for _, cmd := range builtins {
+		if err := r.Register(cmd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) help(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+	if call.Args != "" {
+		name := strings.ToLower(strings.TrimPrefix(call.Args, "/"))
+		info := r.Info(name)
+		if info == nil {
+			return nil, errors.Wrapf(entities.ErrUnknownCommand, "/%s", name)
+		}
+		return &entities.CommandResult{Reply: info.Usage + " - " + info.Description}, nil
+	}
+
+	infos, err := r.Available(ctx, &call.RoomID, call.UserID, call.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, len(infos))
+	for i, info := range infos {
+		lines[i] = info.Usage + " - " + info.Description
+	}
+	return &entities.CommandResult{Reply: strings.Join(lines, "\n")}, nil
+}
+
+// parseTarget reads the user ID a moderation command starts with and returns
+// the remaining arguments.
+func parseTarget(args, usage string) (uuid.UUID, string, error) {
+	first, rest := splitWord(args)
+	targetID, err := uuid.Parse(first)
+	if err != nil {
+		return uuid.Nil, "", &entities.CommandUsageError{Usage: usage}
+	}
+	return targetID, rest, nil
+}
+
+// splitWord splits the first word off a string.
+func splitWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexFunc(s, unicode.IsSpace); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+	return s, ""
+}
+
+// parseDuration parses a Go duration, also accepting whole days such as "3d".
+func parseDuration(s string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
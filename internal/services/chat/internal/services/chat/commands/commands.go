@@ -0,0 +1,184 @@
+// Package commands holds the slash commands of the chat service. Commands are
+// kept in a Registry; packages add their own with Register, and each command
+// declares the permission needed to run it together with the help text shown
+// to clients.
+package commands
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Handler runs a command. It returns what the command did; an error is reported
+// to the caller only.
+type Handler func(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error)
+
+// Command is a slash command.
+type Command struct {
+	Name        string // Lowercase name used after the slash.
+	Usage       string // Arguments, e.g. "<user_id> [reason]".
+	Description string
+	Permission  string // Permission required to run the command; empty lets every participant run it.
+	RoomOwner   bool   // Room owners may run the command in their rooms without the permission.
+	Handler     Handler
+}
+
+// RoomOwners looks up the owner of a room for commands open to room owners.
+type RoomOwners interface {
+	GetRoomOwner(ctx context.Context, roomID uuid.UUID) (uuid.UUID, error)
+}
+
+// Registry holds the commands known to the chat service. It is safe for
+// concurrent use, so commands may be registered at any time.
+type Registry struct {
+	owners   RoomOwners
+	commands map[string]*Command
+	mu       sync.RWMutex
+}
+
+func NewRegistry(owners RoomOwners) *Registry {
+	return &Registry{
+		owners:   owners,
+		commands: make(map[string]*Command),
+	}
+}
+
+// Register adds a command. Names are unique.
+func (r *Registry) Register(cmd Command) error {
+	if !entities.IsCommandName(cmd.Name) {
+		return errors.Errorf("invalid command name %q", cmd.Name)
+	}
+	if cmd.Handler == nil {
+		return errors.Errorf("command %q has no handler", cmd.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[cmd.Name]; exists {
+		return errors.Errorf("command %q is already registered", cmd.Name)
+	}
+	r.commands[cmd.Name] = &cmd
+
+	return nil
+}
+
+// Execute runs a command after checking that the user may run it.
+func (r *Registry) Execute(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error) {
+	cmd := r.lookup(call.Name)
+	if cmd == nil {
+		return nil, errors.Wrapf(entities.ErrUnknownCommand, "/%s", call.Name)
+	}
+
+	allowed, err := r.allowed(ctx, cmd, call.RoomID, call.UserID, call.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.Wrapf(entities.ErrForbidden, "/%s requires the %s permission", cmd.Name, cmd.Permission)
+	}
+
+	result, err := cmd.Handler(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &entities.CommandResult{}
+	}
+	result.Command = cmd.Name
+
+	return result, nil
+}
+
+// Available lists the commands the user may run in the room, sorted by name.
+// Without a room, commands open to room owners are listed only for users
+// holding their permission.
+func (r *Registry) Available(ctx context.Context, roomID *uuid.UUID, userID uuid.UUID, permissions []string) ([]entities.CommandInfo, error) {
+	r.mu.RLock()
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		cmds = append(cmds, cmd)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+
+	// The owner is looked up at most once, and only when a command needs it.
+	var isOwner *bool
+	infos := make([]entities.CommandInfo, 0, len(cmds))
+	for _, cmd := range cmds {
+		allowed := entities.HasPermission(permissions, cmd.Permission)
+		if !allowed && cmd.RoomOwner && roomID != nil {
+			if isOwner == nil {
+				owner, err := r.isRoomOwner(ctx, *roomID, userID)
+				if err != nil {
+					return nil, err
+				}
+				isOwner = &owner
+			}
+			allowed = *isOwner
+		}
+		if allowed {
+			infos = append(infos, cmd.info())
+		}
+	}
+
+	return infos, nil
+}
+
+// Info describes a command, or returns nil when no command has the name.
+func (r *Registry) Info(name string) *entities.CommandInfo {
+	cmd := r.lookup(name)
+	if cmd == nil {
+		return nil
+	}
+
+	info := cmd.info()
+	return &info
+}
+
+func (r *Registry) lookup(name string) *Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.commands[name]
+}
+
+func (r *Registry) allowed(ctx context.Context, cmd *Command, roomID, userID uuid.UUID, permissions []string) (bool, error) {
+	if entities.HasPermission(permissions, cmd.Permission) {
+		return true, nil
+	}
+	if !cmd.RoomOwner {
+		return false, nil
+	}
+
+	return r.isRoomOwner(ctx, roomID, userID)
+}
+
+func (r *Registry) isRoomOwner(ctx context.Context, roomID, userID uuid.UUID) (bool, error) {
+	ownerID, err := r.owners.GetRoomOwner(ctx, roomID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get room owner")
+	}
+	return ownerID == userID, nil
+}
+
+func (c *Command) info() entities.CommandInfo {
+	usage := "/" + c.Name
+	if c.Usage != "" {
+		usage += " " + c.Usage
+	}
+
+	return entities.CommandInfo{
+		Name:        c.Name,
+		Usage:       usage,
+		Description: c.Description,
+		Permission:  c.Permission,
+	}
+}
@@ -2,24 +2,90 @@ package chat
 
 import (
 	"context"
+	"time"
 
+	"github.com/HexArch/go-chat/internal/pkg/roomscript"
 	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/google/uuid"
 )
 
 // Storage defines the interface for chat data persistence.
 type Storage interface {
-	AddParticipant(ctx context.Context, roomID, userID uuid.UUID) error
+	AddParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
 	RemoveParticipant(ctx context.Context, roomID, userID uuid.UUID) error
 	IsParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
-	SaveMessage(ctx context.Context, message *entities.Message) error
-	GetLastMessages(ctx context.Context, roomID uuid.UUID, limit, offset int) ([]*entities.Message, error)
+	SaveMessage(ctx context.Context, message *entities.Message, event *entities.Event) (*entities.Message, error)
+	AppendEvent(ctx context.Context, event *entities.Event) error
+	SkipRoomSeq(ctx context.Context, roomID uuid.UUID) error
+	GetRoomSeq(ctx context.Context, roomID uuid.UUID) (int64, error)
+	GetRoomEvents(ctx context.Context, roomID uuid.UUID, afterSeq, untilSeq int64, limit int) ([]*entities.Event, error)
+	GetSlowMode(ctx context.Context, roomID uuid.UUID) (time.Duration, error)
+	SetSlowMode(ctx context.Context, roomID uuid.UUID, interval time.Duration) error
+	GetTopic(ctx context.Context, roomID uuid.UUID) (string, error)
+	SetTopic(ctx context.Context, roomID uuid.UUID, topic string) error
+	MuteUser(ctx context.Context, roomID, userID, mutedBy uuid.UUID, until time.Time) error
+	UnmuteUser(ctx context.Context, roomID, userID uuid.UUID, now time.Time) (bool, error)
+	GetMute(ctx context.Context, roomID, userID uuid.UUID, now time.Time) (*time.Time, error)
+	GetMessageByClientMsgID(ctx context.Context, userID uuid.UUID, clientMsgID string) (*entities.Message, error)
+	GetLastMessageTime(ctx context.Context, roomID, userID uuid.UUID) (*time.Time, error)
+	ClaimScriptRun(ctx context.Context, scriptID uuid.UUID, interval time.Duration, now time.Time) (bool, error)
+	GetMessagesPage(ctx context.Context, roomID uuid.UUID, before, after *entities.MessageCursor, limit int) ([]*entities.Message, bool, error)
+	GetThreadMessages(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]*entities.Message, error)
+	CountReplies(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	GetMessage(ctx context.Context, messageID uuid.UUID) (*entities.Message, error)
+	GetMessages(ctx context.Context, messageIDs []uuid.UUID) ([]*entities.Message, error)
+	UpdateMessageContent(ctx context.Context, messageID uuid.UUID, content string, editedAt time.Time) error
+	SoftDeleteMessage(ctx context.Context, messageID uuid.UUID, deletedAt time.Time) ([]entities.Attachment, error)
+	AddReaction(ctx context.Context, messageID, userID uuid.UUID, emoji string) error
+	RemoveReaction(ctx context.Context, messageID, userID uuid.UUID, emoji string) error
+	GetReactions(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID][]entities.Reaction, error)
+	GetAttachments(ctx context.Context, attachmentIDs []uuid.UUID) ([]*entities.Attachment, error)
+	GetMessageAttachments(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID][]entities.Attachment, error)
+	GetUserRooms(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	AdvanceReadCursor(ctx context.Context, roomID, userID, messageID uuid.UUID, readAt time.Time) (bool, error)
+	CountUnread(ctx context.Context, userID uuid.UUID, roomIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	SearchMessages(ctx context.Context, roomIDs []uuid.UUID, filter entities.SearchFilter) ([]entities.SearchResult, error)
+	SavePresence(ctx context.Context, userID uuid.UUID, instanceID string, status entities.PresenceStatus, at time.Time) error
+	TouchPresence(ctx context.Context, instanceID string, at time.Time) error
+	ExpirePresence(ctx context.Context, staleBefore time.Time) ([]uuid.UUID, error)
+	ClearInstancePresence(ctx context.Context, instanceID string, at time.Time) error
+	GetPresence(ctx context.Context, userIDs []uuid.UUID, staleBefore time.Time) ([]entities.Presence, error)
+	QueueWebhookEvent(ctx context.Context, event *entities.WebhookEvent) error
+	ClaimWebhookEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.QueuedWebhookEvent, error)
+	RetryWebhookEvent(ctx context.Context, eventID uuid.UUID, nextAttemptAt time.Time) error
+	DeleteWebhookEvent(ctx context.Context, eventID uuid.UUID) error
 }
 
 type WebsiteService interface {
 	RoomExists(ctx context.Context, roomID uuid.UUID) (bool, error)
+	GetRoomOwner(ctx context.Context, roomID uuid.UUID) (uuid.UUID, error)
+	GetRoomScripts(ctx context.Context, roomID uuid.UUID) ([]*entities.RoomScript, error)
+	GetScheduledScripts(ctx context.Context) ([]*entities.RoomScript, error)
+	PublishRoomEvent(ctx context.Context, event *entities.WebhookEvent) (bool, error)
+}
+
+// Backplane delivers room events to every chat service instance.
+type Backplane interface {
+	Publish(ctx context.Context, msg *entities.BackplaneMessage) error
+	Subscribe(handler func(*entities.BackplaneMessage))
+}
+
+// ScriptRunner runs a hook of a room script in a sandbox.
+type ScriptRunner interface {
+	Run(ctx context.Context, req *roomscript.Request) (*roomscript.Result, error)
+}
+
+// AttachmentContents signs the download URLs of attachments and deletes the
+// contents of removed ones.
+type AttachmentContents interface {
+	SignURLs(attachment *entities.Attachment)
+	DeleteContents(ctx context.Context, attachments []entities.Attachment) error
 }
 
 type Deps struct {
-	Storage Storage
+	Storage        Storage
+	WebsiteService WebsiteService
+	Backplane      Backplane
+	Scripts        ScriptRunner // Optional; room scripts do not run without it.
+	Attachments    AttachmentContents
 }
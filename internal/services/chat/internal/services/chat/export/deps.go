@@ -0,0 +1,30 @@
+package export
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Storage reads the history of rooms.
+type Storage interface {
+	GetRoomHistory(ctx context.Context, roomID uuid.UUID, after *entities.MessageCursor, limit int) ([]*entities.Message, error)
+	GetMessageAttachments(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID][]entities.Attachment, error)
+}
+
+// Users resolves the usernames of message authors.
+type Users interface {
+	GetUsername(ctx context.Context, userID uuid.UUID) (string, bool, error)
+}
+
+// Rooms resolves the names of rooms.
+type Rooms interface {
+	GetRoomName(ctx context.Context, roomID uuid.UUID) (string, error)
+}
+
+type Deps struct {
+	Storage Storage
+	Users   Users
+	Rooms   Rooms
+}
@@ -0,0 +1,182 @@
+// Package export writes the whole history of a room as JSON Lines, a
+// self-contained HTML transcript or plain text, for teams keeping a portable
+// record of a room.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// batchSize is the number of messages read from storage at once. Exports are
+// streamed batch by batch, so rooms of any size are exported in bounded memory.
+const batchSize = 500
+
+// roomInfo describes the exported room in the header of a transcript.
+type roomInfo struct {
+	ID         uuid.UUID
+	Name       string
+	ExportedAt time.Time
+}
+
+// entry is an exported message with its resolved author.
+type entry struct {
+	Message     *entities.Message
+	Author      string
+	Attachments []entities.Attachment
+}
+
+// transcript renders an export in one format.
+type transcript interface {
+	begin(room *roomInfo) error
+	write(e *entry) error
+	end(count int) error
+}
+
+type Exporter struct {
+	storage Storage
+	users   Users
+	rooms   Rooms
+	logger  *zap.Logger
+}
+
+func New(deps Deps, logger *zap.Logger) *Exporter {
+	return &Exporter{
+		storage: deps.Storage,
+		users:   deps.Users,
+		rooms:   deps.Rooms,
+		logger:  logger,
+	}
+}
+
+// Export writes the history of a room in the given format, oldest message
+// first, thread replies included. It does not check who asks for the export.
+// Nothing is written when the room cannot be found; a failure later on leaves
+// a truncated export behind.
+func (e *Exporter) Export(ctx context.Context, roomID uuid.UUID, format entities.ExportFormat, w io.Writer) error {
+	name, err := e.rooms.GetRoomName(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriter(w)
+	out := newTranscript(format, buf)
+	if out == nil {
+		return entities.ErrInvalidExportFormat
+	}
+
+	room := &roomInfo{ID: roomID, Name: name, ExportedAt: time.Now().UTC()}
+	if err := out.begin(room); err != nil {
+		return errors.Wrap(err, "failed to write export")
+	}
+
+	authors := make(map[uuid.UUID]string)
+	count := 0
+
+	var after *entities.MessageCursor
+	for {
+		messages, err := e.storage.GetRoomHistory(ctx, roomID, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			break
+		}
+
+		messageIDs := make([]uuid.UUID, len(messages))
+		for i, msg := range messages {
+			messageIDs[i] = msg.ID
+		}
+		attachments, err := e.storage.GetMessageAttachments(ctx, messageIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			author, err := e.author(ctx, msg, authors)
+			if err != nil {
+				return err
+			}
+
+			if err := out.write(&entry{
+				Message:     msg,
+				Author:      author,
+				Attachments: attachments[msg.ID],
+			}); err != nil {
+				return errors.Wrap(err, "failed to write export")
+			}
+		}
+		count += len(messages)
+
+		// Flushing each batch keeps downloads moving while the next one is read.
+		if err := buf.Flush(); err != nil {
+			return errors.Wrap(err, "failed to write export")
+		}
+
+		cursor := entities.CursorOf(messages[len(messages)-1])
+		after = &cursor
+		if len(messages) < batchSize {
+			break
+		}
+	}
+
+	if err := out.end(count); err != nil {
+		return errors.Wrap(err, "failed to write export")
+	}
+	if err := buf.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write export")
+	}
+
+	e.logger.Info("Exported room history",
+		zap.String("room_id", roomID.String()),
+		zap.String("format", string(format)),
+		zap.Int("messages", count),
+	)
+	return nil
+}
+
+// author returns the name a message is shown under: the name it was posted
+// with, such as the name of an incoming webhook, or else the username of its
+// author. Authors without an account, such as room scripts, are shown by ID.
+func (e *Exporter) author(ctx context.Context, msg *entities.Message, authors map[uuid.UUID]string) (string, error) {
+	if msg.AuthorName != "" {
+		return msg.AuthorName, nil
+	}
+	if author, ok := authors[msg.UserID]; ok {
+		return author, nil
+	}
+
+	author, found, err := e.users.GetUsername(ctx, msg.UserID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve author")
+	}
+	if !found || author == "" {
+		author = msg.UserID.String()
+	}
+
+	authors[msg.UserID] = author
+	return author, nil
+}
+
+func newTranscript(format entities.ExportFormat, w *bufio.Writer) transcript {
+	switch format {
+	case entities.ExportFormatJSONL:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return &jsonTranscript{enc: enc}
+	case entities.ExportFormatHTML:
+		return &htmlTranscript{w: w}
+	case entities.ExportFormatText:
+		return &textTranscript{w: w}
+	default:
+		return nil
+	}
+}
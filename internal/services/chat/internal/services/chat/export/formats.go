@@ -0,0 +1,227 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// textTimeFormat is used for the timestamps of text and HTML transcripts,
+// which are always in UTC.
+const textTimeFormat = "2006-01-02 15:04:05"
+
+// jsonTranscript writes one JSON object per message, without header or
+// footer, so that exports can be concatenated and processed line by line.
+type jsonTranscript struct {
+	enc  *json.Encoder
+	room uuid.UUID
+}
+
+type jsonMessage struct {
+	ID          uuid.UUID        `json:"id"`
+	RoomID      uuid.UUID        `json:"room_id"`
+	ParentID    *uuid.UUID       `json:"parent_id,omitempty"`
+	UserID      uuid.UUID        `json:"user_id"`
+	Author      string           `json:"author"`
+	Kind        string           `json:"kind,omitempty"`
+	Content     string           `json:"content"`
+	Timestamp   time.Time        `json:"timestamp"`
+	EditedAt    *time.Time       `json:"edited_at,omitempty"`
+	Attachments []jsonAttachment `json:"attachments,omitempty"`
+}
+
+type jsonAttachment struct {
+	ID          uuid.UUID `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+}
+
+func (t *jsonTranscript) begin(room *roomInfo) error {
+	t.room = room.ID
+	return nil
+}
+
+func (t *jsonTranscript) write(e *entry) error {
+	msg := e.Message
+	record := jsonMessage{
+		ID:        msg.ID,
+		RoomID:    t.room,
+		ParentID:  msg.ParentID,
+		UserID:    msg.UserID,
+		Author:    e.Author,
+		Kind:      msg.Kind,
+		Content:   msg.Content,
+		Timestamp: msg.Timestamp.UTC(),
+	}
+	if msg.EditedAt != nil {
+		editedAt := msg.EditedAt.UTC()
+		record.EditedAt = &editedAt
+	}
+	for _, attachment := range e.Attachments {
+		record.Attachments = append(record.Attachments, jsonAttachment{
+			ID:          attachment.ID,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+
+	return t.enc.Encode(record)
+}
+
+func (t *jsonTranscript) end(int) error {
+	return nil
+}
+
+// textTranscript writes one line per message, prefixed with its time and the
+// start of its ID. Replies name the message they reply to, continuation lines
+// of multi-line messages are indented and attachments are listed below their
+// message.
+type textTranscript struct {
+	w *bufio.Writer
+}
+
+func (t *textTranscript) begin(room *roomInfo) error {
+	_, err := fmt.Fprintf(t.w, "# Room: %s (%s)\n# Exported: %s UTC\n\n",
+		room.Name, room.ID, room.ExportedAt.Format(textTimeFormat))
+	return err
+}
+
+func (t *textTranscript) write(e *entry) error {
+	msg := e.Message
+
+	var line strings.Builder
+	fmt.Fprintf(&line, "[%s] %s ", msg.Timestamp.UTC().Format(textTimeFormat), shortID(msg.ID))
+	if msg.ParentID != nil {
+		fmt.Fprintf(&line, "↳%s ", shortID(*msg.ParentID))
+	}
+	if msg.Kind == entities.MessageKindEmote {
+		fmt.Fprintf(&line, "* %s %s", e.Author, msg.Content)
+	} else {
+		fmt.Fprintf(&line, "%s: %s", e.Author, msg.Content)
+	}
+	if msg.EditedAt != nil {
+		line.WriteString(" (edited)")
+	}
+
+	text := strings.ReplaceAll(line.String(), "\n", "\n    ")
+	if _, err := t.w.WriteString(text + "\n"); err != nil {
+		return err
+	}
+
+	for _, attachment := range e.Attachments {
+		if _, err := fmt.Fprintf(t.w, "    [attachment] %s (%s, %s)\n",
+			attachment.FileName, attachment.ContentType, formatSize(attachment.Size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *textTranscript) end(count int) error {
+	_, err := fmt.Fprintf(t.w, "\n# %d messages\n", count)
+	return err
+}
+
+// htmlTranscript writes a single HTML page with its styles inlined and no
+// scripts, readable offline and safe to open: every value is escaped. Replies
+// link to the message they reply to.
+type htmlTranscript struct {
+	w *bufio.Writer
+}
+
+var htmlTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"time":     func(t time.Time) string { return t.UTC().Format(textTimeFormat) },
+	"size":     formatSize,
+	"emote":    func(kind string) bool { return kind == entities.MessageKindEmote },
+	"external": func(kind string) bool { return kind == entities.MessageKindIntegration },
+}).Parse(`
+{{- define "begin" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} – chat export</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; max-width: 860px; margin: 2em auto; padding: 0 1em; color: #1d1d1f; }
+header { border-bottom: 1px solid #ddd; margin-bottom: 1em; }
+header p { color: #666; font-size: .9em; }
+.message { padding: .4em 0; }
+.reply { margin-left: 2em; padding-left: .8em; border-left: 3px solid #ddd; }
+.meta { color: #666; font-size: .85em; }
+.meta a { color: inherit; }
+.author { font-weight: 600; }
+.badge { font-size: .75em; background: #eee; border-radius: 3px; padding: 0 .3em; }
+.content { white-space: pre-wrap; word-wrap: break-word; margin-top: .15em; }
+.emote .content { font-style: italic; }
+.attachment { font-size: .9em; color: #444; }
+footer { border-top: 1px solid #ddd; margin-top: 1em; color: #666; font-size: .9em; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Name}}</h1>
+<p>Room {{.ID}} · exported {{time .ExportedAt}} UTC · times in UTC</p>
+</header>
+<main>
+{{end -}}
+
+{{- define "message" -}}
+{{- $msg := .Message -}}
+<div class="message{{if $msg.ParentID}} reply{{end}}{{if emote $msg.Kind}} emote{{end}}" id="m-{{$msg.ID}}">
+<div class="meta"><a href="#m-{{$msg.ID}}">{{time $msg.Timestamp}}</a> <span class="author">{{.Author}}</span>
+{{- if external $msg.Kind}} <span class="badge">integration</span>{{end}}
+{{- if $msg.ParentID}} · <a href="#m-{{$msg.ParentID}}">in reply</a>{{end}}
+{{- if $msg.EditedAt}} · edited{{end}}</div>
+{{- if $msg.Content}}
+<div class="content">{{if emote $msg.Kind}}* {{.Author}} {{end}}{{$msg.Content}}</div>
+{{- end}}
+{{- range .Attachments}}
+<div class="attachment">📎 {{.FileName}} ({{.ContentType}}, {{size .Size}})</div>
+{{- end}}
+</div>
+{{end -}}
+
+{{- define "end" -}}
+</main>
+<footer><p>{{.}} messages</p></footer>
+</body>
+</html>
+{{end -}}
+`))
+
+func (t *htmlTranscript) begin(room *roomInfo) error {
+	return htmlTemplates.ExecuteTemplate(t.w, "begin", room)
+}
+
+func (t *htmlTranscript) write(e *entry) error {
+	return htmlTemplates.ExecuteTemplate(t.w, "message", e)
+}
+
+func (t *htmlTranscript) end(count int) error {
+	return htmlTemplates.ExecuteTemplate(t.w, "end", count)
+}
+
+// shortID returns the first group of a UUID, enough to tell the messages of a
+// transcript apart.
+func shortID(id uuid.UUID) string {
+	return id.String()[:8]
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,224 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// The methods below back the built-in slash commands. They do not check the
+// permissions of the acting user: the command registry does that before calling
+// them.
+
+// SendEmote posts a /me message: an action performed by the user, shown as such
+// by clients. It is checked and stored like any other message.
+func (s *Service) SendEmote(ctx context.Context, roomID, userID uuid.UUID, content, clientMsgID string, parentID *uuid.UUID) (*entities.Message, error) {
+	return s.sendMessage(ctx, &entities.Message{
+		ID:          uuid.New(),
+		RoomID:      roomID,
+		UserID:      userID,
+		ClientMsgID: clientMsgID,
+		ParentID:    parentID,
+		Kind:        entities.MessageKindEmote,
+		Content:     content,
+		Timestamp:   time.Now(),
+	})
+}
+
+// SetTopic changes the topic of a room and notifies the room; an empty topic clears it.
+func (s *Service) SetTopic(ctx context.Context, roomID, userID uuid.UUID, topic string) error {
+	if utf8.RuneCountInString(topic) > entities.MaxTopicLength {
+		return errors.Wrapf(entities.ErrInvalidMessage, "topic exceeds %d characters", entities.MaxTopicLength)
+	}
+
+	if err := s.storage.SetTopic(ctx, roomID, topic); err != nil {
+		return errors.Wrap(err, "failed to save topic")
+	}
+
+	payload, err := json.Marshal(entities.TopicPayload{Topic: topic})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal topic")
+	}
+
+	s.broadcastPersisted(ctx, &entities.Event{
+		Type:      entities.EventTopicUpdated,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
+
+// KickUser removes a participant from a room and closes their connections to it
+// on every instance. Kicked users may join again.
+func (s *Service) KickUser(ctx context.Context, roomID, userID, targetID uuid.UUID, reason string) error {
+	if err := s.checkModerationTarget(ctx, roomID, userID, targetID); err != nil {
+		return err
+	}
+
+	isParticipant, err := s.storage.IsParticipant(ctx, roomID, targetID)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify participant")
+	}
+	if !isParticipant {
+		return entities.ErrUserNotFound
+	}
+
+	if err := s.storage.RemoveParticipant(ctx, roomID, targetID); err != nil {
+		return errors.Wrap(err, "failed to remove participant")
+	}
+
+	return s.broadcastModeration(ctx, entities.EventUserKicked, roomID, userID, entities.ModerationPayload{
+		UserID: targetID,
+		Reason: reason,
+	})
+}
+
+// MuteUser stops a user from posting to a room for the given duration and
+// returns when the mute ends.
+func (s *Service) MuteUser(ctx context.Context, roomID, userID, targetID uuid.UUID, duration time.Duration, reason string) (time.Time, error) {
+	if duration <= 0 || duration > entities.MaxMuteDuration {
+		return time.Time{}, errors.Wrapf(entities.ErrInvalidMessage, "mute duration must be between 1s and %s", entities.MaxMuteDuration)
+	}
+
+	if err := s.checkModerationTarget(ctx, roomID, userID, targetID); err != nil {
+		return time.Time{}, err
+	}
+
+	until := time.Now().Add(duration)
+	if err := s.storage.MuteUser(ctx, roomID, targetID, userID, until); err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to mute user")
+	}
+
+	err := s.broadcastModeration(ctx, entities.EventUserMuted, roomID, userID, entities.ModerationPayload{
+		UserID: targetID,
+		Reason: reason,
+		Until:  &until,
+	})
+	return until, err
+}
+
+// UnmuteUser lifts the mute of a user in a room.
+func (s *Service) UnmuteUser(ctx context.Context, roomID, userID, targetID uuid.UUID) error {
+	unmuted, err := s.storage.UnmuteUser(ctx, roomID, targetID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to unmute user")
+	}
+	if !unmuted {
+		return entities.ErrUserNotFound
+	}
+
+	return s.broadcastModeration(ctx, entities.EventUserUnmuted, roomID, userID, entities.ModerationPayload{
+		UserID: targetID,
+	})
+}
+
+// checkModerationTarget refuses moderating oneself or the owner of the room.
+func (s *Service) checkModerationTarget(ctx context.Context, roomID, userID, targetID uuid.UUID) error {
+	if targetID == userID {
+		return errors.Wrap(entities.ErrForbidden, "cannot moderate yourself")
+	}
+
+	ownerID, err := s.website.GetRoomOwner(ctx, roomID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get room owner")
+	}
+	if targetID == ownerID {
+		return errors.Wrap(entities.ErrForbidden, "cannot moderate the room owner")
+	}
+
+	return nil
+}
+
+func (s *Service) broadcastModeration(ctx context.Context, eventType entities.EventType, roomID, userID uuid.UUID, payload entities.ModerationPayload) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal moderation event")
+	}
+
+	s.broadcastPersisted(ctx, &entities.Event{
+		Type:      eventType,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payloadJSON,
+		Timestamp: time.Now(),
+	})
+
+	s.logger.Info("User moderated",
+		zap.String("action", string(eventType)),
+		zap.String("room_id", roomID.String()),
+		zap.String("moderator_id", userID.String()),
+		zap.String("user_id", payload.UserID.String()),
+	)
+
+	return nil
+}
+
+// checkMute refuses messages of a user muted in the room.
+func (s *Service) checkMute(ctx context.Context, roomID, userID uuid.UUID) error {
+	until, err := s.storage.GetMute(ctx, roomID, userID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "failed to check mute")
+	}
+	if until != nil {
+		return &entities.MutedError{Until: *until}
+	}
+	return nil
+}
+
+// closeKickedConnections closes the local connections of a kicked user once
+// the kick event has been delivered.
+func (s *Service) closeKickedConnections(room *entities.Room, event *entities.Event) {
+	var payload entities.ModerationPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		s.logger.Error("Failed to unmarshal kick", zap.Error(err))
+		return
+	}
+
+	room.CloseUserConnections(payload.UserID)
+}
+
+// sendTopic tells a new connection the topic of its room, if there is one.
+func (s *Service) sendTopic(ctx context.Context, roomID, userID uuid.UUID, conn entities.Connection) {
+	topic, err := s.storage.GetTopic(ctx, roomID)
+	if err != nil {
+		s.logger.Error("Failed to get topic",
+			zap.Error(err),
+			zap.String("room_id", roomID.String()),
+		)
+		return
+	}
+	if topic == "" {
+		return
+	}
+
+	payload, err := json.Marshal(entities.TopicPayload{Topic: topic})
+	if err != nil {
+		s.logger.Error("Failed to marshal topic", zap.Error(err))
+		return
+	}
+
+	eventJSON, err := json.Marshal(&entities.Event{
+		Type:      entities.EventTopicUpdated,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal topic event", zap.Error(err))
+		return
+	}
+
+	if err := conn.Send(eventJSON); err != nil {
+		s.logger.Debug("Failed to send topic", zap.Error(err))
+	}
+}
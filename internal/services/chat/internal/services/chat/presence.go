@@ -0,0 +1,333 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// presenceAwayAfter is how long a connection stays "online" without a client heartbeat.
+	presenceAwayAfter = 5 * time.Minute
+	// presenceRefreshInterval is how often the presence sessions of this instance are refreshed in storage.
+	presenceRefreshInterval = 30 * time.Second
+	// presenceTTL is how long a presence session survives without a refresh, e.g. after a crash.
+	presenceTTL = 3 * presenceRefreshInterval
+	// maxPresenceSubscriptions bounds the number of users a single connection may watch.
+	maxPresenceSubscriptions = 1000
+)
+
+// presenceDevice is the presence of a single connection of a user.
+type presenceDevice struct {
+	lastActive time.Time
+	away       bool
+}
+
+// presenceState is the presence of a user on this instance. status is the last
+// status written to storage; the global status aggregates every instance.
+type presenceState struct {
+	devices map[uuid.UUID]*presenceDevice
+	status  entities.PresenceStatus
+}
+
+// currentStatus derives the status of the user from its devices: online while
+// any device is active, away while all devices are idle, offline without devices.
+func (p *presenceState) currentStatus(now time.Time) entities.PresenceStatus {
+	if len(p.devices) == 0 {
+		return entities.PresenceOffline
+	}
+
+	for _, device := range p.devices {
+		if !device.away && now.Sub(device.lastActive) < presenceAwayAfter {
+			return entities.PresenceOnline
+		}
+	}
+
+	return entities.PresenceAway
+}
+
+// SetPresence records a client heartbeat of one connection of the user. A
+// heartbeat with PresenceAway marks the device as idle until the next online heartbeat.
+func (s *Service) SetPresence(ctx context.Context, userID, connectionID uuid.UUID, status entities.PresenceStatus) error {
+	if status != entities.PresenceOnline && status != entities.PresenceAway {
+		return errors.Wrap(entities.ErrInvalidPresence, "status must be online or away")
+	}
+
+	s.presenceMu.Lock()
+	state, exists := s.presence[userID]
+	if !exists || state.devices[connectionID] == nil {
+		s.presenceMu.Unlock()
+		return entities.ErrForbidden
+	}
+
+	device := state.devices[connectionID]
+	device.lastActive = time.Now()
+	device.away = status == entities.PresenceAway
+	s.presenceMu.Unlock()
+
+	s.updatePresence(ctx, userID)
+	return nil
+}
+
+// GetPresence returns the global presence of the given users.
+func (s *Service) GetPresence(ctx context.Context, userIDs []uuid.UUID) ([]entities.Presence, error) {
+	presences, err := s.storage.GetPresence(ctx, userIDs, time.Now().Add(-presenceTTL))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get presence")
+	}
+
+	return presences, nil
+}
+
+// SubscribePresence delivers presence events of the given users to the connection
+// until it unsubscribes or disconnects, and returns their current presence.
+func (s *Service) SubscribePresence(ctx context.Context, conn entities.Connection, userIDs []uuid.UUID) ([]entities.Presence, error) {
+	s.presenceMu.Lock()
+	watched, exists := s.presenceWatches[conn.ID()]
+	if !exists {
+		watched = make(map[uuid.UUID]struct{})
+	}
+
+	added := 0
+	for _, userID := range userIDs {
+		if _, ok := watched[userID]; !ok {
+			added++
+		}
+	}
+	if len(watched)+added > maxPresenceSubscriptions {
+		s.presenceMu.Unlock()
+		return nil, errors.Wrapf(entities.ErrInvalidPresence, "at most %d users can be watched", maxPresenceSubscriptions)
+	}
+
+	s.presenceWatches[conn.ID()] = watched
+	for _, userID := range userIDs {
+		watched[userID] = struct{}{}
+
+		subscribers, ok := s.presenceSubs[userID]
+		if !ok {
+			subscribers = make(map[uuid.UUID]entities.Connection)
+			s.presenceSubs[userID] = subscribers
+		}
+		subscribers[conn.ID()] = conn
+	}
+	s.presenceMu.Unlock()
+
+	return s.GetPresence(ctx, userIDs)
+}
+
+// UnsubscribePresence stops delivering presence events of the given users to the connection.
+func (s *Service) UnsubscribePresence(conn entities.Connection, userIDs []uuid.UUID) {
+	s.presenceMu.Lock()
+	defer s.presenceMu.Unlock()
+
+	for _, userID := range userIDs {
+		s.unwatchPresence(conn.ID(), userID)
+	}
+}
+
+// trackPresence registers a new connection of the user as an active device.
+func (s *Service) trackPresence(ctx context.Context, userID, connectionID uuid.UUID) {
+	s.presenceMu.Lock()
+	state, exists := s.presence[userID]
+	if !exists {
+		state = &presenceState{
+			devices: make(map[uuid.UUID]*presenceDevice),
+			status:  entities.PresenceOffline,
+		}
+		s.presence[userID] = state
+	}
+	state.devices[connectionID] = &presenceDevice{lastActive: time.Now()}
+	s.presenceMu.Unlock()
+
+	s.updatePresence(ctx, userID)
+}
+
+// untrackPresence forgets a closed connection together with its presence subscriptions.
+func (s *Service) untrackPresence(ctx context.Context, userID, connectionID uuid.UUID) {
+	s.presenceMu.Lock()
+	if state, exists := s.presence[userID]; exists {
+		delete(state.devices, connectionID)
+	}
+	for watchedID := range s.presenceWatches[connectionID] {
+		s.unwatchPresence(connectionID, watchedID)
+	}
+	s.presenceMu.Unlock()
+
+	s.updatePresence(ctx, userID)
+}
+
+// unwatchPresence removes a single subscription. The caller must hold presenceMu.
+func (s *Service) unwatchPresence(connectionID, userID uuid.UUID) {
+	if watched, exists := s.presenceWatches[connectionID]; exists {
+		delete(watched, userID)
+		if len(watched) == 0 {
+			delete(s.presenceWatches, connectionID)
+		}
+	}
+
+	if subscribers, exists := s.presenceSubs[userID]; exists {
+		delete(subscribers, connectionID)
+		if len(subscribers) == 0 {
+			delete(s.presenceSubs, userID)
+		}
+	}
+}
+
+// updatePresence recomputes the status of the user on this instance and, when it
+// changed, stores it and notifies the subscribers on every instance. Updates are
+// serialized so that storage always ends up with the latest local status.
+func (s *Service) updatePresence(ctx context.Context, userID uuid.UUID) {
+	s.presenceWriteMu.Lock()
+	defer s.presenceWriteMu.Unlock()
+
+	now := time.Now()
+
+	s.presenceMu.Lock()
+	state, exists := s.presence[userID]
+	if !exists {
+		s.presenceMu.Unlock()
+		return
+	}
+
+	status := state.currentStatus(now)
+	changed := status != state.status
+	state.status = status
+	if len(state.devices) == 0 {
+		delete(s.presence, userID)
+	}
+	s.presenceMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if err := s.storage.SavePresence(ctx, userID, s.instanceID, status, now); err != nil {
+		s.logger.Error("Failed to save presence",
+			zap.Error(err),
+			zap.String("user_id", userID.String()),
+			zap.String("status", string(status)),
+		)
+
+		// Let the next refresh retry the write.
+		s.presenceMu.Lock()
+		if state, exists := s.presence[userID]; exists {
+			state.status = ""
+		}
+		s.presenceMu.Unlock()
+		return
+	}
+
+	s.publishPresence(ctx, []uuid.UUID{userID})
+}
+
+// publishPresence broadcasts the global presence of the users to their subscribers.
+func (s *Service) publishPresence(ctx context.Context, userIDs []uuid.UUID) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	presences, err := s.storage.GetPresence(ctx, userIDs, time.Now().Add(-presenceTTL))
+	if err != nil {
+		s.logger.Error("Failed to get presence", zap.Error(err))
+		return
+	}
+
+	for _, presence := range presences {
+		payload, err := json.Marshal(presence)
+		if err != nil {
+			s.logger.Error("Failed to marshal presence", zap.Error(err))
+			continue
+		}
+
+		s.broadcast(ctx, &entities.Event{
+			Type:      entities.EventPresence,
+			UserID:    presence.UserID,
+			Payload:   payload,
+			Timestamp: time.Now(),
+		}, nil)
+	}
+}
+
+// deliverPresence sends a presence event to the local connections watching its user.
+func (s *Service) deliverPresence(event *entities.Event) {
+	s.presenceMu.Lock()
+	subscribers := make([]entities.Connection, 0, len(s.presenceSubs[event.UserID]))
+	for _, conn := range s.presenceSubs[event.UserID] {
+		subscribers = append(subscribers, conn)
+	}
+	s.presenceMu.Unlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Error("Failed to marshal presence event", zap.Error(err))
+		return
+	}
+
+	for _, conn := range subscribers {
+		if err := conn.Send(eventJSON); err != nil && err != entities.ErrConnectionClosed {
+			s.logger.Debug("Failed to send presence event",
+				zap.Error(err),
+				zap.String("connection_id", conn.ID().String()),
+			)
+		}
+	}
+}
+
+func (s *Service) startPresenceTicker() {
+	s.presenceTick = time.NewTicker(presenceRefreshInterval)
+	go func() {
+		for range s.presenceTick.C {
+			s.refreshPresence()
+		}
+	}()
+}
+
+// refreshPresence turns idle users away, keeps the presence sessions of this
+// instance alive and expires the sessions of instances that stopped refreshing theirs.
+func (s *Service) refreshPresence() {
+	ctx := context.Background()
+
+	s.presenceMu.Lock()
+	userIDs := make([]uuid.UUID, 0, len(s.presence))
+	for userID := range s.presence {
+		userIDs = append(userIDs, userID)
+	}
+	s.presenceMu.Unlock()
+
+	for _, userID := range userIDs {
+		s.updatePresence(ctx, userID)
+	}
+
+	now := time.Now()
+	if err := s.storage.TouchPresence(ctx, s.instanceID, now); err != nil {
+		s.logger.Error("Failed to refresh presence sessions", zap.Error(err))
+	}
+
+	expired, err := s.storage.ExpirePresence(ctx, now.Add(-presenceTTL))
+	if err != nil {
+		s.logger.Error("Failed to expire presence sessions", zap.Error(err))
+		return
+	}
+
+	s.publishPresence(ctx, expired)
+}
+
+// stopPresence marks every user connected to this instance as gone.
+func (s *Service) stopPresence() {
+	if s.presenceTick != nil {
+		s.presenceTick.Stop()
+	}
+
+	if err := s.storage.ClearInstancePresence(context.Background(), s.instanceID, time.Now()); err != nil {
+		s.logger.Error("Failed to clear presence sessions", zap.Error(err))
+	}
+}
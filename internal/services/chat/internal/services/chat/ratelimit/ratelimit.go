@@ -0,0 +1,142 @@
+// Package ratelimit bounds how fast messages may be sent per user, room and client IP.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// sweepInterval is how often buckets that refilled completely are dropped.
+const sweepInterval = time.Minute
+
+// Rate is a token bucket refilled with PerSecond tokens per second up to Burst
+// tokens. A zero PerSecond disables the limit.
+type Rate struct {
+	PerSecond float64
+	Burst     int
+}
+
+// Config holds the limits applied to every message.
+type Config struct {
+	User Rate
+	Room Rate
+	IP   Rate
+}
+
+// Limiter keeps the token buckets of this instance. Limits are not shared between
+// instances, so a client spread over several instances gets each instance's budget.
+type Limiter struct {
+	scopes []*scope
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		scopes: []*scope{
+			newScope(entities.RateLimitUser, cfg.User),
+			newScope(entities.RateLimitRoom, cfg.Room),
+			newScope(entities.RateLimitIP, cfg.IP),
+		},
+	}
+}
+
+// Allow takes a token from the buckets of the user, the room and the client IP.
+// Tokens are only taken when every bucket has one; otherwise a RateLimitError
+// reports the most restrictive limit. An empty clientIP skips the IP limit.
+func (l *Limiter) Allow(roomID, userID uuid.UUID, clientIP string) error {
+	now := time.Now()
+	keys := []string{userID.String(), roomID.String(), clientIP}
+
+	var (
+		reservations []*rate.Reservation
+		rejected     *entities.RateLimitError
+	)
+	for i, s := range l.scopes {
+		if !s.enabled() || keys[i] == "" {
+			continue
+		}
+
+		reservation := s.reserve(keys[i], now)
+		reservations = append(reservations, reservation)
+
+		if delay := reservation.DelayFrom(now); delay > 0 && (rejected == nil || delay > rejected.RetryAfter) {
+			rejected = &entities.RateLimitError{Scope: s.name, RetryAfter: delay}
+		}
+	}
+
+	if rejected == nil {
+		return nil
+	}
+
+	for _, reservation := range reservations {
+		reservation.CancelAt(now)
+	}
+	return rejected
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// scope holds the buckets of one kind of key.
+type scope struct {
+	name    entities.RateLimitScope
+	limit   rate.Limit
+	burst   int
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func newScope(name entities.RateLimitScope, r Rate) *scope {
+	burst := r.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &scope{
+		name:    name,
+		limit:   rate.Limit(r.PerSecond),
+		burst:   burst,
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+func (s *scope) enabled() bool {
+	return s.limit > 0
+}
+
+func (s *scope) reserve(key string, now time.Time) *rate.Reservation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.swept) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &bucket{limiter: rate.NewLimiter(s.limit, s.burst)}
+		s.buckets[key] = b
+	}
+	b.lastUsed = now
+
+	return b.limiter.ReserveN(now, 1)
+}
+
+// sweep drops the buckets that are full again; a new bucket starts full too.
+// The caller must hold mu.
+func (s *scope) sweep(now time.Time) {
+	refill := time.Duration(float64(s.burst) / float64(s.limit) * float64(time.Second))
+	for key, b := range s.buckets {
+		if now.Sub(b.lastUsed) >= refill {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
@@ -0,0 +1,251 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxHeldEvents bounds the live events held back while a replay is sent. A
+	// room busy enough to exceed it would overflow the connection queue anyway.
+	maxHeldEvents = 1000
+	// skipSeqAttempts bounds the attempts to leave a gap for an unlogged event.
+	skipSeqAttempts = 5
+)
+
+// resumingConnection holds back the live events of a resuming client until its
+// replay is sent, so that no event is delivered out of order.
+type resumingConnection struct {
+	entities.Connection
+
+	mu        sync.Mutex
+	replaying bool
+	held      [][]byte
+}
+
+func newResumingConnection(conn entities.Connection) *resumingConnection {
+	return &resumingConnection{Connection: conn, replaying: true}
+}
+
+func (c *resumingConnection) Send(message []byte) error {
+	c.mu.Lock()
+	if c.replaying {
+		if len(c.held) >= maxHeldEvents {
+			c.mu.Unlock()
+			c.Connection.Close()
+			return entities.ErrConnectionClosed
+		}
+		c.held = append(c.held, message)
+		c.mu.Unlock()
+		return nil
+	}
+	c.mu.Unlock()
+
+	return c.Connection.Send(message)
+}
+
+// finishReplay delivers the held events and passes later events straight
+// through. Events up to lastSeq were part of the replay and are dropped.
+func (c *resumingConnection) finishReplay(lastSeq int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, message := range c.held {
+		var event struct {
+			Seq int64 `json:"seq"`
+		}
+		if err := json.Unmarshal(message, &event); err == nil && event.Seq != 0 && event.Seq <= lastSeq {
+			continue
+		}
+
+		if err := c.Connection.Send(message); err != nil {
+			break
+		}
+	}
+
+	c.held = nil
+	c.replaying = false
+}
+
+// replay sends the events of the room after sinceSeq to the connection and
+// returns the sequence the replay ends at. When the events are unavailable the
+// client is told to reset and reload the history instead.
+func (s *Service) replay(ctx context.Context, roomID uuid.UUID, sinceSeq int64, conn entities.Connection) int64 {
+	lastSeq, err := s.storage.GetRoomSeq(ctx, roomID)
+	if err != nil {
+		s.logger.Error("Failed to get room sequence",
+			zap.Error(err),
+			zap.String("room_id", roomID.String()),
+		)
+		s.sendReplay(roomID, conn, &entities.Replay{Events: []*entities.Event{}, Reset: true})
+		return 0
+	}
+
+	if sinceSeq < 0 || sinceSeq > lastSeq || lastSeq-sinceSeq > entities.MaxReplayEvents {
+		s.sendReplay(roomID, conn, &entities.Replay{Events: []*entities.Event{}, LastSeq: lastSeq, Reset: true})
+		return lastSeq
+	}
+
+	cursor := sinceSeq
+	for {
+		events, err := s.storage.GetRoomEvents(ctx, roomID, cursor, lastSeq, entities.ReplayBatchSize)
+		if err != nil {
+			s.logger.Error("Failed to get room events",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.Int64("after_seq", cursor),
+			)
+			s.sendReplay(roomID, conn, &entities.Replay{Events: []*entities.Event{}, LastSeq: lastSeq, Reset: true})
+			return lastSeq
+		}
+
+		// Events purged from the log, or that could not be logged, leave a gap
+		// that cannot be replayed.
+		complete := len(events) > 0 && events[0].Seq == cursor+1 &&
+			events[len(events)-1].Seq-cursor == int64(len(events))
+		if !complete && cursor < lastSeq {
+			s.sendReplay(roomID, conn, &entities.Replay{Events: []*entities.Event{}, LastSeq: lastSeq, Reset: true})
+			return lastSeq
+		}
+
+		if len(events) > 0 {
+			cursor = events[len(events)-1].Seq
+		}
+
+		events, err = s.loadEventMessages(ctx, events)
+		if err != nil {
+			s.logger.Error("Failed to load messages of room events",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+			)
+			s.sendReplay(roomID, conn, &entities.Replay{Events: []*entities.Event{}, LastSeq: lastSeq, Reset: true})
+			return lastSeq
+		}
+
+		hasMore := cursor < lastSeq
+		if events == nil {
+			events = []*entities.Event{}
+		}
+		if !s.sendReplay(roomID, conn, &entities.Replay{Events: events, LastSeq: lastSeq, HasMore: hasMore}) || !hasMore {
+			return lastSeq
+		}
+	}
+}
+
+// loadEventMessages fills the message events read from the log, which only
+// reference their message, with the message as it is now: edited, deleted or
+// with freshly signed attachment URLs. Events of messages that no longer exist
+// are left out.
+func (s *Service) loadEventMessages(ctx context.Context, events []*entities.Event) ([]*entities.Event, error) {
+	var ids []uuid.UUID
+	for _, event := range events {
+		if !event.Type.CarriesMessage() {
+			continue
+		}
+		var ref entities.MessageRef
+		if err := json.Unmarshal(event.Payload, &ref); err != nil {
+			return nil, errors.Wrap(err, "failed to read message reference")
+		}
+		ids = append(ids, ref.ID)
+	}
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	messages, err := s.storage.GetMessages(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enrichMessages(ctx, messages); err != nil {
+		return nil, err
+	}
+
+	payloads := make(map[uuid.UUID][]byte, len(messages))
+	for _, msg := range messages {
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal message")
+		}
+		payloads[msg.ID] = payload
+	}
+
+	loaded := make([]*entities.Event, 0, len(events))
+	for _, event := range events {
+		if event.Type.CarriesMessage() {
+			var ref entities.MessageRef
+			_ = json.Unmarshal(event.Payload, &ref)
+			payload, ok := payloads[ref.ID]
+			if !ok {
+				continue
+			}
+			event.Payload = payload
+		}
+		loaded = append(loaded, event)
+	}
+
+	return loaded, nil
+}
+
+// skipRoomSeq leaves a gap in the sequence of a room for an event that could
+// not be logged, retrying while storage is unavailable.
+func (s *Service) skipRoomSeq(roomID uuid.UUID) {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := s.storage.SkipRoomSeq(ctx, roomID)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt == skipSeqAttempts {
+			s.logger.Error("Failed to mark unlogged room event",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+			)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// sendReplay sends a single replay event and reports whether it was queued.
+func (s *Service) sendReplay(roomID uuid.UUID, conn entities.Connection, replay *entities.Replay) bool {
+	payload, err := json.Marshal(replay)
+	if err != nil {
+		s.logger.Error("Failed to marshal replay", zap.Error(err))
+		return false
+	}
+
+	eventJSON, err := json.Marshal(&entities.Event{
+		Type:      entities.EventReplay,
+		RoomID:    roomID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal replay event", zap.Error(err))
+		return false
+	}
+
+	if err := conn.Send(eventJSON); err != nil {
+		if err != entities.ErrConnectionClosed {
+			s.logger.Debug("Failed to send replay",
+				zap.Error(err),
+				zap.String("room_id", roomID.String()),
+				zap.String("connection_id", conn.ID().String()),
+			)
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,39 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Policies lists the retention policies set on the website service.
+type Policies interface {
+	GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
+}
+
+// Storage removes expired messages and events.
+type Storage interface {
+	GetRetentionCursor(ctx context.Context, roomID uuid.UUID, keep int) (*entities.MessageCursor, error)
+	PurgeMessages(ctx context.Context, roomID uuid.UUID, before entities.MessageCursor, limit int, archive bool) (*entities.PurgeResult, error)
+	PurgeRoomEvents(ctx context.Context, roomID uuid.UUID, before time.Time, limit int) (int, error)
+}
+
+// AttachmentContents deletes the stored files of purged attachments.
+type AttachmentContents interface {
+	DeleteContents(ctx context.Context, attachments []entities.Attachment) error
+}
+
+// Metrics records what the purge job does.
+type Metrics interface {
+	RecordRetentionPurge(kind string, count int)
+	RecordRetentionRun(result string, duration float64)
+}
+
+type Deps struct {
+	Policies    Policies
+	Storage     Storage
+	Attachments AttachmentContents
+	Metrics     Metrics
+}
@@ -0,0 +1,244 @@
+// Package retention purges the messages that the retention policies of their
+// rooms no longer allow to keep. Every instance runs the purge job; rows being
+// purged by one instance are skipped by the others.
+package retention
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	resultSuccess = "success"
+	resultPartial = "partial"
+	resultError   = "error"
+
+	kindMessages    = "messages"
+	kindEvents      = "events"
+	kindAttachments = "attachments"
+)
+
+// Config paces the purge job. Expired messages are removed within Interval
+// after they expire, BatchSize rows per transaction with BatchPause between
+// transactions, so that purging a large backlog does not hold long locks. With
+// Archive, purged messages are copied to the archive table instead of being
+// dropped, and keep their attachments.
+type Config struct {
+	Interval   time.Duration
+	BatchSize  int
+	BatchPause time.Duration
+	Archive    bool
+}
+
+type Purger struct {
+	policies    Policies
+	storage     Storage
+	attachments AttachmentContents
+	metrics     Metrics
+	cfg         Config
+	logger      *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(deps Deps, cfg Config, logger *zap.Logger) *Purger {
+	return &Purger{
+		policies:    deps.Policies,
+		storage:     deps.Storage,
+		attachments: deps.Attachments,
+		metrics:     deps.Metrics,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Start runs the purge job right away and then every interval until Stop.
+func (p *Purger) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			p.Run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop interrupts the current run, if any, and waits for the job to end.
+func (p *Purger) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+}
+
+// Run purges the expired messages of every room with a retention policy. A
+// room that fails is retried on the next run; the other rooms are still purged.
+func (p *Purger) Run(ctx context.Context) {
+	start := time.Now()
+
+	policies, err := p.policies.GetRetentionPolicies(ctx)
+	if err != nil {
+		p.logger.Error("Failed to get retention policies", zap.Error(err))
+		p.metrics.RecordRetentionRun(resultError, time.Since(start).Seconds())
+		return
+	}
+
+	result := resultSuccess
+	for _, policy := range policies {
+		if ctx.Err() != nil {
+			result = resultPartial
+			break
+		}
+
+		if err := p.purgeRoom(ctx, policy, start); err != nil {
+			result = resultPartial
+			if ctx.Err() != nil {
+				break
+			}
+			p.logger.Error("Failed to purge room",
+				zap.Error(err),
+				zap.String("room_id", policy.RoomID.String()),
+			)
+		}
+	}
+
+	p.metrics.RecordRetentionRun(result, time.Since(start).Seconds())
+}
+
+// purgeRoom removes the messages of a room before its retention cursor, then
+// the logged events from before that cursor.
+func (p *Purger) purgeRoom(ctx context.Context, policy *entities.RetentionPolicy, now time.Time) error {
+	before, err := p.retentionCursor(ctx, policy, now)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return nil
+	}
+
+	var messages, events int
+	for {
+		purged, err := p.storage.PurgeMessages(ctx, policy.RoomID, *before, p.cfg.BatchSize, p.cfg.Archive)
+		if err != nil {
+			return err
+		}
+
+		messages += purged.Messages
+		p.metrics.RecordRetentionPurge(kindMessages, purged.Messages)
+
+		if len(purged.Attachments) > 0 {
+			// The attachment rows are gone already, so contents failing to be
+			// deleted are only logged.
+			if err := p.attachments.DeleteContents(ctx, purged.Attachments); err != nil {
+				p.logger.Warn("Failed to delete purged attachments",
+					zap.Error(err),
+					zap.String("room_id", policy.RoomID.String()),
+				)
+			}
+			p.metrics.RecordRetentionPurge(kindAttachments, len(purged.Attachments))
+		}
+
+		if purged.Messages < p.cfg.BatchSize {
+			break
+		}
+		if err := p.pause(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		purged, err := p.storage.PurgeRoomEvents(ctx, policy.RoomID, before.Timestamp, p.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		events += purged
+		p.metrics.RecordRetentionPurge(kindEvents, purged)
+
+		if purged < p.cfg.BatchSize {
+			break
+		}
+		if err := p.pause(ctx); err != nil {
+			return err
+		}
+	}
+
+	if messages > 0 || events > 0 {
+		p.logger.Info("Purged expired messages",
+			zap.String("room_id", policy.RoomID.String()),
+			zap.Int("messages", messages),
+			zap.Int("events", events),
+			zap.Bool("archived", p.cfg.Archive),
+		)
+	}
+	return nil
+}
+
+// retentionCursor returns the position before which the policy expires the
+// messages of its room, or nil when it expires none. When both limits apply,
+// the stricter one wins.
+func (p *Purger) retentionCursor(ctx context.Context, policy *entities.RetentionPolicy, now time.Time) (*entities.MessageCursor, error) {
+	var before *entities.MessageCursor
+
+	// The zero ID sorts first, so the cursor excludes every message of its instant.
+	if cutoff, ok := policy.Cutoff(now); ok {
+		before = &entities.MessageCursor{Timestamp: cutoff}
+	}
+
+	if policy.Messages > 0 {
+		cursor, err := p.storage.GetRetentionCursor(ctx, policy.RoomID, policy.Messages)
+		if err != nil {
+			return nil, err
+		}
+		if cursor != nil && (before == nil || isAfter(*cursor, *before)) {
+			before = cursor
+		}
+	}
+
+	return before, nil
+}
+
+func (p *Purger) pause(ctx context.Context) error {
+	if p.cfg.BatchPause <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(p.cfg.BatchPause)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "purge interrupted")
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isAfter reports whether cursor a comes after b in the history of a room.
+func isAfter(a, b entities.MessageCursor) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.After(b.Timestamp)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) > 0
+}
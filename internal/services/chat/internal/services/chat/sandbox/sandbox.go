@@ -0,0 +1,226 @@
+// Package sandbox runs room script hooks in separate worker processes. Each
+// worker runs one hook at a time under a hard memory limit; a worker that runs
+// out of memory or time is killed and replaced, so a script cannot take the
+// chat service down with it.
+package sandbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/pkg/roomscript"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrTimeout is returned when a hook does not finish in time.
+	ErrTimeout = errors.New("script timed out")
+	// ErrWorkerFailed is returned when the worker running a hook died, most
+	// likely because the script exceeded the memory limit.
+	ErrWorkerFailed = errors.New("script worker failed")
+	// ErrClosed is returned once the runner is closed.
+	ErrClosed = errors.New("sandbox closed")
+)
+
+// Config holds the limits every hook runs under.
+type Config struct {
+	WorkerPath  string        // Path of the script-worker binary.
+	Workers     int           // Number of hooks run at the same time.
+	MemoryLimit int64         // Bytes of memory a worker may use.
+	MaxSteps    uint64        // Starlark execution steps a hook may take.
+	Timeout     time.Duration // Wall-clock time a hook may take.
+}
+
+// Runner hands hooks to a pool of worker processes. Workers are started on
+// first use and restarted after they fail.
+type Runner struct {
+	cfg    Config
+	logger *zap.Logger
+	idle   chan *worker // Nil entries are free slots without a running worker.
+	done   chan struct{}
+	once   sync.Once
+}
+
+func New(cfg Config, logger *zap.Logger) *Runner {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+
+	r := &Runner{
+		cfg:    cfg,
+		logger: logger,
+		idle:   make(chan *worker, cfg.Workers),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		r.idle <- nil
+	}
+	return r
+}
+
+// Run runs one hook and returns its result. Errors raised by the script itself
+// are reported in the result; Run only fails when the hook could not complete.
+func (r *Runner) Run(ctx context.Context, req *roomscript.Request) (*roomscript.Result, error) {
+	var w *worker
+	select {
+	case w = <-r.idle:
+	case <-r.done:
+		return nil, ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	result, err := r.run(ctx, &w, req)
+	r.release(w)
+	return result, err
+}
+
+// release returns a worker to the pool, or stops it once the runner is closed.
+func (r *Runner) release(w *worker) {
+	select {
+	case <-r.done:
+		if w != nil {
+			w.kill()
+		}
+		w = nil
+	default:
+	}
+	r.idle <- w
+}
+
+func (r *Runner) run(ctx context.Context, w **worker, req *roomscript.Request) (*roomscript.Result, error) {
+	select {
+	case <-r.done:
+		return nil, ErrClosed
+	default:
+	}
+
+	if *w == nil {
+		started, err := r.startWorker()
+		if err != nil {
+			return nil, err
+		}
+		*w = started
+	}
+
+	request := *req
+	request.MaxSteps = r.cfg.MaxSteps
+
+	result, err := (*w).call(ctx, &request, r.cfg.Timeout)
+	if err != nil {
+		r.logger.Warn("Script worker stopped",
+			zap.Error(err),
+			zap.String("script_id", req.ScriptID),
+			zap.String("hook", req.Hook),
+		)
+		(*w).kill()
+		*w = nil
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Close stops every idle worker. Workers busy with a hook stop when it returns.
+func (r *Runner) Close() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+
+	for {
+		select {
+		case w := <-r.idle:
+			if w != nil {
+				w.kill()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (r *Runner) startWorker() (*worker, error) {
+	cmd := exec.Command(r.cfg.WorkerPath, "-memory-limit", strconv.FormatInt(r.cfg.MemoryLimit, 10))
+	cmd.Env = []string{"GOMAXPROCS=1"}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open worker stdin")
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open worker stdout")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, "failed to start script worker")
+	}
+
+	return &worker{
+		cmd:     cmd,
+		stdin:   stdin,
+		results: json.NewDecoder(bufio.NewReader(stdout)),
+	}, nil
+}
+
+// worker is a running script-worker process. It speaks one JSON request per
+// line on stdin and answers with one JSON result per line on stdout.
+type worker struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	results *json.Decoder
+}
+
+type callResult struct {
+	result *roomscript.Result
+	err    error
+}
+
+func (w *worker) call(ctx context.Context, req *roomscript.Request, timeout time.Duration) (*roomscript.Result, error) {
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal script request")
+	}
+
+	if _, err := w.stdin.Write(append(line, '\n')); err != nil {
+		return nil, errors.Wrap(ErrWorkerFailed, err.Error())
+	}
+
+	// The read is abandoned on timeout; killing the worker ends it.
+	done := make(chan callResult, 1)
+	go func() {
+		var result roomscript.Result
+		if err := w.results.Decode(&result); err != nil {
+			done <- callResult{err: errors.Wrap(ErrWorkerFailed, err.Error())}
+			return
+		}
+		done <- callResult{result: &result}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		return res.result, res.err
+	case <-timer.C:
+		return nil, ErrTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (w *worker) kill() {
+	w.stdin.Close()
+	if w.cmd.Process != nil {
+		w.cmd.Process.Kill()
+	}
+	w.cmd.Wait()
+}
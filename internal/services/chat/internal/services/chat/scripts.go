@@ -0,0 +1,240 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/pkg/roomscript"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// scriptCacheTTL is how long the scripts of a room are used before they are
+	// fetched again, and so how long a script change takes to apply.
+	scriptCacheTTL = time.Minute
+	// scriptRetryDelay is how long cached scripts are kept after a failed fetch
+	// before fetching them again.
+	scriptRetryDelay = 5 * time.Second
+	// scheduleCheckInterval is how often scheduled hooks are checked for due runs.
+	scheduleCheckInterval = 15 * time.Second
+	// scriptHookTimeout bounds hooks run in the background, waiting for a free
+	// worker included.
+	scriptHookTimeout = 30 * time.Second
+)
+
+type cachedScripts struct {
+	scripts []*entities.RoomScript
+	checkAt time.Time // When the scripts are fetched again.
+}
+
+// runMessageHooks runs on_message of every script of the room, in order. Each
+// script sees the content returned by the previous one. The replies of the
+// scripts are returned to be posted once the message is stored; a rejected
+// message is reported with a MessageRejectedError together with the replies.
+func (s *Service) runMessageHooks(ctx context.Context, msg *entities.Message) ([]*entities.Message, error) {
+	var replies []*entities.Message
+	for _, script := range s.roomScripts(ctx, msg.RoomID) {
+		event := roomscript.Event{
+			RoomID:  msg.RoomID.String(),
+			UserID:  msg.UserID.String(),
+			Content: msg.Content,
+		}
+		if msg.ParentID != nil {
+			event.ParentID = msg.ParentID.String()
+		}
+
+		result := s.runHook(ctx, script, roomscript.HookMessage, event)
+		if result == nil {
+			continue
+		}
+
+		replies = append(replies, scriptReplies(script, msg.ParentID, result.Replies)...)
+		if result.Rejected {
+			return replies, &entities.MessageRejectedError{Reason: result.Reason}
+		}
+		if result.Content != nil {
+			msg.Content = *result.Content
+		}
+	}
+
+	return replies, nil
+}
+
+// runJoinHooks runs on_join of every script of the room in the background.
+func (s *Service) runJoinHooks(roomID, userID uuid.UUID) {
+	if s.scripts == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), scriptHookTimeout)
+		defer cancel()
+
+		for _, script := range s.roomScripts(ctx, roomID) {
+			result := s.runHook(ctx, script, roomscript.HookJoin, roomscript.Event{
+				RoomID: roomID.String(),
+				UserID: userID.String(),
+			})
+			if result != nil {
+				s.postScriptReplies(ctx, scriptReplies(script, nil, result.Replies))
+			}
+		}
+	}()
+}
+
+// runHook runs a hook of a script and returns its result, or nil when the hook
+// failed. Failures are logged for the room owner's benefit and otherwise ignored:
+// a broken script must not stop the room.
+func (s *Service) runHook(ctx context.Context, script *entities.RoomScript, hook string, event roomscript.Event) *roomscript.Result {
+	result, err := s.scripts.Run(ctx, &roomscript.Request{
+		ScriptID: script.ID.String(),
+		Source:   script.Source,
+		Hook:     hook,
+		Event:    event,
+	})
+	if err == nil && result.Error != "" {
+		err = errors.New(result.Error)
+	}
+	if err != nil {
+		s.logger.Warn("Room script failed",
+			zap.Error(err),
+			zap.String("room_id", script.RoomID.String()),
+			zap.String("script_id", script.ID.String()),
+			zap.String("script_name", script.Name),
+			zap.String("hook", hook),
+		)
+		return nil
+	}
+
+	return result
+}
+
+// roomScripts returns the enabled scripts of a room. When they cannot be
+// fetched, the scripts fetched last are used until a retry shortly after; a
+// room whose scripts were never fetched runs none until then.
+func (s *Service) roomScripts(ctx context.Context, roomID uuid.UUID) []*entities.RoomScript {
+	if s.scripts == nil {
+		return nil
+	}
+
+	s.scriptMu.Lock()
+	cached, exists := s.scriptCache[roomID]
+	s.scriptMu.Unlock()
+	if exists && time.Now().Before(cached.checkAt) {
+		return cached.scripts
+	}
+
+	scripts, err := s.website.GetRoomScripts(ctx, roomID)
+	if err != nil {
+		s.logger.Error("Failed to get room scripts",
+			zap.Error(err),
+			zap.String("room_id", roomID.String()),
+		)
+		if !exists {
+			return nil
+		}
+
+		s.scriptMu.Lock()
+		s.scriptCache[roomID] = &cachedScripts{scripts: cached.scripts, checkAt: time.Now().Add(scriptRetryDelay)}
+		s.scriptMu.Unlock()
+		return cached.scripts
+	}
+
+	s.scriptMu.Lock()
+	s.scriptCache[roomID] = &cachedScripts{scripts: scripts, checkAt: time.Now().Add(scriptCacheTTL)}
+	s.scriptMu.Unlock()
+
+	return scripts
+}
+
+// pruneScriptCache forgets the scripts of rooms that were not used for a while.
+func (s *Service) pruneScriptCache() {
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+
+	now := time.Now()
+	for roomID, cached := range s.scriptCache {
+		if now.Sub(cached.checkAt) >= scriptCacheTTL {
+			delete(s.scriptCache, roomID)
+		}
+	}
+}
+
+func (s *Service) startScheduleTicker() {
+	if s.scripts == nil {
+		return
+	}
+
+	s.scheduleTick = time.NewTicker(scheduleCheckInterval)
+	go func() {
+		for range s.scheduleTick.C {
+			s.runSchedules()
+		}
+	}()
+}
+
+// runSchedules runs on_schedule of the scripts that are due. Every instance
+// checks every scheduled script; storage makes sure only one of them runs it.
+func (s *Service) runSchedules() {
+	ctx, cancel := context.WithTimeout(context.Background(), scriptHookTimeout)
+	defer cancel()
+
+	scripts, err := s.website.GetScheduledScripts(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get scheduled scripts", zap.Error(err))
+		return
+	}
+
+	for _, script := range scripts {
+		claimed, err := s.storage.ClaimScriptRun(ctx, script.ID, script.ScheduleInterval, time.Now())
+		if err != nil {
+			s.logger.Error("Failed to claim script run",
+				zap.Error(err),
+				zap.String("script_id", script.ID.String()),
+			)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		result := s.runHook(ctx, script, roomscript.HookSchedule, roomscript.Event{RoomID: script.RoomID.String()})
+		if result != nil {
+			s.postScriptReplies(ctx, scriptReplies(script, nil, result.Replies))
+		}
+	}
+}
+
+// postScriptReplies stores and broadcasts the replies of a script. Replies do
+// not run on_message hooks, so scripts cannot trigger each other.
+func (s *Service) postScriptReplies(ctx context.Context, replies []*entities.Message) {
+	for _, reply := range replies {
+		if _, err := s.postMessage(ctx, reply); err != nil {
+			s.logger.Error("Failed to post script reply",
+				zap.Error(err),
+				zap.String("room_id", reply.RoomID.String()),
+				zap.String("script_id", reply.UserID.String()),
+			)
+		}
+	}
+}
+
+// scriptReplies turns the replies of a script into messages authored by the
+// script. Replies to a thread message stay in its thread.
+func scriptReplies(script *entities.RoomScript, parentID *uuid.UUID, texts []string) []*entities.Message {
+	replies := make([]*entities.Message, len(texts))
+	for i, text := range texts {
+		replies[i] = &entities.Message{
+			ID:        uuid.New(),
+			RoomID:    script.RoomID,
+			UserID:    script.ID,
+			ParentID:  parentID,
+			Content:   text,
+			Timestamp: time.Now(),
+		}
+	}
+	return replies
+}
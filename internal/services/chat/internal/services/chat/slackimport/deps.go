@@ -0,0 +1,17 @@
+package slackimport
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+type Storage interface {
+	ImportMessages(ctx context.Context, messages []*entities.Message) (int, error)
+	AddParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
+}
+
+type Deps struct {
+	Storage Storage
+}
@@ -0,0 +1,254 @@
+// Package slackimport brings the messages of a Slack export into the rooms the
+// Website Service import created for its conversations. Messages keep their
+// original timestamps, threads and authors; bot messages are shown as
+// integration messages. Files are not downloaded: a message only names the
+// files it shared.
+//
+// Message IDs derive from the conversation and Slack timestamp of each
+// message, so running the import again stores only what is missing.
+package slackimport
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/pkg/slackexport"
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// namespace derives the IDs of imported messages and bots.
+var namespace = uuid.MustParse("e74128ba-c5dd-410b-9b4c-c9d5a3f5948c")
+
+// maxAuthorNameLength is the size of the author name column.
+const maxAuthorNameLength = 50
+
+// importedSubtypes are the Slack message subtypes carrying something people
+// wrote. Others, such as joins and topic changes, are left out.
+var importedSubtypes = map[string]bool{
+	"":                 true,
+	"me_message":       true,
+	"bot_message":      true,
+	"file_share":       true,
+	"thread_broadcast": true,
+}
+
+type Config struct {
+	BatchSize int // Messages stored per insert.
+}
+
+// Result counts what an import did.
+type Result struct {
+	Rooms    int
+	Messages int // Messages stored.
+	Existing int // Messages stored by a previous run.
+	Skipped  int // Messages of other subtypes, empty or by unknown users.
+}
+
+type Importer struct {
+	storage Storage
+	cfg     Config
+	logger  *zap.Logger
+}
+
+func New(deps Deps, cfg Config, logger *zap.Logger) *Importer {
+	return &Importer{
+		storage: deps.Storage,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Import stores the messages of every conversation of the archive that has a
+// room in mapping, and adds their authors and members to the participants of
+// the room.
+func (i *Importer) Import(ctx context.Context, archive *slackexport.Archive, mapping *slackexport.Mapping) (*Result, error) {
+	conversations, err := archive.Conversations()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for idx := range conversations {
+		conversation := &conversations[idx]
+
+		roomID, ok := mapping.Rooms[conversation.ID]
+		if !ok {
+			i.logger.Warn("Skipping conversation without room",
+				zap.String("conversation_id", conversation.ID),
+				zap.String("name", conversation.Name),
+			)
+			continue
+		}
+
+		if err := i.importConversation(ctx, archive, conversation, roomID, mapping, result); err != nil {
+			return result, errors.Wrapf(err, "failed to import conversation %s", conversation.ID)
+		}
+		result.Rooms++
+	}
+
+	return result, nil
+}
+
+// conversationImport holds the state of the import of one conversation.
+type conversationImport struct {
+	roomID       uuid.UUID
+	conversation *slackexport.Conversation
+	mapping      *slackexport.Mapping
+	imported     map[string]bool // Slack timestamps of imported messages.
+	participants map[uuid.UUID]bool
+	batch        []*entities.Message
+}
+
+func (i *Importer) importConversation(ctx context.Context, archive *slackexport.Archive, conversation *slackexport.Conversation, roomID uuid.UUID, mapping *slackexport.Mapping, result *Result) error {
+	c := &conversationImport{
+		roomID:       roomID,
+		conversation: conversation,
+		mapping:      mapping,
+		imported:     make(map[string]bool),
+		participants: make(map[uuid.UUID]bool),
+	}
+	for _, slackID := range conversation.Members {
+		if user, ok := mapping.Users[slackID]; ok {
+			c.participants[user.ID] = true
+		}
+	}
+
+	err := archive.Messages(conversation, func(slackMsg *slackexport.Message) error {
+		msg, ok := c.convert(slackMsg)
+		if !ok {
+			result.Skipped++
+			return nil
+		}
+
+		c.batch = append(c.batch, msg)
+		if len(c.batch) >= i.cfg.BatchSize {
+			return i.flush(ctx, c, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := i.flush(ctx, c, result); err != nil {
+		return err
+	}
+
+	for userID := range c.participants {
+		if _, err := i.storage.AddParticipant(ctx, roomID, userID); err != nil {
+			return err
+		}
+	}
+
+	i.logger.Info("Imported conversation",
+		zap.String("conversation_id", conversation.ID),
+		zap.String("room_id", roomID.String()),
+		zap.Int("messages", len(c.imported)),
+	)
+	return nil
+}
+
+func (i *Importer) flush(ctx context.Context, c *conversationImport, result *Result) error {
+	if len(c.batch) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored, err := i.storage.ImportMessages(ctx, c.batch)
+	if err != nil {
+		return err
+	}
+	result.Messages += stored
+	result.Existing += len(c.batch) - stored
+
+	c.batch = c.batch[:0]
+	return nil
+}
+
+// convert turns a Slack message into a message of the room, or reports that
+// it is not imported.
+func (c *conversationImport) convert(slackMsg *slackexport.Message) (*entities.Message, bool) {
+	if slackMsg.Type != "message" || !importedSubtypes[slackMsg.Subtype] {
+		return nil, false
+	}
+
+	timestamp, err := slackMsg.Time()
+	if err != nil {
+		return nil, false
+	}
+
+	content := convertText(slackMsg.Text, c.mapping)
+	for _, file := range slackMsg.Files {
+		name := file.Name
+		if name == "" {
+			name = file.Title
+		}
+		content = strings.TrimSpace(content + "\n[file: " + name + "]")
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, false
+	}
+
+	msg := &entities.Message{
+		ID:        messageID(c.conversation.ID, slackMsg.TS),
+		RoomID:    c.roomID,
+		Content:   content,
+		Timestamp: timestamp,
+	}
+
+	switch user, ok := c.mapping.Users[slackMsg.User]; {
+	case ok:
+		msg.UserID = user.ID
+		c.participants[user.ID] = true
+		if slackMsg.Subtype == "me_message" {
+			msg.Kind = entities.MessageKindEmote
+		}
+	case slackMsg.BotID != "":
+		msg.UserID = uuid.NewSHA1(namespace, []byte("bot:"+slackMsg.BotID))
+		msg.Kind = entities.MessageKindIntegration
+		msg.AuthorName = botName(slackMsg)
+	default:
+		return nil, false
+	}
+
+	// Replies keep their thread only when its first message was imported.
+	if slackMsg.IsReply() && c.imported[slackMsg.ThreadTS] {
+		parentID := messageID(c.conversation.ID, slackMsg.ThreadTS)
+		msg.ParentID = &parentID
+	}
+	if slackMsg.Edited != nil {
+		if editedAt, err := slackexport.ParseTS(slackMsg.Edited.TS); err == nil {
+			msg.EditedAt = &editedAt
+		}
+	}
+
+	c.imported[slackMsg.TS] = true
+	return msg, true
+}
+
+// messageID identifies a Slack message: its timestamp is unique within its
+// conversation.
+func messageID(conversationID, ts string) uuid.UUID {
+	return uuid.NewSHA1(namespace, []byte(conversationID+":"+ts))
+}
+
+func botName(slackMsg *slackexport.Message) string {
+	name := slackMsg.Username
+	if name == "" && slackMsg.BotProfile != nil {
+		name = slackMsg.BotProfile.Name
+	}
+	if name == "" {
+		name = "bot"
+	}
+
+	for utf8.RuneCountInString(name) > maxAuthorNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
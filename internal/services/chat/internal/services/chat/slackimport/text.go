@@ -0,0 +1,68 @@
+package slackimport
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HexArch/go-chat/internal/pkg/slackexport"
+)
+
+// slackToken matches the <…> sequences Slack uses for mentions and links.
+var slackToken = regexp.MustCompile(`<([^<>]*)>`)
+
+// slackEntities are the only characters Slack escapes in message text.
+var slackEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// convertText turns the markup of a Slack message into plain text: mentions
+// become @username and #channel, links their label followed by the URL, and
+// escaped characters are restored.
+func convertText(text string, mapping *slackexport.Mapping) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range slackToken.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(slackEntities.Replace(text[last:loc[0]]))
+		b.WriteString(convertToken(text[loc[2]:loc[3]], mapping))
+		last = loc[1]
+	}
+	b.WriteString(slackEntities.Replace(text[last:]))
+	return b.String()
+}
+
+func convertToken(token string, mapping *slackexport.Mapping) string {
+	target, label, _ := strings.Cut(token, "|")
+	label = slackEntities.Replace(label)
+
+	switch {
+	case strings.HasPrefix(target, "@"):
+		if user, ok := mapping.Users[target[1:]]; ok {
+			return "@" + user.Username
+		}
+		if label != "" {
+			return "@" + strings.TrimPrefix(label, "@")
+		}
+		return target
+
+	case strings.HasPrefix(target, "#"):
+		if label != "" {
+			return "#" + label
+		}
+		return target
+
+	case strings.HasPrefix(target, "!"):
+		// Special mentions such as <!here>, user groups and dates.
+		if label != "" {
+			return label
+		}
+		return "@" + strings.TrimPrefix(target, "!")
+
+	default:
+		url := slackEntities.Replace(target)
+		if mail, ok := strings.CutPrefix(url, "mailto:"); ok {
+			url = mail
+		}
+		if label == "" || label == url {
+			return url
+		}
+		return label + " (" + url + ")"
+	}
+}
@@ -0,0 +1,135 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetSlowMode sets the minimum interval between two messages of a user in the
+// room. Only the room owner may change it; a zero interval turns slow mode off.
+func (s *Service) SetSlowMode(ctx context.Context, roomID, userID uuid.UUID, interval time.Duration) error {
+	if interval < 0 || interval > entities.MaxSlowMode || interval%time.Second != 0 {
+		return errors.Wrapf(entities.ErrInvalidSlowMode, "interval must be whole seconds between 0 and %s", entities.MaxSlowMode)
+	}
+
+	ownerID, err := s.website.GetRoomOwner(ctx, roomID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get room owner")
+	}
+	if ownerID != userID {
+		return entities.ErrForbidden
+	}
+
+	if err := s.storage.SetSlowMode(ctx, roomID, interval); err != nil {
+		return errors.Wrap(err, "failed to save slow mode")
+	}
+
+	payload, err := json.Marshal(entities.SlowModePayload{Seconds: int(interval / time.Second)})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal slow mode")
+	}
+
+	// Every instance, this one included, applies the interval when delivering the event.
+	s.broadcastPersisted(ctx, &entities.Event{
+		Type:      entities.EventSlowModeUpdated,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+
+	return nil
+}
+
+// checkSlowMode accepts a message of the user unless slow mode is on and the
+// user's last stored message in the room is more recent than the interval. The
+// time comes from storage, so the interval holds whichever instance the user is
+// connected to, and messages refused by a hook or not stored do not count. Two
+// messages sent at the same moment may both be accepted.
+func (s *Service) checkSlowMode(ctx context.Context, roomID, userID uuid.UUID) error {
+	interval, err := s.slowModeInterval(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if interval == 0 {
+		return nil
+	}
+
+	last, err := s.storage.GetLastMessageTime(ctx, roomID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get last message time")
+	}
+	if last == nil {
+		return nil
+	}
+
+	if wait := interval - time.Since(*last); wait > 0 {
+		return &entities.RateLimitError{Scope: entities.RateLimitSlowMode, RetryAfter: wait}
+	}
+	return nil
+}
+
+// slowModeInterval returns the slow mode of a room, read from storage unless it
+// is known already.
+func (s *Service) slowModeInterval(ctx context.Context, roomID uuid.UUID) (time.Duration, error) {
+	s.slowModeMu.Lock()
+	interval, exists := s.slowModes[roomID]
+	s.slowModeMu.Unlock()
+	if exists {
+		return interval, nil
+	}
+
+	interval, err := s.storage.GetSlowMode(ctx, roomID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get slow mode")
+	}
+
+	s.slowModeMu.Lock()
+	defer s.slowModeMu.Unlock()
+
+	// A slow mode event received meanwhile is newer than what was read.
+	if current, exists := s.slowModes[roomID]; exists {
+		return current, nil
+	}
+	s.slowModes[roomID] = interval
+	return interval, nil
+}
+
+// applySlowMode updates the slow mode of a room from a slow mode event.
+func (s *Service) applySlowMode(event *entities.Event) {
+	var payload entities.SlowModePayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		s.logger.Error("Failed to unmarshal slow mode", zap.Error(err))
+		return
+	}
+
+	s.slowModeMu.Lock()
+	defer s.slowModeMu.Unlock()
+
+	s.slowModes[event.RoomID] = time.Duration(payload.Seconds) * time.Second
+}
+
+// pruneSlowMode forgets the slow mode of rooms without local connections.
+func (s *Service) pruneSlowMode() {
+	s.mu.RLock()
+	active := make(map[uuid.UUID]struct{}, len(s.rooms))
+	for roomID := range s.rooms {
+		active[roomID] = struct{}{}
+	}
+	s.mu.RUnlock()
+
+	s.slowModeMu.Lock()
+	defer s.slowModeMu.Unlock()
+
+	for roomID := range s.slowModes {
+		if _, ok := active[roomID]; !ok {
+			delete(s.slowModes, roomID)
+		}
+	}
+}
@@ -3,22 +3,95 @@ package storage
 import (
 	"time"
 
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/google/uuid"
 )
 
 // MessageDTO represents a chat message in the database.
 type MessageDTO struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	RoomID    uuid.UUID `gorm:"type:uuid;index"`
-	UserID    uuid.UUID `gorm:"type:uuid;index"`
-	Content   string    `gorm:"type:text"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4();index:idx_chat_messages_room_keyset,priority:3"`
+	RoomID      uuid.UUID  `gorm:"type:uuid;index;index:idx_chat_messages_room_keyset,priority:1"`
+	UserID      uuid.UUID  `gorm:"type:uuid;index;uniqueIndex:idx_chat_messages_client_msg,priority:1"`
+	ClientMsgID *string    `gorm:"column:client_msg_id;type:varchar(64);uniqueIndex:idx_chat_messages_client_msg,priority:2"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index"`
+	Kind        string     `gorm:"type:varchar(16);not null;default:''"`
+	AuthorName  string     `gorm:"type:varchar(50);not null;default:''"`
+	Content     string     `gorm:"type:text"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime;index:idx_chat_messages_room_keyset,priority:2"`
+	EditedAt    *time.Time `gorm:"column:edited_at"`
+	DeletedAt   *time.Time `gorm:"column:deleted_at;index"`
 }
 
 func (MessageDTO) TableName() string {
 	return "chat_messages"
 }
 
+func dtoToMessage(dto *MessageDTO) *entities.Message {
+	msg := &entities.Message{
+		ID:         dto.ID,
+		RoomID:     dto.RoomID,
+		UserID:     dto.UserID,
+		ParentID:   dto.ParentID,
+		Kind:       dto.Kind,
+		AuthorName: dto.AuthorName,
+		Content:    dto.Content,
+		Timestamp:  dto.CreatedAt,
+		EditedAt:   dto.EditedAt,
+		DeletedAt:  dto.DeletedAt,
+	}
+	if dto.ClientMsgID != nil {
+		msg.ClientMsgID = *dto.ClientMsgID
+	}
+	return msg
+}
+
+// ArchivedMessageDTO is a message purged by the retention policy of its room
+// while archiving is on. Archived messages are kept for compliance only; they
+// are never served to clients.
+type ArchivedMessageDTO struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	RoomID      uuid.UUID  `gorm:"type:uuid;index"`
+	UserID      uuid.UUID  `gorm:"type:uuid"`
+	ClientMsgID *string    `gorm:"column:client_msg_id;type:varchar(64)"`
+	ParentID    *uuid.UUID `gorm:"type:uuid"`
+	Kind        string     `gorm:"type:varchar(16);not null;default:''"`
+	AuthorName  string     `gorm:"type:varchar(50);not null;default:''"`
+	Content     string     `gorm:"type:text"`
+	CreatedAt   time.Time
+	EditedAt    *time.Time `gorm:"column:edited_at"`
+	DeletedAt   *time.Time `gorm:"column:deleted_at"`
+	ArchivedAt  time.Time  `gorm:"not null;index"`
+}
+
+func (ArchivedMessageDTO) TableName() string {
+	return "chat_messages_archive"
+}
+
+// MessageReactionDTO represents a single user's reaction to a message in the database.
+type MessageReactionDTO struct {
+	MessageID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_message_user_emoji"`
+	UserID    uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_message_user_emoji"`
+	Emoji     string    `gorm:"type:varchar(64);uniqueIndex:idx_message_user_emoji"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (MessageReactionDTO) TableName() string {
+	return "chat_message_reactions"
+}
+
+// ReadCursorDTO represents the last message a user has read in a room.
+type ReadCursorDTO struct {
+	RoomID            uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_read_cursor_room_user"`
+	UserID            uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_read_cursor_room_user"`
+	LastReadMessageID uuid.UUID `gorm:"type:uuid"`
+	LastReadAt        time.Time
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ReadCursorDTO) TableName() string {
+	return "chat_read_cursors"
+}
+
 // RoomParticipantDTO represents a room participant in the database.
 type RoomParticipantDTO struct {
 	RoomID    uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_room_user"`
@@ -29,3 +102,152 @@ type RoomParticipantDTO struct {
 func (RoomParticipantDTO) TableName() string {
 	return "chat_room_participants"
 }
+
+// PresenceSessionDTO records that a user is connected to a chat instance.
+// The instance refreshes UpdatedAt periodically; rows it stops refreshing expire.
+type PresenceSessionDTO struct {
+	UserID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	InstanceID string    `gorm:"type:varchar(64);primaryKey"`
+	Status     string    `gorm:"type:varchar(16)"`
+	UpdatedAt  time.Time `gorm:"index"`
+}
+
+func (PresenceSessionDTO) TableName() string {
+	return "chat_presence_sessions"
+}
+
+// LastSeenDTO holds the last time a user was connected to any chat instance.
+type LastSeenDTO struct {
+	UserID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LastSeenAt time.Time
+}
+
+func (LastSeenDTO) TableName() string {
+	return "chat_user_last_seen"
+}
+
+// RoomSequenceDTO holds the last sequence number assigned to an event of a room.
+type RoomSequenceDTO struct {
+	RoomID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LastSeq int64     `gorm:"not null"`
+}
+
+func (RoomSequenceDTO) TableName() string {
+	return "chat_room_sequences"
+}
+
+// RoomEventDTO is a persisted room event. Events are replayed in sequence order
+// to clients resuming after a reconnect.
+type RoomEventDTO struct {
+	RoomID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Seq       int64     `gorm:"primaryKey;autoIncrement:false"`
+	Type      string    `gorm:"type:varchar(32)"`
+	UserID    uuid.UUID `gorm:"type:uuid"`
+	Payload   []byte    `gorm:"type:bytea"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (RoomEventDTO) TableName() string {
+	return "chat_room_events"
+}
+
+// RoomSettingsDTO holds the chat settings of a room chosen by its owner or moderators.
+type RoomSettingsDTO struct {
+	RoomID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SlowModeSeconds int       `gorm:"not null;default:0"`
+	Topic           string    `gorm:"type:text;not null;default:''"`
+	UpdatedAt       time.Time
+}
+
+func (RoomSettingsDTO) TableName() string {
+	return "chat_room_settings"
+}
+
+// RoomMuteDTO records that a user may not post to a room until a given time.
+type RoomMuteDTO struct {
+	RoomID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Until     time.Time `gorm:"not null;index"`
+	MutedBy   uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (RoomMuteDTO) TableName() string {
+	return "chat_room_mutes"
+}
+
+// ScriptRunDTO holds when the scheduled hook of a room script runs next.
+// Instances claim a run by moving next_run_at forward, so each run happens once.
+type ScriptRunDTO struct {
+	ScriptID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	NextRunAt time.Time `gorm:"not null"`
+}
+
+func (ScriptRunDTO) TableName() string {
+	return "chat_script_runs"
+}
+
+// WebhookOutboxDTO is a room event waiting to be published to the Website
+// Service for the outgoing webhooks of its room. Instances claim an event by
+// moving next_attempt_at forward, and delete it once it is published.
+type WebhookOutboxDTO struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoomID        uuid.UUID `gorm:"type:uuid;not null"`
+	Type          string    `gorm:"type:varchar(32);not null"`
+	Data          []byte    `gorm:"type:bytea"`
+	OccurredAt    time.Time `gorm:"not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (WebhookOutboxDTO) TableName() string {
+	return "chat_webhook_outbox"
+}
+
+// AttachmentDTO represents a file uploaded to a room. MessageID stays NULL until
+// a message of the uploader references the attachment.
+type AttachmentDTO struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	RoomID       uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UploaderID   uuid.UUID  `gorm:"type:uuid;not null"`
+	MessageID    *uuid.UUID `gorm:"type:uuid;index"`
+	FileName     string     `gorm:"type:varchar(255);not null"`
+	ContentType  string     `gorm:"type:varchar(255);not null"`
+	Size         int64      `gorm:"not null"`
+	Width        int        `gorm:"not null;default:0"`
+	Height       int        `gorm:"not null;default:0"`
+	HasThumbnail bool       `gorm:"not null;default:false"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime;index"`
+}
+
+func (AttachmentDTO) TableName() string {
+	return "chat_attachments"
+}
+
+func dtoToAttachment(dto *AttachmentDTO) *entities.Attachment {
+	return &entities.Attachment{
+		ID:           dto.ID,
+		RoomID:       dto.RoomID,
+		UploaderID:   dto.UploaderID,
+		MessageID:    dto.MessageID,
+		FileName:     dto.FileName,
+		ContentType:  dto.ContentType,
+		Size:         dto.Size,
+		Width:        dto.Width,
+		Height:       dto.Height,
+		HasThumbnail: dto.HasThumbnail,
+		CreatedAt:    dto.CreatedAt,
+	}
+}
+
+func dtoToEvent(dto *RoomEventDTO) *entities.Event {
+	return &entities.Event{
+		Type:      entities.EventType(dto.Type),
+		RoomID:    dto.RoomID,
+		UserID:    dto.UserID,
+		Payload:   dto.Payload,
+		Timestamp: dto.CreatedAt,
+		Seq:       dto.Seq,
+	}
+}
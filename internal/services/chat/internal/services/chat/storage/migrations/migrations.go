@@ -14,5 +14,66 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(&storage.RoomParticipantDTO{}); err != nil {
 		return errors.Wrap(err, "failed to migrate ParticipantDTO")
 	}
+
+	if err := db.AutoMigrate(&storage.MessageReactionDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate MessageReactionDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.ReadCursorDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate ReadCursorDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.PresenceSessionDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate PresenceSessionDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.LastSeenDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate LastSeenDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.RoomSequenceDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate RoomSequenceDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.RoomEventDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate RoomEventDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.RoomSettingsDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate RoomSettingsDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.RoomMuteDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate RoomMuteDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.ScriptRunDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate ScriptRunDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.WebhookOutboxDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate WebhookOutboxDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.AttachmentDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate AttachmentDTO")
+	}
+
+	if err := db.AutoMigrate(&storage.ArchivedMessageDTO{}); err != nil {
+		return errors.Wrap(err, "failed to migrate ArchivedMessageDTO")
+	}
+
+	// The search vector is a generated column, so it is managed with raw SQL
+	// instead of being mapped on MessageDTO. The 'simple' configuration is
+	// language-agnostic, which suits rooms mixing several languages.
+	if err := db.Exec(`ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', coalesce(content, ''))) STORED`).Error; err != nil {
+		return errors.Wrap(err, "failed to add search vector column")
+	}
+
+	if err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_chat_messages_search_vector
+		ON chat_messages USING GIN (search_vector)`).Error; err != nil {
+		return errors.Wrap(err, "failed to create search vector index")
+	}
 	return nil
 }
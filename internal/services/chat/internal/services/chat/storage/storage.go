@@ -2,6 +2,9 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"html"
+	"strings"
 	"time"
 
 	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
@@ -22,61 +25,856 @@ func NewStorage(db *gorm.DB) *Storage {
 	return &Storage{db: db}
 }
 
-// SaveMessage stores a new message in the database.
-func (s *Storage) SaveMessage(ctx context.Context, msg *entities.Message) error {
+// SaveMessage stores a new message in the database and returns the stored message.
+// A message carrying a client message ID that its user already used is not stored
+// again; the message stored the first time is returned instead. When a new message
+// is stored, its event is appended to the room's event log in the same transaction.
+func (s *Storage) SaveMessage(ctx context.Context, msg *entities.Message, event *entities.Event) (*entities.Message, error) {
 	dto := &MessageDTO{
-		ID:        msg.ID,
-		RoomID:    msg.RoomID,
-		UserID:    msg.UserID,
-		Content:   msg.Content,
-		CreatedAt: msg.Timestamp,
+		ID:         msg.ID,
+		RoomID:     msg.RoomID,
+		UserID:     msg.UserID,
+		ParentID:   msg.ParentID,
+		Kind:       msg.Kind,
+		AuthorName: msg.AuthorName,
+		Content:    msg.Content,
+		CreatedAt:  msg.Timestamp,
 	}
 
-	if err := s.db.WithContext(ctx).Create(dto).Error; err != nil {
-		return errors.Wrap(err, "failed to save message")
+	var onConflict []clause.Expression
+	if msg.ClientMsgID != "" {
+		dto.ClientMsgID = &msg.ClientMsgID
+		onConflict = append(onConflict, clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_msg_id"}},
+			DoNothing: true,
+		})
+	}
+
+	created := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(onConflict...).Create(dto)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		created = true
+		if err := attachToMessage(tx, msg); err != nil {
+			return err
+		}
+		return appendEvent(tx, event)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save message")
+	}
+	if created {
+		return msg, nil
+	}
+
+	var existing MessageDTO
+	if err := s.db.WithContext(ctx).
+		Where("user_id = ? AND client_msg_id = ?", msg.UserID, msg.ClientMsgID).
+		First(&existing).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get deduplicated message")
+	}
+
+	return dtoToMessage(&existing), nil
+}
+
+// GetMessageByClientMsgID returns the message a user stored with a client
+// message ID, or nil when they did not use the ID yet.
+func (s *Storage) GetMessageByClientMsgID(ctx context.Context, userID uuid.UUID, clientMsgID string) (*entities.Message, error) {
+	var dto MessageDTO
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND client_msg_id = ?", userID, clientMsgID).
+		Take(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get message by client message ID")
+	}
+
+	return dtoToMessage(&dto), nil
+}
+
+// GetLastMessageTime returns when the user last posted in the room, or nil when
+// they never did. Deleted messages count.
+func (s *Storage) GetLastMessageTime(ctx context.Context, roomID, userID uuid.UUID) (*time.Time, error) {
+	var row struct {
+		Last *time.Time
+	}
+	if err := s.db.WithContext(ctx).
+		Model(&MessageDTO{}).
+		Select("MAX(created_at) AS last").
+		Where("room_id = ? AND user_id = ?", roomID, userID).
+		Scan(&row).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get last message time")
+	}
+
+	return row.Last, nil
+}
+
+// ImportMessages stores messages brought from another chat system, keeping
+// their IDs and timestamps. Messages whose ID is already stored are skipped,
+// so an import can be run again; the number of stored messages is returned.
+// No events are appended: imported messages are history, not news.
+func (s *Storage) ImportMessages(ctx context.Context, messages []*entities.Message) (int, error) {
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	dtos := make([]*MessageDTO, len(messages))
+	for i, msg := range messages {
+		dtos[i] = &MessageDTO{
+			ID:         msg.ID,
+			RoomID:     msg.RoomID,
+			UserID:     msg.UserID,
+			ParentID:   msg.ParentID,
+			Kind:       msg.Kind,
+			AuthorName: msg.AuthorName,
+			Content:    msg.Content,
+			CreatedAt:  msg.Timestamp,
+			EditedAt:   msg.EditedAt,
+		}
+	}
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoNothing: true}).
+		Create(&dtos)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "failed to import messages")
+	}
+	return int(result.RowsAffected), nil
+}
+
+// attachToMessage links the attachments of a new message to it. They must be
+// unused uploads of its author in its room; otherwise the message is refused.
+func attachToMessage(tx *gorm.DB, msg *entities.Message) error {
+	if len(msg.Attachments) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(msg.Attachments))
+	for i, attachment := range msg.Attachments {
+		ids[i] = attachment.ID
+	}
+
+	result := tx.Model(&AttachmentDTO{}).
+		Where("id IN ? AND room_id = ? AND uploader_id = ? AND message_id IS NULL", ids, msg.RoomID, msg.UserID).
+		Update("message_id", msg.ID)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to link attachments")
+	}
+	if result.RowsAffected != int64(len(ids)) {
+		return errors.Wrap(entities.ErrInvalidAttachment, "attachment is already used")
+	}
+
+	return nil
+}
+
+// AppendEvent appends an event to the event log of its room and sets its sequence number.
+func (s *Storage) AppendEvent(ctx context.Context, event *entities.Event) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return appendEvent(tx, event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to append room event")
+	}
+
+	return nil
+}
+
+// appendEvent assigns the next sequence number of the room to the event and logs it.
+// The sequence row stays locked until the transaction ends, so events of a room
+// commit in sequence order and a rolled back event leaves no gap. Message events
+// are logged by reference, and events too old to be replayed are dropped.
+func appendEvent(tx *gorm.DB, event *entities.Event) error {
+	var seq int64
+	if err := tx.Raw(`INSERT INTO chat_room_sequences (room_id, last_seq) VALUES (?, 1)
+		ON CONFLICT (room_id) DO UPDATE SET last_seq = chat_room_sequences.last_seq + 1
+		RETURNING last_seq`, event.RoomID).Scan(&seq).Error; err != nil {
+		return errors.Wrap(err, "failed to allocate sequence number")
+	}
+
+	payload := []byte(event.Payload)
+	if event.Type.CarriesMessage() {
+		var ref entities.MessageRef
+		if err := json.Unmarshal(event.Payload, &ref); err != nil {
+			return errors.Wrap(err, "failed to read message of event")
+		}
+		var err error
+		if payload, err = json.Marshal(ref); err != nil {
+			return errors.Wrap(err, "failed to marshal message reference")
+		}
+	}
+
+	if err := tx.Create(&RoomEventDTO{
+		RoomID:    event.RoomID,
+		Seq:       seq,
+		Type:      string(event.Type),
+		UserID:    event.UserID,
+		Payload:   payload,
+		CreatedAt: event.Timestamp,
+	}).Error; err != nil {
+		return errors.Wrap(err, "failed to log room event")
+	}
+
+	if seq > entities.MaxReplayEvents {
+		if err := tx.Where("room_id = ? AND seq <= ?", event.RoomID, seq-entities.MaxReplayEvents).
+			Delete(&RoomEventDTO{}).Error; err != nil {
+			return errors.Wrap(err, "failed to trim room events")
+		}
+	}
+
+	event.Seq = seq
+	return nil
+}
+
+// SkipRoomSeq uses up the next sequence number of a room without logging an
+// event, for an event that could not be logged. Clients resuming from before
+// the gap are told to reload the history.
+func (s *Storage) SkipRoomSeq(ctx context.Context, roomID uuid.UUID) error {
+	if err := s.db.WithContext(ctx).Exec(`INSERT INTO chat_room_sequences (room_id, last_seq) VALUES (?, 1)
+		ON CONFLICT (room_id) DO UPDATE SET last_seq = chat_room_sequences.last_seq + 1`, roomID).Error; err != nil {
+		return errors.Wrap(err, "failed to skip room sequence number")
+	}
+
+	return nil
+}
+
+// GetRoomSeq returns the sequence number of the latest event of a room, or 0 without events.
+func (s *Storage) GetRoomSeq(ctx context.Context, roomID uuid.UUID) (int64, error) {
+	var dto RoomSequenceDTO
+	err := s.db.WithContext(ctx).Where("room_id = ?", roomID).Take(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get room sequence")
+	}
+
+	return dto.LastSeq, nil
+}
+
+// GetRoomEvents returns up to limit logged events of a room with afterSeq < seq <= untilSeq, in sequence order.
+func (s *Storage) GetRoomEvents(ctx context.Context, roomID uuid.UUID, afterSeq, untilSeq int64, limit int) ([]*entities.Event, error) {
+	var dtos []RoomEventDTO
+	if err := s.db.WithContext(ctx).
+		Where("room_id = ? AND seq > ? AND seq <= ?", roomID, afterSeq, untilSeq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get room events")
+	}
+
+	events := make([]*entities.Event, len(dtos))
+	for i := range dtos {
+		events[i] = dtoToEvent(&dtos[i])
+	}
+
+	return events, nil
+}
+
+// GetSlowMode returns the minimum interval between two messages of a user in the room, 0 when slow mode is off.
+func (s *Storage) GetSlowMode(ctx context.Context, roomID uuid.UUID) (time.Duration, error) {
+	var dto RoomSettingsDTO
+	err := s.db.WithContext(ctx).Where("room_id = ?", roomID).Take(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get room settings")
+	}
+
+	return time.Duration(dto.SlowModeSeconds) * time.Second, nil
+}
+
+// SetSlowMode stores the slow mode interval of a room; 0 turns slow mode off.
+func (s *Storage) SetSlowMode(ctx context.Context, roomID uuid.UUID, interval time.Duration) error {
+	settings := &RoomSettingsDTO{
+		RoomID:          roomID,
+		SlowModeSeconds: int(interval / time.Second),
+		UpdatedAt:       time.Now(),
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "room_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"slow_mode_seconds", "updated_at"}),
+		}).
+		Create(settings).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "failed to save room settings")
+	}
+
+	return nil
+}
+
+// GetTopic returns the topic of a room, empty when none is set.
+func (s *Storage) GetTopic(ctx context.Context, roomID uuid.UUID) (string, error) {
+	var dto RoomSettingsDTO
+	err := s.db.WithContext(ctx).Where("room_id = ?", roomID).Take(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get room settings")
+	}
+
+	return dto.Topic, nil
+}
+
+// SetTopic stores the topic of a room; an empty topic clears it.
+func (s *Storage) SetTopic(ctx context.Context, roomID uuid.UUID, topic string) error {
+	settings := &RoomSettingsDTO{
+		RoomID:    roomID,
+		Topic:     topic,
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "room_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"topic", "updated_at"}),
+		}).
+		Create(settings).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "failed to save room settings")
+	}
+
+	return nil
+}
+
+// MuteUser stops a user from posting to a room until the given time, replacing
+// any earlier mute.
+func (s *Storage) MuteUser(ctx context.Context, roomID, userID, mutedBy uuid.UUID, until time.Time) error {
+	mute := &RoomMuteDTO{
+		RoomID:  roomID,
+		UserID:  userID,
+		Until:   until,
+		MutedBy: mutedBy,
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "room_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"until", "muted_by", "created_at"}),
+		}).
+		Create(mute).
+		Error
+	if err != nil {
+		return errors.Wrap(err, "failed to save mute")
+	}
+
+	return nil
+}
+
+// UnmuteUser lifts the mute of a user in a room and reports whether there was one.
+func (s *Storage) UnmuteUser(ctx context.Context, roomID, userID uuid.UUID, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("room_id = ? AND user_id = ? AND until > ?", roomID, userID, now).
+		Delete(&RoomMuteDTO{})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to delete mute")
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetMute returns when the mute of a user in a room ends, or nil when the user
+// is not muted at the given time.
+func (s *Storage) GetMute(ctx context.Context, roomID, userID uuid.UUID, now time.Time) (*time.Time, error) {
+	var dto RoomMuteDTO
+	err := s.db.WithContext(ctx).
+		Where("room_id = ? AND user_id = ? AND until > ?", roomID, userID, now).
+		Take(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get mute")
+	}
+
+	return &dto.Until, nil
+}
+
+// ClaimScriptRun reports whether this instance runs the scheduled hook of a
+// script now, and if so schedules the next run one interval later. A script
+// seen for the first time is scheduled without running.
+func (s *Storage) ClaimScriptRun(ctx context.Context, scriptID uuid.UUID, interval time.Duration, now time.Time) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Model(&ScriptRunDTO{}).
+		Where("script_id = ? AND next_run_at <= ?", scriptID, now).
+		Update("next_run_at", now.Add(interval))
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to claim script run")
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ScriptRunDTO{ScriptID: scriptID, NextRunAt: now.Add(interval)}).
+		Error
+	if err != nil {
+		return false, errors.Wrap(err, "failed to schedule script run")
+	}
+
+	return false, nil
+}
+
+// GetMessagesPage retrieves a page of top-level messages from a room using keyset
+// pagination on (created_at, id). With an after cursor the page starts right after it,
+// otherwise it ends right before the before cursor, or at the newest message when
+// no cursor is given. Messages are returned in chronological order and the flag
+// reports whether more messages exist beyond the page in the scanned direction.
+// Thread replies are excluded and can be fetched with GetThreadMessages.
+func (s *Storage) GetMessagesPage(ctx context.Context, roomID uuid.UUID, before, after *entities.MessageCursor, limit int) ([]*entities.Message, bool, error) {
+	var dtos []MessageDTO
+
+	query := s.db.WithContext(ctx).
+		Where("room_id = ? AND parent_id IS NULL AND deleted_at IS NULL", roomID)
+
+	forward := after != nil
+	switch {
+	case forward:
+		query = query.
+			Where("(created_at, id) > (?, ?)", after.Timestamp, after.ID).
+			Order("created_at ASC, id ASC")
+	case before != nil:
+		query = query.
+			Where("(created_at, id) < (?, ?)", before.Timestamp, before.ID).
+			Order("created_at DESC, id DESC")
+	default:
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	// Fetch one extra row to know whether another page exists.
+	if err := query.Limit(limit + 1).Find(&dtos).Error; err != nil {
+		return nil, false, errors.Wrap(err, "failed to get messages page")
+	}
+
+	hasMore := len(dtos) > limit
+	if hasMore {
+		dtos = dtos[:limit]
+	}
+
+	messages := make([]*entities.Message, len(dtos))
+	for i := range dtos {
+		if forward {
+			messages[i] = dtoToMessage(&dtos[i])
+		} else {
+			messages[len(dtos)-1-i] = dtoToMessage(&dtos[i])
+		}
+	}
+
+	return messages, hasMore, nil
+}
+
+// GetRoomHistory returns up to limit messages of a room after the cursor, or
+// from the start without one, oldest first. Unlike history pages it includes
+// thread replies; deleted messages are left out.
+func (s *Storage) GetRoomHistory(ctx context.Context, roomID uuid.UUID, after *entities.MessageCursor, limit int) ([]*entities.Message, error) {
+	var dtos []MessageDTO
+
+	query := s.db.WithContext(ctx).
+		Where("room_id = ? AND deleted_at IS NULL", roomID)
+	if after != nil {
+		query = query.Where("(created_at, id) > (?, ?)", after.Timestamp, after.ID)
+	}
+
+	if err := query.
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get room history")
+	}
+
+	messages := make([]*entities.Message, len(dtos))
+	for i := range dtos {
+		messages[i] = dtoToMessage(&dtos[i])
+	}
+
+	return messages, nil
+}
+
+// GetThreadMessages retrieves the replies to a message with pagination.
+func (s *Storage) GetThreadMessages(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]*entities.Message, error) {
+	var dtos []MessageDTO
+
+	err := s.db.WithContext(ctx).
+		Where("parent_id = ? AND deleted_at IS NULL", parentID).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&dtos).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get thread messages")
+	}
+
+	messages := make([]*entities.Message, len(dtos))
+	for i := range dtos {
+		messages[i] = dtoToMessage(&dtos[i])
+	}
+
+	return messages, nil
+}
+
+// CountReplies returns the number of live replies for each of the given messages.
+// Messages without replies are absent from the result.
+func (s *Storage) CountReplies(ctx context.Context, parentIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParentID uuid.UUID
+		Count    int
+	}
+
+	err := s.db.WithContext(ctx).
+		Model(&MessageDTO{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ? AND deleted_at IS NULL", parentIDs).
+		Group("parent_id").
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count replies")
+	}
+
+	for _, row := range rows {
+		counts[row.ParentID] = row.Count
+	}
+
+	return counts, nil
+}
+
+// GetMessage retrieves a single message by its ID.
+func (s *Storage) GetMessage(ctx context.Context, messageID uuid.UUID) (*entities.Message, error) {
+	var dto MessageDTO
+
+	err := s.db.WithContext(ctx).
+		Where("id = ?", messageID).
+		First(&dto).
+		Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrMessageNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get message")
+	}
+
+	return dtoToMessage(&dto), nil
+}
+
+// GetMessages returns the messages with the given IDs that exist, deleted ones
+// included, in no particular order.
+func (s *Storage) GetMessages(ctx context.Context, messageIDs []uuid.UUID) ([]*entities.Message, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	var dtos []MessageDTO
+	if err := s.db.WithContext(ctx).
+		Where("id IN ?", messageIDs).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get messages")
+	}
+
+	messages := make([]*entities.Message, len(dtos))
+	for i := range dtos {
+		messages[i] = dtoToMessage(&dtos[i])
+	}
+
+	return messages, nil
+}
+
+// UpdateMessageContent replaces the content of a message that has not been deleted.
+func (s *Storage) UpdateMessageContent(ctx context.Context, messageID uuid.UUID, content string, editedAt time.Time) error {
+	result := s.db.WithContext(ctx).
+		Model(&MessageDTO{}).
+		Where("id = ? AND deleted_at IS NULL", messageID).
+		Updates(map[string]interface{}{
+			"content":   content,
+			"edited_at": editedAt,
+		})
+
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to update message")
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// SoftDeleteMessage clears the content of a message and marks it deleted. Its
+// attachments are removed and returned, so that their contents can be deleted.
+func (s *Storage) SoftDeleteMessage(ctx context.Context, messageID uuid.UUID, deletedAt time.Time) ([]entities.Attachment, error) {
+	var attachments []AttachmentDTO
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&MessageDTO{}).
+			Where("id = ? AND deleted_at IS NULL", messageID).
+			Updates(map[string]interface{}{
+				"content":    "",
+				"deleted_at": deletedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrMessageNotFound
+		}
+
+		return tx.Clauses(clause.Returning{}).
+			Where("message_id = ?", messageID).
+			Delete(&attachments).Error
+	})
+	if errors.Is(err, entities.ErrMessageNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete message")
+	}
+
+	result := make([]entities.Attachment, len(attachments))
+	for i := range attachments {
+		result[i] = *dtoToAttachment(&attachments[i])
+	}
+	return result, nil
+}
+
+// AddReaction stores a user's reaction to a message. Adding the same reaction twice is a no-op.
+func (s *Storage) AddReaction(ctx context.Context, messageID, userID uuid.UUID, emoji string) error {
+	reaction := &MessageReactionDTO{
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: time.Now(),
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}, {Name: "user_id"}, {Name: "emoji"}},
+			DoNothing: true,
+		}).
+		Create(reaction).
+		Error
+
+	if err != nil {
+		return errors.Wrap(err, "failed to add reaction")
 	}
 
 	return nil
 }
 
-// GetLastMessages retrieves messages from a specific room with pagination.
-func (s *Storage) GetLastMessages(ctx context.Context, roomID uuid.UUID, limit, offset int) ([]*entities.Message, error) {
-	var dtos []MessageDTO
+// RemoveReaction removes a user's reaction from a message.
+func (s *Storage) RemoveReaction(ctx context.Context, messageID, userID uuid.UUID, emoji string) error {
+	err := s.db.WithContext(ctx).
+		Where("message_id = ? AND user_id = ? AND emoji = ?", messageID, userID, emoji).
+		Delete(&MessageReactionDTO{}).
+		Error
+
+	if err != nil {
+		return errors.Wrap(err, "failed to remove reaction")
+	}
+
+	return nil
+}
+
+// GetReactions returns the aggregated reactions of the given messages,
+// with emojis ordered by their first use.
+func (s *Storage) GetReactions(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID][]entities.Reaction, error) {
+	result := make(map[uuid.UUID][]entities.Reaction, len(messageIDs))
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	var dtos []MessageReactionDTO
 
 	err := s.db.WithContext(ctx).
-		Where("room_id = ?", roomID).
+		Where("message_id IN ?", messageIDs).
 		Order("created_at ASC").
-		Limit(limit).
-		Offset(offset).
 		Find(&dtos).
 		Error
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get last messages")
+		return nil, errors.Wrap(err, "failed to get reactions")
 	}
 
-	messages := make([]*entities.Message, len(dtos))
-	for i, dto := range dtos {
-		messages[i] = &entities.Message{
-			ID:        dto.ID,
-			RoomID:    dto.RoomID,
-			UserID:    dto.UserID,
-			Content:   dto.Content,
-			Timestamp: dto.CreatedAt,
+	for _, dto := range dtos {
+		reactions := result[dto.MessageID]
+
+		idx := -1
+		for i := range reactions {
+			if reactions[i].Emoji == dto.Emoji {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			reactions = append(reactions, entities.Reaction{Emoji: dto.Emoji})
+			idx = len(reactions) - 1
 		}
+
+		reactions[idx].Count++
+		reactions[idx].UserIDs = append(reactions[idx].UserIDs, dto.UserID)
+		result[dto.MessageID] = reactions
 	}
 
-	return messages, nil
+	return result, nil
+}
+
+// AdvanceReadCursor moves the read cursor of a user in a room to the given message.
+// The cursor never moves backwards; the returned flag reports whether it advanced.
+func (s *Storage) AdvanceReadCursor(ctx context.Context, roomID, userID, messageID uuid.UUID, readAt time.Time) (bool, error) {
+	cursor := &ReadCursorDTO{
+		RoomID:            roomID,
+		UserID:            userID,
+		LastReadMessageID: messageID,
+		LastReadAt:        readAt,
+		UpdatedAt:         time.Now(),
+	}
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "room_id"}, {Name: "user_id"}},
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "chat_read_cursors.last_read_at < excluded.last_read_at"},
+			}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_read_message_id", "last_read_at", "updated_at"}),
+		}).
+		Create(cursor)
+
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to advance read cursor")
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnread returns, for each of the given rooms, the number of live messages
+// written by other users after the user's read cursor.
+func (s *Storage) CountUnread(ctx context.Context, userID uuid.UUID, roomIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(roomIDs))
+	if len(roomIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		RoomID uuid.UUID
+		Count  int
+	}
+
+	err := s.db.WithContext(ctx).
+		Table("chat_messages AS m").
+		Select("m.room_id, COUNT(*) AS count").
+		Joins("LEFT JOIN chat_read_cursors AS c ON c.room_id = m.room_id AND c.user_id = ?", userID).
+		Where("m.room_id IN ? AND m.user_id <> ? AND m.deleted_at IS NULL", roomIDs, userID).
+		Where("c.last_read_at IS NULL OR m.created_at > c.last_read_at").
+		Group("m.room_id").
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count unread messages")
+	}
+
+	for _, row := range rows {
+		counts[row.RoomID] = row.Count
+	}
+
+	return counts, nil
+}
+
+// Control characters delimit matches in ts_headline output so that the snippet
+// can be HTML-escaped before the highlight markup is inserted.
+const (
+	headlineStartSel = "\x02"
+	headlineStopSel  = "\x03"
+)
+
+var headlineReplacer = strings.NewReplacer(headlineStartSel, "<mark>", headlineStopSel, "</mark>")
+
+// SearchMessages performs a ranked full-text search over live messages of the given rooms.
+// Snippets are HTML-escaped with matches wrapped in <mark> tags.
+func (s *Storage) SearchMessages(ctx context.Context, roomIDs []uuid.UUID, filter entities.SearchFilter) ([]entities.SearchResult, error) {
+	if len(roomIDs) == 0 {
+		return []entities.SearchResult{}, nil
+	}
+
+	var rows []struct {
+		MessageDTO
+		Snippet string
+		Rank    float64
+	}
+
+	headlineOptions := "StartSel=" + headlineStartSel + ",StopSel=" + headlineStopSel + ",MaxFragments=2,MaxWords=20,MinWords=5"
+
+	query := s.db.WithContext(ctx).
+		Table("chat_messages, websearch_to_tsquery('simple', ?) AS q", filter.Text).
+		Select("chat_messages.*, "+
+			"ts_headline('simple', chat_messages.content, q, ?) AS snippet, "+
+			"ts_rank(chat_messages.search_vector, q) AS rank", headlineOptions).
+		Where("chat_messages.search_vector @@ q").
+		Where("chat_messages.room_id IN ? AND chat_messages.deleted_at IS NULL", roomIDs)
+
+	if filter.AuthorID != nil {
+		query = query.Where("chat_messages.user_id = ?", *filter.AuthorID)
+	}
+	if filter.From != nil {
+		query = query.Where("chat_messages.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("chat_messages.created_at <= ?", *filter.To)
+	}
+
+	err := query.
+		Order("rank DESC, chat_messages.created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search messages")
+	}
+
+	results := make([]entities.SearchResult, len(rows))
+	for i := range rows {
+		results[i] = entities.SearchResult{
+			Message: dtoToMessage(&rows[i].MessageDTO),
+			Snippet: headlineReplacer.Replace(html.EscapeString(rows[i].Snippet)),
+			Rank:    rows[i].Rank,
+		}
+	}
+
+	return results, nil
 }
 
-// AddParticipant adds a new participant to a room.
-func (s *Storage) AddParticipant(ctx context.Context, roomID, userID uuid.UUID) error {
+// AddParticipant adds a new participant to a room. It reports whether the user
+// was added, that is, whether they were not a participant already.
+func (s *Storage) AddParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error) {
 	isParticipant, err := s.IsParticipant(ctx, roomID, userID)
 	if err != nil {
-		return errors.Wrap(err, "failed to check if participant exists")
+		return false, errors.Wrap(err, "failed to check if participant exists")
 	}
 	if isParticipant {
-		return nil
+		return false, nil
 	}
 
 	participant := &RoomParticipantDTO{
@@ -85,24 +883,23 @@ func (s *Storage) AddParticipant(ctx context.Context, roomID, userID uuid.UUID)
 		CreatedAt: time.Now(),
 	}
 
-	err = s.db.WithContext(ctx).
+	result := s.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "room_id"}, {Name: "user_id"}},
 			DoNothing: true,
 		}).
-		Create(participant).
-		Error
+		Create(participant)
 
-	if err != nil {
+	if err := result.Error; err != nil {
 		if pgErr, ok := err.(*pq.Error); ok {
 			if pgErr.Code == "23505" {
-				return nil
+				return false, nil
 			}
 		}
-		return errors.Wrap(err, "failed to add participant")
+		return false, errors.Wrap(err, "failed to add participant")
 	}
 
-	return nil
+	return result.RowsAffected > 0, nil
 }
 
 // RemoveParticipant removes a participant from a room.
@@ -190,3 +987,498 @@ func (s *Storage) CleanupParticipants(ctx context.Context, roomID uuid.UUID) err
 
 	return nil
 }
+
+// SavePresence records the status of a user on a chat instance. Going offline
+// removes the instance's session of the user and updates the user's last seen time.
+func (s *Storage) SavePresence(ctx context.Context, userID uuid.UUID, instanceID string, status entities.PresenceStatus, at time.Time) error {
+	if status == entities.PresenceOffline {
+		return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.
+				Where("user_id = ? AND instance_id = ?", userID, instanceID).
+				Delete(&PresenceSessionDTO{}).
+				Error; err != nil {
+				return errors.Wrap(err, "failed to delete presence session")
+			}
+
+			return saveLastSeen(tx, userID, at)
+		})
+	}
+
+	session := &PresenceSessionDTO{
+		UserID:     userID,
+		InstanceID: instanceID,
+		Status:     string(status),
+		UpdatedAt:  at,
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "instance_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
+		}).
+		Create(session).
+		Error
+
+	if err != nil {
+		return errors.Wrap(err, "failed to save presence session")
+	}
+
+	return nil
+}
+
+// TouchPresence refreshes every presence session of a chat instance.
+func (s *Storage) TouchPresence(ctx context.Context, instanceID string, at time.Time) error {
+	err := s.db.WithContext(ctx).
+		Model(&PresenceSessionDTO{}).
+		Where("instance_id = ?", instanceID).
+		Update("updated_at", at).
+		Error
+
+	if err != nil {
+		return errors.Wrap(err, "failed to refresh presence sessions")
+	}
+
+	return nil
+}
+
+// ExpirePresence removes the presence sessions that were not refreshed since
+// staleBefore, typically left behind by a crashed instance, and returns their users.
+func (s *Storage) ExpirePresence(ctx context.Context, staleBefore time.Time) ([]uuid.UUID, error) {
+	return s.expirePresenceSessions(ctx, "updated_at < ?", staleBefore)
+}
+
+// ClearInstancePresence removes every presence session of a chat instance,
+// marking its users as last seen at the given time.
+func (s *Storage) ClearInstancePresence(ctx context.Context, instanceID string, at time.Time) error {
+	if err := s.TouchPresence(ctx, instanceID, at); err != nil {
+		return err
+	}
+
+	_, err := s.expirePresenceSessions(ctx, "instance_id = ?", instanceID)
+	return err
+}
+
+// GetPresence aggregates the presence of the given users over every chat instance.
+// A user is online when any live session is online, away when all live sessions are away,
+// and offline otherwise. Results are returned in the order of userIDs.
+func (s *Storage) GetPresence(ctx context.Context, userIDs []uuid.UUID, staleBefore time.Time) ([]entities.Presence, error) {
+	result := make([]entities.Presence, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var sessions []PresenceSessionDTO
+	if err := s.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Find(&sessions).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get presence sessions")
+	}
+
+	var lastSeen []LastSeenDTO
+	if err := s.db.WithContext(ctx).
+		Where("user_id IN ?", userIDs).
+		Find(&lastSeen).
+		Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get last seen times")
+	}
+
+	statuses := make(map[uuid.UUID]entities.PresenceStatus, len(sessions))
+	seenAt := make(map[uuid.UUID]time.Time, len(lastSeen)+len(sessions))
+
+	for _, dto := range lastSeen {
+		seenAt[dto.UserID] = dto.LastSeenAt
+	}
+
+	for _, dto := range sessions {
+		// Sessions that are due to expire only contribute to the last seen time.
+		if dto.UpdatedAt.Before(staleBefore) {
+			if dto.UpdatedAt.After(seenAt[dto.UserID]) {
+				seenAt[dto.UserID] = dto.UpdatedAt
+			}
+			continue
+		}
+
+		if statuses[dto.UserID] != entities.PresenceOnline {
+			statuses[dto.UserID] = entities.PresenceStatus(dto.Status)
+		}
+	}
+
+	for i, userID := range userIDs {
+		presence := entities.Presence{
+			UserID: userID,
+			Status: entities.PresenceOffline,
+		}
+
+		if status, ok := statuses[userID]; ok {
+			presence.Status = status
+		} else if at, ok := seenAt[userID]; ok {
+			presence.LastSeen = &at
+		}
+
+		result[i] = presence
+	}
+
+	return result, nil
+}
+
+// expirePresenceSessions deletes the matching presence sessions in a single statement,
+// moving their refresh time into the last seen time of their users.
+func (s *Storage) expirePresenceSessions(ctx context.Context, condition string, args ...interface{}) ([]uuid.UUID, error) {
+	var rows []struct {
+		UserID uuid.UUID
+	}
+
+	err := s.db.WithContext(ctx).
+		Raw(`WITH expired AS (
+			DELETE FROM chat_presence_sessions WHERE `+condition+` RETURNING user_id, updated_at
+		)
+		INSERT INTO chat_user_last_seen (user_id, last_seen_at)
+		SELECT user_id, MAX(updated_at) FROM expired GROUP BY user_id
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_seen_at = GREATEST(chat_user_last_seen.last_seen_at, excluded.last_seen_at)
+		RETURNING user_id`, args...).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to expire presence sessions")
+	}
+
+	userIDs := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		userIDs[i] = row.UserID
+	}
+
+	return userIDs, nil
+}
+
+func saveLastSeen(tx *gorm.DB, userID uuid.UUID, at time.Time) error {
+	err := tx.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "chat_user_last_seen.last_seen_at < excluded.last_seen_at"},
+			}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_seen_at"}),
+		}).
+		Create(&LastSeenDTO{UserID: userID, LastSeenAt: at}).
+		Error
+
+	if err != nil {
+		return errors.Wrap(err, "failed to save last seen time")
+	}
+
+	return nil
+}
+
+// SaveAttachment stores the metadata of an uploaded attachment.
+func (s *Storage) SaveAttachment(ctx context.Context, attachment *entities.Attachment) error {
+	dto := &AttachmentDTO{
+		ID:           attachment.ID,
+		RoomID:       attachment.RoomID,
+		UploaderID:   attachment.UploaderID,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+		HasThumbnail: attachment.HasThumbnail,
+		CreatedAt:    attachment.CreatedAt,
+	}
+
+	if err := s.db.WithContext(ctx).Create(dto).Error; err != nil {
+		return errors.Wrap(err, "failed to save attachment")
+	}
+	return nil
+}
+
+// GetAttachment returns an attachment by ID. Attachments of deleted messages
+// are reported as not found.
+func (s *Storage) GetAttachment(ctx context.Context, attachmentID uuid.UUID) (*entities.Attachment, error) {
+	var dto AttachmentDTO
+	err := s.db.WithContext(ctx).
+		Where("id = ?", attachmentID).
+		Where("NOT EXISTS (SELECT 1 FROM chat_messages m WHERE m.id = chat_attachments.message_id AND m.deleted_at IS NOT NULL)").
+		First(&dto).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrAttachmentNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get attachment")
+	}
+
+	return dtoToAttachment(&dto), nil
+}
+
+// GetAttachments returns the attachments with the given IDs that exist, in no
+// particular order.
+func (s *Storage) GetAttachments(ctx context.Context, attachmentIDs []uuid.UUID) ([]*entities.Attachment, error) {
+	if len(attachmentIDs) == 0 {
+		return nil, nil
+	}
+
+	var dtos []AttachmentDTO
+	if err := s.db.WithContext(ctx).
+		Where("id IN ?", attachmentIDs).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get attachments")
+	}
+
+	attachments := make([]*entities.Attachment, len(dtos))
+	for i := range dtos {
+		attachments[i] = dtoToAttachment(&dtos[i])
+	}
+	return attachments, nil
+}
+
+// DeleteOrphanedAttachments removes up to limit of the oldest attachments that
+// no message references and that were uploaded before the given time, and
+// returns them so that their contents can be deleted. Rows locked by another
+// instance, e.g. while a message is being linked to them, are skipped.
+func (s *Storage) DeleteOrphanedAttachments(ctx context.Context, before time.Time, limit int) ([]entities.Attachment, error) {
+	var attachments []AttachmentDTO
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Model(&AttachmentDTO{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("message_id IS NULL AND created_at < ?", before).
+			Order("created_at ASC").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.Returning{}).
+			Where("id IN ? AND message_id IS NULL", ids).
+			Delete(&attachments).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to delete orphaned attachments")
+	}
+
+	result := make([]entities.Attachment, len(attachments))
+	for i := range attachments {
+		result[i] = *dtoToAttachment(&attachments[i])
+	}
+	return result, nil
+}
+
+// GetMessageAttachments returns the attachments of messages, keyed by message
+// ID, in upload order.
+func (s *Storage) GetMessageAttachments(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID][]entities.Attachment, error) {
+	result := make(map[uuid.UUID][]entities.Attachment)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	var dtos []AttachmentDTO
+	if err := s.db.WithContext(ctx).
+		Where("message_id IN ?", messageIDs).
+		Order("created_at ASC, id ASC").
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get message attachments")
+	}
+
+	for i := range dtos {
+		messageID := *dtos[i].MessageID
+		result[messageID] = append(result[messageID], *dtoToAttachment(&dtos[i]))
+	}
+	return result, nil
+}
+
+// GetRetentionCursor returns the cursor of the oldest of the keep newest
+// messages of a room, or nil when the room has no more than keep messages.
+// Thread replies and deleted messages count too.
+func (s *Storage) GetRetentionCursor(ctx context.Context, roomID uuid.UUID, keep int) (*entities.MessageCursor, error) {
+	var dto MessageDTO
+	err := s.db.WithContext(ctx).
+		Select("id", "created_at").
+		Where("room_id = ?", roomID).
+		Order("created_at DESC, id DESC").
+		Offset(keep - 1).
+		Take(&dto).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get retention cursor")
+	}
+
+	return &entities.MessageCursor{Timestamp: dto.CreatedAt, ID: dto.ID}, nil
+}
+
+// PurgeMessages removes up to limit of the oldest messages of a room that are
+// before the cursor, with their reactions. Archived messages are copied to the
+// archive table and keep their attachments; otherwise the attachments are
+// removed too and returned, so that their contents can be deleted. Rows locked
+// by another instance purging the same room are skipped.
+func (s *Storage) PurgeMessages(ctx context.Context, roomID uuid.UUID, before entities.MessageCursor, limit int, archive bool) (*entities.PurgeResult, error) {
+	result := &entities.PurgeResult{}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var messageIDs []uuid.UUID
+		if err := tx.Model(&MessageDTO{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("room_id = ? AND (created_at, id) < (?, ?)", roomID, before.Timestamp, before.ID).
+			Order("created_at ASC, id ASC").
+			Limit(limit).
+			Pluck("id", &messageIDs).Error; err != nil {
+			return err
+		}
+		if len(messageIDs) == 0 {
+			return nil
+		}
+
+		if archive {
+			if err := tx.Exec(`INSERT INTO chat_messages_archive
+				(id, room_id, user_id, client_msg_id, parent_id, kind, author_name, content, created_at, edited_at, deleted_at, archived_at)
+				SELECT id, room_id, user_id, client_msg_id, parent_id, kind, author_name, content, created_at, edited_at, deleted_at, ?
+				FROM chat_messages WHERE id IN ?
+				ON CONFLICT (id) DO NOTHING`, time.Now(), messageIDs).Error; err != nil {
+				return err
+			}
+		} else {
+			var attachments []AttachmentDTO
+			if err := tx.Clauses(clause.Returning{}).
+				Where("message_id IN ?", messageIDs).
+				Delete(&attachments).Error; err != nil {
+				return err
+			}
+			for i := range attachments {
+				result.Attachments = append(result.Attachments, *dtoToAttachment(&attachments[i]))
+			}
+		}
+
+		if err := tx.Where("message_id IN ?", messageIDs).
+			Delete(&MessageReactionDTO{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("id IN ?", messageIDs).
+			Delete(&MessageDTO{}).Error; err != nil {
+			return err
+		}
+
+		result.Messages = len(messageIDs)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to purge messages")
+	}
+
+	return result, nil
+}
+
+// PurgeRoomEvents removes up to limit of the oldest logged events of a room
+// created before the given time and returns how many were removed. Clients
+// resuming from a purged event get a reset instead of a replay.
+func (s *Storage) PurgeRoomEvents(ctx context.Context, roomID uuid.UUID, before time.Time, limit int) (int, error) {
+	result := s.db.WithContext(ctx).Exec(`DELETE FROM chat_room_events
+		WHERE room_id = ? AND seq IN (
+			SELECT seq FROM chat_room_events
+			WHERE room_id = ? AND created_at < ?
+			ORDER BY seq ASC
+			LIMIT ?
+		)`, roomID, roomID, before, limit)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "failed to purge room events")
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// QueueWebhookEvent stores a webhook event in the outbox, due at once. An event
+// already queued is kept as is.
+func (s *Storage) QueueWebhookEvent(ctx context.Context, event *entities.WebhookEvent) error {
+	dto := WebhookOutboxDTO{
+		ID:            event.ID,
+		RoomID:        event.RoomID,
+		Type:          event.Type,
+		Data:          event.Data,
+		OccurredAt:    event.OccurredAt,
+		NextAttemptAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dto).Error; err != nil {
+		return errors.Wrap(err, "failed to queue webhook event")
+	}
+	return nil
+}
+
+// ClaimWebhookEvents claims up to limit outbox events due at now for
+// publication by the caller and counts their attempt. A claimed event is not
+// due again until leaseUntil, so an event whose claimer died is retried then.
+// Events claimed by another instance are skipped.
+func (s *Storage) ClaimWebhookEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.QueuedWebhookEvent, error) {
+	var dtos []WebhookOutboxDTO
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Model(&WebhookOutboxDTO{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tx.Model(&dtos).
+			Clauses(clause.Returning{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": leaseUntil,
+			}).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim webhook events")
+	}
+
+	events := make([]*entities.QueuedWebhookEvent, len(dtos))
+	for i, dto := range dtos {
+		events[i] = &entities.QueuedWebhookEvent{
+			WebhookEvent: entities.WebhookEvent{
+				ID:         dto.ID,
+				RoomID:     dto.RoomID,
+				Type:       dto.Type,
+				Data:       dto.Data,
+				OccurredAt: dto.OccurredAt,
+			},
+			Attempts: dto.Attempts,
+			QueuedAt: dto.CreatedAt,
+		}
+	}
+	return events, nil
+}
+
+// RetryWebhookEvent makes an outbox event due again at the given time.
+func (s *Storage) RetryWebhookEvent(ctx context.Context, eventID uuid.UUID, nextAttemptAt time.Time) error {
+	if err := s.db.WithContext(ctx).
+		Model(&WebhookOutboxDTO{}).
+		Where("id = ?", eventID).
+		Update("next_attempt_at", nextAttemptAt).Error; err != nil {
+		return errors.Wrap(err, "failed to reschedule webhook event")
+	}
+	return nil
+}
+
+// DeleteWebhookEvent removes an event from the outbox.
+func (s *Storage) DeleteWebhookEvent(ctx context.Context, eventID uuid.UUID) error {
+	if err := s.db.WithContext(ctx).
+		Delete(&WebhookOutboxDTO{}, "id = ?", eventID).Error; err != nil {
+		return errors.Wrap(err, "failed to delete webhook event")
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// typingTimeout is how long a user stays "typing" without a new typing_started frame.
+	typingTimeout = 6 * time.Second
+	// typingThrottle is the minimum interval between two typing broadcasts of the same user in a room.
+	typingThrottle = 2 * time.Second
+)
+
+type typingKey struct {
+	roomID uuid.UUID
+	userID uuid.UUID
+}
+
+// typingState is kept in memory only. Inactive states are forgotten after
+// typingThrottle.
+type typingState struct {
+	active        bool // Whether the user is typing.
+	shown         bool // What the other participants were last told.
+	lastBroadcast time.Time
+	timer         *time.Timer // Expires an active state and forgets an inactive one.
+	flush         *time.Timer // Broadcasts a change held back by the throttle.
+}
+
+// StartTyping marks the user as typing in the room and notifies the other participants.
+// Repeated calls refresh the expiry. Broadcasts of a user in a room, starts and
+// stops alike, are at least typingThrottle apart: a change within the throttle
+// is broadcast when it ends, unless it was undone meanwhile.
+func (s *Service) StartTyping(roomID, userID uuid.UUID) error {
+	room := s.getRoom(roomID)
+	if room == nil {
+		return entities.ErrRoomNotFound
+	}
+	if !room.CheckConnection(userID) {
+		return entities.ErrForbidden
+	}
+
+	key := typingKey{roomID: roomID, userID: userID}
+
+	s.typingMu.Lock()
+	state, exists := s.typing[key]
+	if !exists {
+		state = &typingState{}
+		state.timer = time.AfterFunc(typingTimeout, func() { s.onTypingTimer(key) })
+		s.typing[key] = state
+	} else {
+		state.timer.Reset(typingTimeout)
+	}
+
+	broadcast := s.changeTyping(key, state, true)
+	s.typingMu.Unlock()
+
+	if broadcast {
+		s.broadcastTyping(roomID, userID, true)
+	}
+	return nil
+}
+
+// StopTyping clears the typing state of the user and notifies the other participants.
+func (s *Service) StopTyping(roomID, userID uuid.UUID) error {
+	if s.getRoom(roomID) == nil {
+		return entities.ErrRoomNotFound
+	}
+
+	key := typingKey{roomID: roomID, userID: userID}
+
+	s.typingMu.Lock()
+	state, exists := s.typing[key]
+	if !exists || !state.active {
+		s.typingMu.Unlock()
+		return nil
+	}
+
+	broadcast := s.changeTyping(key, state, false)
+	state.timer.Reset(typingThrottle)
+	s.typingMu.Unlock()
+
+	if broadcast {
+		s.broadcastTyping(roomID, userID, false)
+	}
+	return nil
+}
+
+// changeTyping records whether the user is typing and reports whether to
+// broadcast it now. While the user keeps typing, the start is broadcast again
+// once per typingThrottle. A change within the throttle of the last broadcast
+// is left to flushTyping. The caller holds typingMu.
+func (s *Service) changeTyping(key typingKey, state *typingState, active bool) bool {
+	refresh := active && state.active
+	state.active = active
+
+	if state.flush != nil {
+		// The pending flush broadcasts whatever the state is by then.
+		return false
+	}
+	if state.shown == active && !refresh {
+		return false
+	}
+
+	now := time.Now()
+	if wait := typingThrottle - now.Sub(state.lastBroadcast); wait > 0 {
+		if state.shown != active {
+			state.flush = time.AfterFunc(wait, func() { s.flushTyping(key) })
+		}
+		return false
+	}
+
+	state.shown = active
+	state.lastBroadcast = now
+	return true
+}
+
+// flushTyping broadcasts a change held back by the throttle, if it still holds.
+func (s *Service) flushTyping(key typingKey) {
+	s.typingMu.Lock()
+	state, exists := s.typing[key]
+	if !exists {
+		s.typingMu.Unlock()
+		return
+	}
+
+	state.flush = nil
+	if state.active == state.shown {
+		s.typingMu.Unlock()
+		return
+	}
+
+	state.shown = state.active
+	state.lastBroadcast = time.Now()
+	if !state.active {
+		// Kept for a full throttle after this broadcast.
+		state.timer.Reset(typingThrottle)
+	}
+	typing := state.active
+	s.typingMu.Unlock()
+
+	s.broadcastTyping(key.roomID, key.userID, typing)
+}
+
+// clearTyping drops the typing state of a user leaving the room without broadcasting.
+func (s *Service) clearTyping(roomID, userID uuid.UUID) {
+	key := typingKey{roomID: roomID, userID: userID}
+
+	s.typingMu.Lock()
+	defer s.typingMu.Unlock()
+
+	if state, exists := s.typing[key]; exists {
+		state.timer.Stop()
+		if state.flush != nil {
+			state.flush.Stop()
+		}
+		delete(s.typing, key)
+	}
+}
+
+// onTypingTimer expires an active typing state or forgets an inactive one.
+func (s *Service) onTypingTimer(key typingKey) {
+	s.typingMu.Lock()
+	state, exists := s.typing[key]
+	if !exists {
+		s.typingMu.Unlock()
+		return
+	}
+
+	if !state.active {
+		if state.flush != nil {
+			state.timer.Reset(typingThrottle)
+		} else {
+			delete(s.typing, key)
+		}
+		s.typingMu.Unlock()
+		return
+	}
+
+	broadcast := s.changeTyping(key, state, false)
+	state.timer.Reset(typingThrottle)
+	s.typingMu.Unlock()
+
+	s.logger.Debug("Typing indicator expired",
+		zap.String("room_id", key.roomID.String()),
+		zap.String("user_id", key.userID.String()),
+	)
+	if broadcast {
+		s.broadcastTyping(key.roomID, key.userID, false)
+	}
+}
+
+func (s *Service) broadcastTyping(roomID, userID uuid.UUID, typing bool) {
+	payload, err := json.Marshal(entities.TypingPayload{Typing: typing})
+	if err != nil {
+		s.logger.Error("Failed to marshal typing payload", zap.Error(err))
+		return
+	}
+
+	s.broadcast(context.Background(), &entities.Event{
+		Type:      entities.EventUserTyping,
+		RoomID:    roomID,
+		UserID:    userID,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	}, &userID)
+}
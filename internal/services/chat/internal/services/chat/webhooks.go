@@ -0,0 +1,198 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// webhookPublishTimeout bounds the queueing and the publication of an event.
+	webhookPublishTimeout = 5 * time.Second
+	// webhookRelayInterval is how often the outbox is checked for events due
+	// for a retry. New events are published as soon as they are queued.
+	webhookRelayInterval = 5 * time.Second
+	// webhookRelayBatch bounds the events claimed from the outbox at once.
+	webhookRelayBatch = 100
+	// webhookLease is how long a claimed event is not claimed again, so that
+	// the events of an instance that died while publishing them are retried.
+	webhookLease = time.Minute
+	// webhookRetryDelay and webhookMaxRetryDelay bound the exponential backoff
+	// between attempts to publish an event.
+	webhookRetryDelay    = 5 * time.Second
+	webhookMaxRetryDelay = 10 * time.Minute
+	// webhookEventTTL is how long an event is retried before it is dropped.
+	webhookEventTTL = 24 * time.Hour
+	// unsubscribedRoomTTL is how long a room without webhooks is not published
+	// to. A webhook added to such a room receives events after at most this long.
+	unsubscribedRoomTTL = time.Minute
+)
+
+// publishMessageCreated publishes a new message for the webhooks of its room.
+func (s *Service) publishMessageCreated(msg *entities.Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		s.logger.Error("Failed to marshal webhook message", zap.Error(err))
+		return
+	}
+
+	s.publishWebhookEvent(&entities.WebhookEvent{
+		ID:         msg.ID,
+		RoomID:     msg.RoomID,
+		Type:       entities.WebhookEventMessageCreated,
+		Data:       data,
+		OccurredAt: msg.Timestamp,
+	})
+}
+
+// publishUserJoined publishes a user joining a room for the webhooks of the room.
+func (s *Service) publishUserJoined(roomID, userID uuid.UUID) {
+	data, err := json.Marshal(entities.UserJoinedData{UserID: userID})
+	if err != nil {
+		s.logger.Error("Failed to marshal webhook join", zap.Error(err))
+		return
+	}
+
+	s.publishWebhookEvent(&entities.WebhookEvent{
+		ID:         uuid.New(),
+		RoomID:     roomID,
+		Type:       entities.WebhookEventUserJoined,
+		Data:       data,
+		OccurredAt: time.Now(),
+	})
+}
+
+// publishWebhookEvent stores an event in the outbox, from which it is
+// published in the background until the Website Service accepts it.
+func (s *Service) publishWebhookEvent(event *entities.WebhookEvent) {
+	if s.roomUnsubscribed(event.RoomID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookPublishTimeout)
+	defer cancel()
+
+	if err := s.storage.QueueWebhookEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to queue webhook event",
+			zap.Error(err),
+			zap.String("room_id", event.RoomID.String()),
+			zap.String("event", event.Type),
+		)
+		return
+	}
+
+	select {
+	case s.webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) startWebhookRelay() {
+	s.webhookTick = time.NewTicker(webhookRelayInterval)
+	go func() {
+		for {
+			select {
+			case <-s.webhookTick.C:
+			case <-s.webhookWake:
+			}
+			s.relayWebhookEvents()
+		}
+	}()
+}
+
+// relayWebhookEvents publishes the outbox events that are due, batch by batch.
+func (s *Service) relayWebhookEvents() {
+	for {
+		now := time.Now()
+		events, err := s.storage.ClaimWebhookEvents(context.Background(), now, now.Add(webhookLease), webhookRelayBatch)
+		if err != nil {
+			s.logger.Error("Failed to claim webhook events", zap.Error(err))
+			return
+		}
+
+		for _, event := range events {
+			s.publishToWebsite(event)
+		}
+		if len(events) < webhookRelayBatch {
+			return
+		}
+	}
+}
+
+// publishToWebsite publishes an outbox event and removes it from the outbox
+// once the Website Service accepted it. Otherwise the event is retried with an
+// exponential backoff for up to webhookEventTTL.
+func (s *Service) publishToWebsite(event *entities.QueuedWebhookEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookPublishTimeout)
+	defer cancel()
+
+	subscribed, err := s.website.PublishRoomEvent(ctx, &event.WebhookEvent)
+	if err != nil {
+		if time.Since(event.QueuedAt) < webhookEventTTL {
+			s.logger.Warn("Failed to publish webhook event, retrying",
+				zap.Error(err),
+				zap.String("room_id", event.RoomID.String()),
+				zap.String("event", event.Type),
+				zap.Int("attempts", event.Attempts),
+			)
+			s.retryWebhookEvent(ctx, event)
+			return
+		}
+
+		s.logger.Error("Failed to publish webhook event, dropping it",
+			zap.Error(err),
+			zap.String("room_id", event.RoomID.String()),
+			zap.String("event", event.Type),
+			zap.Int("attempts", event.Attempts),
+		)
+	}
+
+	if err == nil && !subscribed {
+		s.webhookMu.Lock()
+		s.unsubscribedRooms[event.RoomID] = time.Now().Add(unsubscribedRoomTTL)
+		s.webhookMu.Unlock()
+	}
+
+	if err := s.storage.DeleteWebhookEvent(ctx, event.ID); err != nil {
+		s.logger.Error("Failed to delete webhook event", zap.Error(err))
+	}
+}
+
+// retryWebhookEvent schedules the next attempt to publish an outbox event. If
+// this fails, the event is retried when its lease expires.
+func (s *Service) retryWebhookEvent(ctx context.Context, event *entities.QueuedWebhookEvent) {
+	delay := webhookMaxRetryDelay
+	if event.Attempts <= 10 {
+		delay = min(webhookRetryDelay<<(event.Attempts-1), webhookMaxRetryDelay)
+	}
+
+	if err := s.storage.RetryWebhookEvent(ctx, event.ID, time.Now().Add(delay)); err != nil {
+		s.logger.Error("Failed to reschedule webhook event", zap.Error(err))
+	}
+}
+
+// roomUnsubscribed reports whether the room recently had no enabled webhooks.
+func (s *Service) roomUnsubscribed(roomID uuid.UUID) bool {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+
+	until, ok := s.unsubscribedRooms[roomID]
+	return ok && time.Now().Before(until)
+}
+
+// pruneUnsubscribedRooms forgets the expired entries of unsubscribedRooms.
+func (s *Service) pruneUnsubscribedRooms() {
+	s.webhookMu.Lock()
+	defer s.webhookMu.Unlock()
+
+	now := time.Now()
+	for roomID, until := range s.unsubscribedRooms {
+		if !now.Before(until) {
+			delete(s.unsubscribedRooms, roomID)
+		}
+	}
+}
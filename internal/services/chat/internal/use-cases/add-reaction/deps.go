@@ -0,0 +1,17 @@
+package addreaction
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	AddReaction(ctx context.Context, roomID, userID, messageID uuid.UUID, emoji string) error
+}
+
+// Deps holds the dependencies for the add reaction use case.
+type Deps struct {
+	ChatService ChatService
+}
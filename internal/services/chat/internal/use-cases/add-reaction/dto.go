@@ -0,0 +1,11 @@
+package addreaction
+
+import "github.com/google/uuid"
+
+// ReactionInput represents the input data for the add reaction use case.
+type ReactionInput struct {
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	MessageID uuid.UUID
+	Emoji     string
+}
@@ -0,0 +1,40 @@
+package addreaction
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// maxEmojiLength limits the number of runes in a reaction.
+// Composite emojis (flags, skin tones, ZWJ sequences) span several runes.
+const maxEmojiLength = 16
+
+// UseCase implements the add reaction use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the add reaction use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute adds an emoji reaction to a message.
+func (uc *UseCase) Execute(ctx context.Context, input ReactionInput) error {
+	input.Emoji = strings.TrimSpace(input.Emoji)
+	if input.Emoji == "" || utf8.RuneCountInString(input.Emoji) > maxEmojiLength {
+		return entities.ErrInvalidReaction
+	}
+
+	if err := uc.chatService.AddReaction(ctx, input.RoomID, input.UserID, input.MessageID, input.Emoji); err != nil {
+		return errors.Wrap(err, "failed to add reaction")
+	}
+
+	return nil
+}
@@ -10,11 +10,12 @@ import (
 // WebsiteService defines the interface for room validation.
 type WebsiteService interface {
 	RoomExists(ctx context.Context, roomID uuid.UUID) (bool, error)
+	CanJoinRoom(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
 }
 
 // ChatService defines the interface for chat operations.
 type ChatService interface {
-	Connect(ctx context.Context, roomID, userID uuid.UUID, conn entities.Connection) error
+	Connect(ctx context.Context, roomID, userID uuid.UUID, conn entities.Connection, sinceSeq *int64) error
 }
 
 // Deps holds the dependencies for the connect use case.
@@ -11,4 +11,6 @@ type ConnectInput struct {
 	UserID     uuid.UUID
 	Connection entities.Connection
 	Event      *entities.Event
+	// SinceSeq is the last sequence number seen by a resuming client, nil for a fresh connection.
+	SinceSeq *int64
 }
@@ -3,6 +3,7 @@ package connect
 import (
 	"context"
 
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/pkg/errors"
 )
 
@@ -22,8 +23,17 @@ func New(deps Deps) *UseCase {
 
 // Execute performs the connection of a user to a chat room.
 func (uc *UseCase) Execute(ctx context.Context, input ConnectInput) error {
+	// Direct and group rooms only admit their members.
+	allowed, err := uc.websiteService.CanJoinRoom(ctx, input.RoomID, input.UserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to check room access")
+	}
+	if !allowed {
+		return entities.ErrForbidden
+	}
+
 	// Connect to chat room
-	if err := uc.chatService.Connect(ctx, input.RoomID, input.UserID, input.Connection); err != nil {
+	if err := uc.chatService.Connect(ctx, input.RoomID, input.UserID, input.Connection, input.SinceSeq); err != nil {
 		return errors.Wrap(err, "failed to connect to chat room")
 	}
 
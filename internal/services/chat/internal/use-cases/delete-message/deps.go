@@ -0,0 +1,17 @@
+package deletemessage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	DeleteMessage(ctx context.Context, roomID, userID, messageID uuid.UUID) error
+}
+
+// Deps holds the dependencies for the delete message use case.
+type Deps struct {
+	ChatService ChatService
+}
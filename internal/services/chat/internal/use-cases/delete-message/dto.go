@@ -0,0 +1,10 @@
+package deletemessage
+
+import "github.com/google/uuid"
+
+// DeleteInput represents the input data for deleting a message.
+type DeleteInput struct {
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	MessageID uuid.UUID
+}
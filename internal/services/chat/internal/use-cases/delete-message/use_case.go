@@ -0,0 +1,28 @@
+package deletemessage
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the delete message use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the delete message use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute soft-deletes an existing message.
+func (uc *UseCase) Execute(ctx context.Context, input DeleteInput) error {
+	if err := uc.chatService.DeleteMessage(ctx, input.RoomID, input.UserID, input.MessageID); err != nil {
+		return errors.Wrap(err, "failed to delete message")
+	}
+
+	return nil
+}
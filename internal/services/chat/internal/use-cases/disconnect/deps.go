@@ -8,7 +8,7 @@ import (
 
 // ChatService defines the interface for chat operations.
 type ChatService interface {
-	Disconnect(ctx context.Context, roomID, userID uuid.UUID) error
+	Disconnect(ctx context.Context, roomID, userID, connectionID uuid.UUID) error
 }
 
 // Deps holds the dependencies for the disconnect use case.
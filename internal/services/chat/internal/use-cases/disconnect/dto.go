@@ -6,7 +6,8 @@ import (
 )
 
 type DisconnectInput struct {
-	RoomID uuid.UUID
-	UserID uuid.UUID
-	Event  *entities.Event
+	RoomID       uuid.UUID
+	UserID       uuid.UUID
+	ConnectionID uuid.UUID
+	Event        *entities.Event
 }
@@ -20,7 +20,7 @@ func New(deps Deps) *UseCase {
 
 // Execute performs the disconnection of a user from a chat room.
 func (uc *UseCase) Execute(ctx context.Context, input DisconnectInput) error {
-	if err := uc.chatService.Disconnect(ctx, input.RoomID, input.UserID); err != nil {
+	if err := uc.chatService.Disconnect(ctx, input.RoomID, input.UserID, input.ConnectionID); err != nil {
 		return errors.Wrap(err, "failed to disconnect from chat room")
 	}
 	return nil
@@ -0,0 +1,19 @@
+package downloadattachment
+
+import (
+	"context"
+	"io"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Attachments serves stored files.
+type Attachments interface {
+	Open(ctx context.Context, attachmentID uuid.UUID, thumbnail bool, expires int64, signature string) (*entities.Attachment, io.ReadCloser, error)
+}
+
+// Deps holds the dependencies for the download attachment use case.
+type Deps struct {
+	Attachments Attachments
+}
@@ -0,0 +1,11 @@
+package downloadattachment
+
+import "github.com/google/uuid"
+
+// DownloadInput represents a request of a signed download URL.
+type DownloadInput struct {
+	AttachmentID uuid.UUID
+	Thumbnail    bool
+	Expires      int64 // Unix time the URL expires at.
+	Signature    string
+}
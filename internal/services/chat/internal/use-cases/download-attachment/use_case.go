@@ -0,0 +1,26 @@
+package downloadattachment
+
+import (
+	"context"
+	"io"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+)
+
+// UseCase implements the download attachment use case.
+type UseCase struct {
+	attachments Attachments
+}
+
+// New creates a new instance of the download attachment use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		attachments: deps.Attachments,
+	}
+}
+
+// Execute returns an attachment and its content, or the content of its
+// thumbnail. The caller closes the content.
+func (uc *UseCase) Execute(ctx context.Context, input DownloadInput) (*entities.Attachment, io.ReadCloser, error) {
+	return uc.attachments.Open(ctx, input.AttachmentID, input.Thumbnail, input.Expires, input.Signature)
+}
@@ -0,0 +1,18 @@
+package editmessage
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	EditMessage(ctx context.Context, roomID, userID, messageID uuid.UUID, content string) (*entities.Message, error)
+}
+
+// Deps holds the dependencies for the edit message use case.
+type Deps struct {
+	ChatService ChatService
+}
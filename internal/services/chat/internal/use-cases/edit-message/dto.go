@@ -0,0 +1,11 @@
+package editmessage
+
+import "github.com/google/uuid"
+
+// EditInput represents the input data for editing a message.
+type EditInput struct {
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	MessageID uuid.UUID
+	Content   string
+}
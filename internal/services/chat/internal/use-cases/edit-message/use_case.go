@@ -0,0 +1,38 @@
+package editmessage
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the edit message use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the edit message use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute replaces the content of an existing message.
+func (uc *UseCase) Execute(ctx context.Context, input EditInput) (*entities.Message, error) {
+	if input.Content == "" {
+		return nil, errors.New("message content cannot be empty")
+	}
+	if utf8.RuneCountInString(input.Content) > entities.MaxMessageLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "message content exceeds %d characters", entities.MaxMessageLength)
+	}
+
+	msg, err := uc.chatService.EditMessage(ctx, input.RoomID, input.UserID, input.MessageID, input.Content)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to edit message")
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,25 @@
+package exportroom
+
+import (
+	"context"
+	"io"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Storage checks who may export a room.
+type Storage interface {
+	IsParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
+}
+
+// Exporter writes the history of a room.
+type Exporter interface {
+	Export(ctx context.Context, roomID uuid.UUID, format entities.ExportFormat, w io.Writer) error
+}
+
+// Deps holds the dependencies for the export room use case.
+type Deps struct {
+	Storage  Storage
+	Exporter Exporter
+}
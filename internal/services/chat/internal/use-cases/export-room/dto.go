@@ -0,0 +1,13 @@
+package exportroom
+
+import (
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ExportInput represents a request to export the history of a room.
+type ExportInput struct {
+	RoomID uuid.UUID
+	UserID uuid.UUID
+	Format entities.ExportFormat
+}
@@ -0,0 +1,37 @@
+package exportroom
+
+import (
+	"context"
+	"io"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the export room use case.
+type UseCase struct {
+	storage  Storage
+	exporter Exporter
+}
+
+// New creates a new instance of the export room use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		storage:  deps.Storage,
+		exporter: deps.Exporter,
+	}
+}
+
+// Execute writes the history of a room on behalf of one of its participants.
+// Nothing is written when the export is refused.
+func (uc *UseCase) Execute(ctx context.Context, input ExportInput, w io.Writer) error {
+	isParticipant, err := uc.storage.IsParticipant(ctx, input.RoomID, input.UserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify participant")
+	}
+	if !isParticipant {
+		return entities.ErrForbidden
+	}
+
+	return uc.exporter.Export(ctx, input.RoomID, input.Format, w)
+}
@@ -0,0 +1,18 @@
+package getcommands
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Commands defines the interface of the slash command registry.
+type Commands interface {
+	Available(ctx context.Context, roomID *uuid.UUID, userID uuid.UUID, permissions []string) ([]entities.CommandInfo, error)
+}
+
+// Deps holds the dependencies for the get commands use case.
+type Deps struct {
+	Commands Commands
+}
@@ -0,0 +1,10 @@
+package getcommands
+
+import "github.com/google/uuid"
+
+// CommandsInput represents the input data for listing slash commands.
+type CommandsInput struct {
+	RoomID      *uuid.UUID // Optional; room owners see the commands of their room.
+	UserID      uuid.UUID
+	Permissions []string
+}
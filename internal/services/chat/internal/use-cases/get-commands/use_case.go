@@ -0,0 +1,30 @@
+package getcommands
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the get commands use case.
+type UseCase struct {
+	commands Commands
+}
+
+// New creates a new instance of the get commands use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		commands: deps.Commands,
+	}
+}
+
+// Execute lists the slash commands the user may run, for help and autocomplete.
+func (uc *UseCase) Execute(ctx context.Context, input CommandsInput) (*entities.CommandsPayload, error) {
+	commands, err := uc.commands.Available(ctx, input.RoomID, input.UserID, input.Permissions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list commands")
+	}
+
+	return &entities.CommandsPayload{Commands: commands}, nil
+}
@@ -9,7 +9,7 @@ import (
 
 // ChatService defines the interface for chat operations.
 type ChatService interface {
-	GetRoomMessages(ctx context.Context, roomID uuid.UUID, limit, offset int) ([]*entities.Message, error)
+	GetRoomMessages(ctx context.Context, roomID, userID uuid.UUID, before, after *entities.MessageCursor, limit int) (*entities.MessagePage, error)
 }
 
 // Deps holds the dependencies for the get messages use case.
@@ -8,12 +8,18 @@ import (
 // MessagesInput represents the input data for getting messages.
 type MessagesInput struct {
 	RoomID uuid.UUID
+	UserID uuid.UUID
 	Limit  int
-	Offset int
+	Before *entities.MessageCursor
+	After  *entities.MessageCursor
 }
 
 // MessagesResponse represents the response structure for messages.
 type MessagesResponse struct {
-	Messages []*entities.Message `json:"messages"`
-	Total    int                 `json:"total"`
+	Messages   []*entities.Message `json:"messages"`
+	Total      int                 `json:"total"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	PrevCursor string              `json:"prev_cursor,omitempty"`
+	// LastSeq is the room sequence a client resumes from after loading the page.
+	LastSeq int64 `json:"last_seq"`
 }
@@ -27,19 +27,17 @@ func (uc *UseCase) Execute(ctx context.Context, input MessagesInput) (*MessagesR
 		input.Limit = 100 // Max limit.
 	}
 
-	// Ensure offset is not negative.
-	if input.Offset < 0 {
-		input.Offset = 0
-	}
-
-	messages, err := uc.chatService.GetRoomMessages(ctx, input.RoomID, input.Limit, input.Offset)
+	page, err := uc.chatService.GetRoomMessages(ctx, input.RoomID, input.UserID, input.Before, input.After, input.Limit)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get room messages")
 	}
 
 	response := &MessagesResponse{
-		Messages: messages,
-		Total:    len(messages),
+		Messages:   page.Messages,
+		Total:      len(page.Messages),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		LastSeq:    page.LastSeq,
 	}
 
 	return response, nil
@@ -0,0 +1,18 @@
+package getpresence
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	GetPresence(ctx context.Context, userIDs []uuid.UUID) ([]entities.Presence, error)
+}
+
+// Deps holds the dependencies for the get presence use case.
+type Deps struct {
+	ChatService ChatService
+}
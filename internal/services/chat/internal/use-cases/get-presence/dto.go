@@ -0,0 +1,8 @@
+package getpresence
+
+import "github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+
+// PresenceResponse represents the response structure for a presence lookup.
+type PresenceResponse struct {
+	Presence []entities.Presence `json:"presence"`
+}
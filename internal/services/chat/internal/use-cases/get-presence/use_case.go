@@ -0,0 +1,37 @@
+package getpresence
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the get presence use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the get presence use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute returns the global presence of a batch of users, in the requested order.
+func (uc *UseCase) Execute(ctx context.Context, userIDs []uuid.UUID) (*PresenceResponse, error) {
+	if len(userIDs) == 0 || len(userIDs) > entities.MaxPresenceUsers {
+		return nil, errors.Wrapf(entities.ErrInvalidPresence, "between 1 and %d users must be requested", entities.MaxPresenceUsers)
+	}
+
+	presence, err := uc.chatService.GetPresence(ctx, userIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get presence")
+	}
+
+	return &PresenceResponse{
+		Presence: presence,
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 // Storage defines the interface for chat data persistence.
 type Storage interface {
 	GetRoomParticipants(ctx context.Context, roomID uuid.UUID) ([]uuid.UUID, error)
+	IsParticipant(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
 }
 
 // WebsiteService defines the interface for room validation.
@@ -22,8 +22,8 @@ func New(deps Deps) *UseCase {
 	}
 }
 
-// Execute retrieves all participants from a chat room.
-func (uc *UseCase) Execute(ctx context.Context, roomID uuid.UUID) ([]uuid.UUID, error) {
+// Execute retrieves all participants from a chat room on behalf of one of its participants.
+func (uc *UseCase) Execute(ctx context.Context, roomID, userID uuid.UUID) ([]uuid.UUID, error) {
 	// Validate room existence.
 	exists, err := uc.websiteService.RoomExists(ctx, roomID)
 	if err != nil {
@@ -33,6 +33,14 @@ func (uc *UseCase) Execute(ctx context.Context, roomID uuid.UUID) ([]uuid.UUID,
 		return nil, entities.ErrRoomNotFound
 	}
 
+	isParticipant, err := uc.storage.IsParticipant(ctx, roomID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify participant")
+	}
+	if !isParticipant {
+		return nil, entities.ErrForbidden
+	}
+
 	participants, err := uc.storage.GetRoomParticipants(ctx, roomID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get room participants")
@@ -0,0 +1,18 @@
+package getthread
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	GetThreadMessages(ctx context.Context, roomID, parentID uuid.UUID, limit, offset int) (*entities.Message, []*entities.Message, error)
+}
+
+// Deps holds the dependencies for the get thread use case.
+type Deps struct {
+	ChatService ChatService
+}
@@ -0,0 +1,21 @@
+package getthread
+
+import (
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ThreadInput represents the input data for getting a thread.
+type ThreadInput struct {
+	RoomID   uuid.UUID
+	ParentID uuid.UUID
+	Limit    int
+	Offset   int
+}
+
+// ThreadResponse represents the response structure for a thread.
+type ThreadResponse struct {
+	Parent   *entities.Message   `json:"parent"`
+	Messages []*entities.Message `json:"messages"`
+	Total    int                 `json:"total"`
+}
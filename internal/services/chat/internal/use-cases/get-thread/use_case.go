@@ -0,0 +1,45 @@
+package getthread
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the get thread use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the get thread use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute retrieves the replies of a thread.
+func (uc *UseCase) Execute(ctx context.Context, input ThreadInput) (*ThreadResponse, error) {
+	// Validate limit.
+	if input.Limit <= 0 {
+		input.Limit = 50 // Default limit.
+	} else if input.Limit > 100 {
+		input.Limit = 100 // Max limit.
+	}
+
+	// Ensure offset is not negative.
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	parent, replies, err := uc.chatService.GetThreadMessages(ctx, input.RoomID, input.ParentID, input.Limit, input.Offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get thread messages")
+	}
+
+	return &ThreadResponse{
+		Parent:   parent,
+		Messages: replies,
+		Total:    parent.ReplyCount,
+	}, nil
+}
@@ -0,0 +1,18 @@
+package getunreadcounts
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	GetUnreadCounts(ctx context.Context, userID uuid.UUID) ([]entities.UnreadCount, error)
+}
+
+// Deps holds the dependencies for the get unread counts use case.
+type Deps struct {
+	ChatService ChatService
+}
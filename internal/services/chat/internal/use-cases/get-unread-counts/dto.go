@@ -0,0 +1,9 @@
+package getunreadcounts
+
+import "github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+
+// UnreadCountsResponse represents the response structure for unread counts.
+type UnreadCountsResponse struct {
+	Rooms []entities.UnreadCount `json:"rooms"`
+	Total int                    `json:"total"`
+}
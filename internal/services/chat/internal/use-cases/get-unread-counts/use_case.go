@@ -0,0 +1,38 @@
+package getunreadcounts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the get unread counts use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the get unread counts use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute returns the unread message counts of every room the user has joined.
+func (uc *UseCase) Execute(ctx context.Context, userID uuid.UUID) (*UnreadCountsResponse, error) {
+	counts, err := uc.chatService.GetUnreadCounts(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get unread counts")
+	}
+
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+
+	return &UnreadCountsResponse{
+		Rooms: counts,
+		Total: total,
+	}, nil
+}
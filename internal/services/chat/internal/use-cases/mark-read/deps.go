@@ -0,0 +1,17 @@
+package markread
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	MarkRead(ctx context.Context, roomID, userID, messageID uuid.UUID) error
+}
+
+// Deps holds the dependencies for the mark read use case.
+type Deps struct {
+	ChatService ChatService
+}
@@ -0,0 +1,10 @@
+package markread
+
+import "github.com/google/uuid"
+
+// MarkReadInput represents the input data for the mark read use case.
+type MarkReadInput struct {
+	RoomID    uuid.UUID
+	UserID    uuid.UUID
+	MessageID uuid.UUID
+}
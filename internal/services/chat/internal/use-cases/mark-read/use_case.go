@@ -0,0 +1,28 @@
+package markread
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the mark read use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the mark read use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute records that a user has read a room up to a message.
+func (uc *UseCase) Execute(ctx context.Context, input MarkReadInput) error {
+	if err := uc.chatService.MarkRead(ctx, input.RoomID, input.UserID, input.MessageID); err != nil {
+		return errors.Wrap(err, "failed to mark messages as read")
+	}
+
+	return nil
+}
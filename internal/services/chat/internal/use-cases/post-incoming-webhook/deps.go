@@ -0,0 +1,31 @@
+package postincomingwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Integrations resolves the tokens of incoming webhooks.
+type Integrations interface {
+	ResolveIncomingWebhook(ctx context.Context, token string) (*entities.Integration, error)
+}
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	HandleIntegrationMessage(ctx context.Context, integration *entities.Integration, content, clientMsgID string) (*entities.Message, error)
+	FindSentMessage(ctx context.Context, userID uuid.UUID, clientMsgID string) (*entities.Message, error)
+}
+
+// RateLimiter defines the interface for the per-user, per-room and per-IP message limits.
+type RateLimiter interface {
+	Allow(roomID, userID uuid.UUID, clientIP string) error
+}
+
+// Deps holds the dependencies for the post incoming webhook use case.
+type Deps struct {
+	Integrations Integrations
+	ChatService  ChatService
+	RateLimiter  RateLimiter
+}
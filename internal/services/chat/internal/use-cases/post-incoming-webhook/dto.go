@@ -0,0 +1,9 @@
+package postincomingwebhook
+
+// IncomingWebhookInput represents a message posted to an incoming webhook.
+type IncomingWebhookInput struct {
+	Token       string
+	Content     string
+	ClientMsgID string // Optional; retries with the same ID are stored once.
+	ClientIP    string // Optional; empty skips the per-IP limit.
+}
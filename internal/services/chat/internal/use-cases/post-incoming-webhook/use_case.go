@@ -0,0 +1,65 @@
+package postincomingwebhook
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the post incoming webhook use case.
+type UseCase struct {
+	integrations Integrations
+	chatService  ChatService
+	rateLimiter  RateLimiter
+}
+
+// New creates a new instance of the post incoming webhook use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		integrations: deps.Integrations,
+		chatService:  deps.ChatService,
+		rateLimiter:  deps.RateLimiter,
+	}
+}
+
+// Execute posts a message to the room of an incoming webhook and returns the
+// stored message. The integration counts as the user for the rate limits.
+// Slash commands are not run: the content is stored as is.
+func (uc *UseCase) Execute(ctx context.Context, input IncomingWebhookInput) (*entities.Message, error) {
+	if input.Content == "" {
+		return nil, errors.Wrap(entities.ErrInvalidMessage, "message content cannot be empty")
+	}
+	if utf8.RuneCountInString(input.Content) > entities.MaxMessageLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "message content exceeds %d characters", entities.MaxMessageLength)
+	}
+	if len(input.ClientMsgID) > entities.MaxClientMsgIDLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "client message ID exceeds %d characters", entities.MaxClientMsgIDLength)
+	}
+
+	integration, err := uc.integrations.ResolveIncomingWebhook(ctx, input.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// A retry of a stored message is acknowledged without counting against the limits.
+	sent, err := uc.chatService.FindSentMessage(ctx, integration.ID, input.ClientMsgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to post message")
+	}
+	if sent != nil {
+		return sent, nil
+	}
+
+	if err := uc.rateLimiter.Allow(integration.RoomID, integration.ID, input.ClientIP); err != nil {
+		return nil, err
+	}
+
+	msg, err := uc.chatService.HandleIntegrationMessage(ctx, integration, input.Content, input.ClientMsgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to post message")
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,35 @@
+package removereaction
+
+import (
+	"context"
+	"strings"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the remove reaction use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the remove reaction use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute removes an emoji reaction from a message.
+func (uc *UseCase) Execute(ctx context.Context, input ReactionInput) error {
+	input.Emoji = strings.TrimSpace(input.Emoji)
+	if input.Emoji == "" {
+		return entities.ErrInvalidReaction
+	}
+
+	if err := uc.chatService.RemoveReaction(ctx, input.RoomID, input.UserID, input.MessageID, input.Emoji); err != nil {
+		return errors.Wrap(err, "failed to remove reaction")
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package runcommand
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Commands defines the interface of the slash command registry.
+type Commands interface {
+	Execute(ctx context.Context, call *entities.CommandCall) (*entities.CommandResult, error)
+}
+
+// RateLimiter defines the interface for the per-user, per-room and per-IP message limits.
+type RateLimiter interface {
+	Allow(roomID, userID uuid.UUID, clientIP string) error
+}
+
+// Deps holds the dependencies for the run command use case.
+type Deps struct {
+	Commands    Commands
+	RateLimiter RateLimiter
+}
@@ -0,0 +1,16 @@
+package runcommand
+
+import (
+	"github.com/google/uuid"
+)
+
+// CommandInput represents a message holding a slash command.
+type CommandInput struct {
+	RoomID      uuid.UUID
+	UserID      uuid.UUID
+	Permissions []string // Permissions of the user, from the Auth Service.
+	Content     string
+	ParentID    *uuid.UUID
+	ClientMsgID string // Optional; passed on to messages the command posts.
+	ClientIP    string // Optional; empty skips the per-IP limit.
+}
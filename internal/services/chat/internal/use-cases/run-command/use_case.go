@@ -0,0 +1,64 @@
+package runcommand
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the run command use case.
+type UseCase struct {
+	commands    Commands
+	rateLimiter RateLimiter
+}
+
+// New creates a new instance of the run command use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		commands:    deps.Commands,
+		rateLimiter: deps.RateLimiter,
+	}
+}
+
+// IsCommand reports whether a message is a slash command rather than a message
+// to post.
+func IsCommand(content string) bool {
+	_, _, ok := entities.ParseCommand(content)
+	return ok
+}
+
+// Execute runs the slash command a message holds. Commands count against the
+// same rate limits as messages.
+func (uc *UseCase) Execute(ctx context.Context, input CommandInput) (*entities.CommandResult, error) {
+	name, args, ok := entities.ParseCommand(input.Content)
+	if !ok {
+		return nil, errors.Wrap(entities.ErrUnknownCommand, "message is not a command")
+	}
+	if utf8.RuneCountInString(input.Content) > entities.MaxMessageLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "message content exceeds %d characters", entities.MaxMessageLength)
+	}
+	if len(input.ClientMsgID) > entities.MaxClientMsgIDLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "client message ID exceeds %d characters", entities.MaxClientMsgIDLength)
+	}
+
+	if err := uc.rateLimiter.Allow(input.RoomID, input.UserID, input.ClientIP); err != nil {
+		return nil, err
+	}
+
+	result, err := uc.commands.Execute(ctx, &entities.CommandCall{
+		RoomID:      input.RoomID,
+		UserID:      input.UserID,
+		Permissions: input.Permissions,
+		Name:        name,
+		Args:        args,
+		ParentID:    input.ParentID,
+		ClientMsgID: input.ClientMsgID,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run /%s", name)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,18 @@
+package searchmessages
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	SearchMessages(ctx context.Context, userID uuid.UUID, filter entities.SearchFilter) ([]entities.SearchResult, error)
+}
+
+// Deps holds the dependencies for the search messages use case.
+type Deps struct {
+	ChatService ChatService
+}
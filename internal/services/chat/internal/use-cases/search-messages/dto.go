@@ -0,0 +1,26 @@
+package searchmessages
+
+import (
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// SearchInput represents the input data for searching messages.
+type SearchInput struct {
+	UserID   uuid.UUID
+	Query    string
+	RoomID   *uuid.UUID
+	AuthorID *uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
+
+// SearchResponse represents the response structure for a search.
+type SearchResponse struct {
+	Results []entities.SearchResult `json:"results"`
+	Total   int                     `json:"total"`
+}
@@ -0,0 +1,67 @@
+package searchmessages
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// maxQueryLength limits the number of runes in a search query.
+const maxQueryLength = 256
+
+// UseCase implements the search messages use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the search messages use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute searches the messages of the rooms the user has joined.
+func (uc *UseCase) Execute(ctx context.Context, input SearchInput) (*SearchResponse, error) {
+	input.Query = strings.TrimSpace(input.Query)
+	if input.Query == "" || utf8.RuneCountInString(input.Query) > maxQueryLength {
+		return nil, errors.Wrap(entities.ErrInvalidSearch, "query must be between 1 and 256 characters")
+	}
+
+	if input.From != nil && input.To != nil && input.From.After(*input.To) {
+		return nil, errors.Wrap(entities.ErrInvalidSearch, "date range start is after its end")
+	}
+
+	// Validate limit.
+	if input.Limit <= 0 {
+		input.Limit = 20 // Default limit.
+	} else if input.Limit > 100 {
+		input.Limit = 100 // Max limit.
+	}
+
+	// Ensure offset is not negative.
+	if input.Offset < 0 {
+		input.Offset = 0
+	}
+
+	results, err := uc.chatService.SearchMessages(ctx, input.UserID, entities.SearchFilter{
+		Text:     input.Query,
+		RoomID:   input.RoomID,
+		AuthorID: input.AuthorID,
+		From:     input.From,
+		To:       input.To,
+		Limit:    input.Limit,
+		Offset:   input.Offset,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search messages")
+	}
+
+	return &SearchResponse{
+		Results: results,
+		Total:   len(results),
+	}, nil
+}
@@ -9,10 +9,17 @@ import (
 
 // ChatService defines the interface for chat operations.
 type ChatService interface {
-	HandleMessage(ctx context.Context, roomID, userID uuid.UUID, content string, event *entities.Event) error
+	HandleMessage(ctx context.Context, roomID, userID uuid.UUID, content, clientMsgID string, parentID *uuid.UUID, attachmentIDs []uuid.UUID) (*entities.Message, error)
+	FindSentMessage(ctx context.Context, userID uuid.UUID, clientMsgID string) (*entities.Message, error)
+}
+
+// RateLimiter defines the interface for the per-user, per-room and per-IP message limits.
+type RateLimiter interface {
+	Allow(roomID, userID uuid.UUID, clientIP string) error
 }
 
 // Deps holds the dependencies for the send message use case.
 type Deps struct {
 	ChatService ChatService
+	RateLimiter RateLimiter
 }
@@ -1,14 +1,16 @@
 package sendmessage
 
 import (
-	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/google/uuid"
 )
 
 // MessageInput represents the input data for sending a message.
 type MessageInput struct {
-	RoomID  uuid.UUID
-	UserID  uuid.UUID
-	Content string
-	Event   *entities.Event
+	RoomID        uuid.UUID
+	UserID        uuid.UUID
+	Content       string
+	ParentID      *uuid.UUID
+	AttachmentIDs []uuid.UUID // Optional; uploads of the user to the room.
+	ClientMsgID   string      // Optional; retries with the same ID are stored once.
+	ClientIP      string      // Optional; empty skips the per-IP limit.
 }
@@ -2,31 +2,59 @@ package sendmessage
 
 import (
 	"context"
+	"unicode/utf8"
 
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
 	"github.com/pkg/errors"
 )
 
 // UseCase implements the send message use case.
 type UseCase struct {
 	chatService ChatService
+	rateLimiter RateLimiter
 }
 
 // New creates a new instance of the send message use case.
 func New(deps Deps) *UseCase {
 	return &UseCase{
 		chatService: deps.ChatService,
+		rateLimiter: deps.RateLimiter,
 	}
 }
 
-// Execute sends a new message to a chat room.
-func (uc *UseCase) Execute(ctx context.Context, input MessageInput) error {
-	if input.Content == "" {
-		return errors.New("message content cannot be empty")
+// Execute sends a new message to a chat room and returns the stored message.
+// Slash commands go through the run command use case instead; a message that
+// starts with a double slash is stored with a single one. A message with
+// attachments may have no content.
+func (uc *UseCase) Execute(ctx context.Context, input MessageInput) (*entities.Message, error) {
+	if input.Content == "" && len(input.AttachmentIDs) == 0 {
+		return nil, errors.New("message content cannot be empty")
+	}
+	if utf8.RuneCountInString(input.Content) > entities.MaxMessageLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "message content exceeds %d characters", entities.MaxMessageLength)
+	}
+	if len(input.ClientMsgID) > entities.MaxClientMsgIDLength {
+		return nil, errors.Wrapf(entities.ErrInvalidMessage, "client message ID exceeds %d characters", entities.MaxClientMsgIDLength)
+	}
+
+	// A retry of a stored message is acknowledged without counting against the limits.
+	sent, err := uc.chatService.FindSentMessage(ctx, input.UserID, input.ClientMsgID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send message")
+	}
+	if sent != nil {
+		return sent, nil
+	}
+
+	if err := uc.rateLimiter.Allow(input.RoomID, input.UserID, input.ClientIP); err != nil {
+		return nil, err
 	}
 
-	if err := uc.chatService.HandleMessage(ctx, input.RoomID, input.UserID, input.Content, input.Event); err != nil {
-		return errors.Wrap(err, "failed to send message")
+	content := entities.UnescapeCommand(input.Content)
+	msg, err := uc.chatService.HandleMessage(ctx, input.RoomID, input.UserID, content, input.ClientMsgID, input.ParentID, input.AttachmentIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send message")
 	}
 
-	return nil
+	return msg, nil
 }
@@ -0,0 +1,18 @@
+package setpresence
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	SetPresence(ctx context.Context, userID, connectionID uuid.UUID, status entities.PresenceStatus) error
+}
+
+// Deps holds the dependencies for the set presence use case.
+type Deps struct {
+	ChatService ChatService
+}
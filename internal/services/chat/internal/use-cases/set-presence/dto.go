@@ -0,0 +1,13 @@
+package setpresence
+
+import (
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// PresenceInput represents a presence heartbeat of one connection of a user.
+type PresenceInput struct {
+	UserID       uuid.UUID
+	ConnectionID uuid.UUID
+	Status       entities.PresenceStatus
+}
@@ -0,0 +1,32 @@
+package setpresence
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the set presence use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the set presence use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute records a client heartbeat. An empty status is treated as online.
+func (uc *UseCase) Execute(ctx context.Context, input PresenceInput) error {
+	if input.Status == "" {
+		input.Status = entities.PresenceOnline
+	}
+
+	if err := uc.chatService.SetPresence(ctx, input.UserID, input.ConnectionID, input.Status); err != nil {
+		return errors.Wrap(err, "failed to set presence")
+	}
+	return nil
+}
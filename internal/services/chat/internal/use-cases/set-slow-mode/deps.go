@@ -0,0 +1,18 @@
+package setslowmode
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	SetSlowMode(ctx context.Context, roomID, userID uuid.UUID, interval time.Duration) error
+}
+
+// Deps holds the dependencies for the set slow mode use case.
+type Deps struct {
+	ChatService ChatService
+}
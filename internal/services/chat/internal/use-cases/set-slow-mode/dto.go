@@ -0,0 +1,10 @@
+package setslowmode
+
+import "github.com/google/uuid"
+
+// SlowModeInput represents the input data for the set slow mode use case.
+type SlowModeInput struct {
+	RoomID  uuid.UUID
+	UserID  uuid.UUID
+	Seconds int // 0 turns slow mode off.
+}
@@ -0,0 +1,35 @@
+package setslowmode
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the set slow mode use case.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the set slow mode use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute changes the slow mode of a room on behalf of its owner.
+func (uc *UseCase) Execute(ctx context.Context, input SlowModeInput) error {
+	if input.Seconds < 0 || input.Seconds > int(entities.MaxSlowMode/time.Second) {
+		return errors.Wrapf(entities.ErrInvalidSlowMode, "seconds must be between 0 and %d", int(entities.MaxSlowMode/time.Second))
+	}
+
+	interval := time.Duration(input.Seconds) * time.Second
+	if err := uc.chatService.SetSlowMode(ctx, input.RoomID, input.UserID, interval); err != nil {
+		return errors.Wrap(err, "failed to set slow mode")
+	}
+
+	return nil
+}
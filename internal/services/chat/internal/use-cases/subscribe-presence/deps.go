@@ -0,0 +1,19 @@
+package subscribepresence
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	SubscribePresence(ctx context.Context, conn entities.Connection, userIDs []uuid.UUID) ([]entities.Presence, error)
+	UnsubscribePresence(conn entities.Connection, userIDs []uuid.UUID)
+}
+
+// Deps holds the dependencies for the subscribe presence use case.
+type Deps struct {
+	ChatService ChatService
+}
@@ -0,0 +1,13 @@
+package subscribepresence
+
+import (
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// SubscribeInput represents the input data for the subscribe presence use case.
+type SubscribeInput struct {
+	Connection  entities.Connection
+	UserIDs     []uuid.UUID
+	Unsubscribe bool
+}
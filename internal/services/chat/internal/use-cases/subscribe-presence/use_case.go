@@ -0,0 +1,41 @@
+package subscribepresence
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the subscribe presence use case.
+// Subscriptions live in memory and end with their connection.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the subscribe presence use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute subscribes the connection to the presence of the given users and returns
+// their current presence, or unsubscribes it and returns nothing.
+func (uc *UseCase) Execute(ctx context.Context, input SubscribeInput) ([]entities.Presence, error) {
+	if len(input.UserIDs) == 0 || len(input.UserIDs) > entities.MaxPresenceUsers {
+		return nil, errors.Wrapf(entities.ErrInvalidPresence, "between 1 and %d users must be requested", entities.MaxPresenceUsers)
+	}
+
+	if input.Unsubscribe {
+		uc.chatService.UnsubscribePresence(input.Connection, input.UserIDs)
+		return nil, nil
+	}
+
+	presence, err := uc.chatService.SubscribePresence(ctx, input.Connection, input.UserIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to subscribe to presence")
+	}
+
+	return presence, nil
+}
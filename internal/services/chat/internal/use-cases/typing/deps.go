@@ -0,0 +1,14 @@
+package typing
+
+import "github.com/google/uuid"
+
+// ChatService defines the interface for chat operations.
+type ChatService interface {
+	StartTyping(roomID, userID uuid.UUID) error
+	StopTyping(roomID, userID uuid.UUID) error
+}
+
+// Deps holds the dependencies for the typing use case.
+type Deps struct {
+	ChatService ChatService
+}
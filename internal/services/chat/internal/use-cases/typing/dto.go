@@ -0,0 +1,10 @@
+package typing
+
+import "github.com/google/uuid"
+
+// TypingInput represents the input data for the typing use case.
+type TypingInput struct {
+	RoomID uuid.UUID
+	UserID uuid.UUID
+	Typing bool
+}
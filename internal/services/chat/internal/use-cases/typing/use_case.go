@@ -0,0 +1,35 @@
+package typing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the typing indicator use case.
+// Typing state is ephemeral and never persisted.
+type UseCase struct {
+	chatService ChatService
+}
+
+// New creates a new instance of the typing use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		chatService: deps.ChatService,
+	}
+}
+
+// Execute starts or stops the typing indicator of a user in a room.
+func (uc *UseCase) Execute(ctx context.Context, input TypingInput) error {
+	if input.Typing {
+		if err := uc.chatService.StartTyping(input.RoomID, input.UserID); err != nil {
+			return errors.Wrap(err, "failed to start typing")
+		}
+		return nil
+	}
+
+	if err := uc.chatService.StopTyping(input.RoomID, input.UserID); err != nil {
+		return errors.Wrap(err, "failed to stop typing")
+	}
+	return nil
+}
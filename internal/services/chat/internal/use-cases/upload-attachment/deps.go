@@ -0,0 +1,25 @@
+package uploadattachment
+
+import (
+	"context"
+	"io"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/google/uuid"
+)
+
+// Attachments stores uploaded files.
+type Attachments interface {
+	Upload(ctx context.Context, roomID, userID uuid.UUID, fileName string, r io.Reader) (*entities.Attachment, error)
+}
+
+// RateLimiter defines the interface for the per-user, per-room and per-IP message limits.
+type RateLimiter interface {
+	Allow(roomID, userID uuid.UUID, clientIP string) error
+}
+
+// Deps holds the dependencies for the upload attachment use case.
+type Deps struct {
+	Attachments Attachments
+	RateLimiter RateLimiter
+}
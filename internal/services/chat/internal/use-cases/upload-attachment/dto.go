@@ -0,0 +1,16 @@
+package uploadattachment
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// UploadInput represents a file uploaded to a room.
+type UploadInput struct {
+	RoomID   uuid.UUID
+	UserID   uuid.UUID
+	FileName string
+	Content  io.Reader
+	ClientIP string // Optional; empty skips the per-IP limit.
+}
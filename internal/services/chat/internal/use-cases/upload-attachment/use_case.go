@@ -0,0 +1,37 @@
+package uploadattachment
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/chat/internal/entities"
+	"github.com/pkg/errors"
+)
+
+// UseCase implements the upload attachment use case.
+type UseCase struct {
+	attachments Attachments
+	rateLimiter RateLimiter
+}
+
+// New creates a new instance of the upload attachment use case.
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		attachments: deps.Attachments,
+		rateLimiter: deps.RateLimiter,
+	}
+}
+
+// Execute stores a file uploaded by a participant of a room. Uploads count
+// against the message rate limits.
+func (uc *UseCase) Execute(ctx context.Context, input UploadInput) (*entities.Attachment, error) {
+	if err := uc.rateLimiter.Allow(input.RoomID, input.UserID, input.ClientIP); err != nil {
+		return nil, err
+	}
+
+	attachment, err := uc.attachments.Upload(ctx, input.RoomID, input.UserID, input.FileName, input.Content)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to upload attachment")
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/HexArch/go-chat/internal/pkg/logger"
+	"github.com/HexArch/go-chat/internal/pkg/slackexport"
+	"github.com/HexArch/go-chat/internal/services/website/internal/clients/auth"
+	"github.com/HexArch/go-chat/internal/services/website/internal/config"
+	roomstorage "github.com/HexArch/go-chat/internal/services/website/internal/services/rooms/storage"
+	"github.com/HexArch/go-chat/internal/services/website/internal/services/slackimport"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// import creates the users and rooms of a Slack export and writes the mapping
+// the Chat Service import needs to bring in the messages. It is the first step
+// of a migration from Slack and can be run again on the same export.
+func main() {
+	configPath := flag.String("config", "../../configs/config.prod.yaml", "Path to the configuration file")
+	archivePath := flag.String("archive", "", "Slack export zip to import")
+	mappingPath := flag.String("mapping", "slack-mapping.json", "File to write the user and room mapping to")
+	emailDomain := flag.String("email-domain", "slack-import.test", "Email domain of placeholder users")
+	flag.Parse()
+
+	if *archivePath == "" {
+		log.Fatal("Missing -archive")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger, err := logger.NewLogger(cfg.Logging.Level)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	archive, err := slackexport.Open(*archivePath)
+	if err != nil {
+		logger.Fatal("Failed to open Slack export", zap.Error(err))
+	}
+	defer archive.Close()
+
+	mapping, err := slackexport.LoadMapping(*mappingPath)
+	if err != nil {
+		logger.Fatal("Failed to load mapping", zap.Error(err))
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.Engines.Storage.URL), &gorm.Config{})
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	authClient, err := auth.NewAuthClient(logger, cfg.AuthService.Address, cfg.AuthService.ServiceToken)
+	if err != nil {
+		logger.Fatal("Failed to create auth client", zap.Error(err))
+	}
+	defer authClient.Close()
+
+	importer := slackimport.New(slackimport.Deps{
+		Storage: roomstorage.New(db),
+		Users:   authClient,
+	}, slackimport.Config{
+		EmailDomain: *emailDomain,
+	}, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, importErr := importer.Import(ctx, archive, mapping)
+	// What was imported before a failure is saved too, for the next run to reuse.
+	if err := mapping.Save(*mappingPath); err != nil {
+		logger.Fatal("Failed to save mapping", zap.Error(err))
+	}
+	if importErr != nil {
+		logger.Fatal("Failed to import Slack export", zap.Error(importErr))
+	}
+
+	logger.Info("Slack export imported",
+		zap.Int("users_matched", result.UsersMatched),
+		zap.Int("users_created", result.UsersCreated),
+		zap.Int("rooms_created", result.RoomsCreated),
+		zap.Int("rooms_reused", result.RoomsReused),
+		zap.Int("rooms_skipped", result.RoomsSkipped),
+		zap.String("mapping", *mappingPath),
+	)
+}
@@ -12,12 +12,36 @@ import (
 	"github.com/HexArch/go-chat/internal/services/website/internal/metrics"
 	"github.com/HexArch/go-chat/internal/services/website/internal/services/rooms"
 	roomstorage "github.com/HexArch/go-chat/internal/services/website/internal/services/rooms/storage"
+	"github.com/HexArch/go-chat/internal/services/website/internal/services/webhooks"
+	creategrouproom "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-group-room"
+	createincomingwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-incoming-webhook"
 	createroom "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-room"
+	createroomscript "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-room-script"
+	createroomwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-room-webhook"
+	deleteincomingwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-incoming-webhook"
 	deleteroom "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-room"
+	deleteroomscript "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-room-script"
+	deleteroomwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-room-webhook"
 	getallrooms "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-all-rooms"
+	getenabledscripts "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-enabled-scripts"
+	getincomingwebhooks "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-incoming-webhooks"
+	getmemberrooms "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-member-rooms"
+	getorcreatedirectroom "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-or-create-direct-room"
 	getownerrooms "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-owner-rooms"
+	getretentionpolicies "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-retention-policies"
+	getretentionpolicy "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-retention-policy"
 	getroom "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-room"
+	getroomscripts "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-room-scripts"
+	getroomwebhooks "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-room-webhooks"
+	getwebhookdeadletters "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-webhook-dead-letters"
+	getwebhookdeliveries "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-webhook-deliveries"
+	publishroomevent "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/publish-room-event"
+	redeliverwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/redeliver-webhook"
+	resolveincomingwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/resolve-incoming-webhook"
 	searchrooms "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/search-rooms"
+	setretentionpolicy "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/set-retention-policy"
+	updateroomscript "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/update-room-script"
+	updateroomwebhook "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/update-room-webhook"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
@@ -30,6 +54,7 @@ type App struct {
 	grShutdown *graceful.Shutdown
 	metrics    *metrics.WebsiteMetrics
 	server     *controllers.Server
+	dispatcher *webhooks.Dispatcher
 	db         *gorm.DB
 }
 
@@ -91,6 +116,75 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 	getAllRooms := getallrooms.New(getallrooms.Deps{
 		RoomService: roomService,
 	})
+	getOrCreateDirectRoom := getorcreatedirectroom.New(getorcreatedirectroom.Deps{
+		RoomService: roomService,
+	})
+	createGroupRoom := creategrouproom.New(creategrouproom.Deps{
+		RoomService: roomService,
+	})
+	getMemberRooms := getmemberrooms.New(getmemberrooms.Deps{
+		RoomService: roomService,
+	})
+	createRoomScript := createroomscript.New(createroomscript.Deps{
+		RoomService: roomService,
+	})
+	updateRoomScript := updateroomscript.New(updateroomscript.Deps{
+		RoomService: roomService,
+	})
+	deleteRoomScript := deleteroomscript.New(deleteroomscript.Deps{
+		RoomService: roomService,
+	})
+	getRoomScripts := getroomscripts.New(getroomscripts.Deps{
+		RoomService: roomService,
+	})
+	getEnabledScripts := getenabledscripts.New(getenabledscripts.Deps{
+		RoomService: roomService,
+	})
+	createRoomWebhook := createroomwebhook.New(createroomwebhook.Deps{
+		RoomService: roomService,
+	})
+	updateRoomWebhook := updateroomwebhook.New(updateroomwebhook.Deps{
+		RoomService: roomService,
+	})
+	deleteRoomWebhook := deleteroomwebhook.New(deleteroomwebhook.Deps{
+		RoomService: roomService,
+	})
+	getRoomWebhooks := getroomwebhooks.New(getroomwebhooks.Deps{
+		RoomService: roomService,
+	})
+	getWebhookDeliveries := getwebhookdeliveries.New(getwebhookdeliveries.Deps{
+		RoomService: roomService,
+	})
+	getWebhookDeadLetters := getwebhookdeadletters.New(getwebhookdeadletters.Deps{
+		RoomService: roomService,
+	})
+	redeliverWebhook := redeliverwebhook.New(redeliverwebhook.Deps{
+		RoomService: roomService,
+	})
+	publishRoomEvent := publishroomevent.New(publishroomevent.Deps{
+		RoomService: roomService,
+	})
+	createIncomingWebhook := createincomingwebhook.New(createincomingwebhook.Deps{
+		RoomService: roomService,
+	})
+	getIncomingWebhooks := getincomingwebhooks.New(getincomingwebhooks.Deps{
+		RoomService: roomService,
+	})
+	deleteIncomingWebhook := deleteincomingwebhook.New(deleteincomingwebhook.Deps{
+		RoomService: roomService,
+	})
+	resolveIncomingWebhook := resolveincomingwebhook.New(resolveincomingwebhook.Deps{
+		RoomService: roomService,
+	})
+	setRetentionPolicy := setretentionpolicy.New(setretentionpolicy.Deps{
+		RoomService: roomService,
+	})
+	getRetentionPolicy := getretentionpolicy.New(getretentionpolicy.Deps{
+		RoomService: roomService,
+	})
+	getRetentionPolicies := getretentionpolicies.New(getretentionpolicies.Deps{
+		RoomService: roomService,
+	})
 
 	// Initialize website service
 	roomServiceServer := controllers.NewWebsiteServiceServer(
@@ -103,8 +197,50 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 		getOwnerRooms,
 		searchRooms,
 		getAllRooms,
+		getOrCreateDirectRoom,
+		createGroupRoom,
+		getMemberRooms,
+		createRoomScript,
+		updateRoomScript,
+		deleteRoomScript,
+		getRoomScripts,
+		getEnabledScripts,
+		createRoomWebhook,
+		updateRoomWebhook,
+		deleteRoomWebhook,
+		getRoomWebhooks,
+		getWebhookDeliveries,
+		getWebhookDeadLetters,
+		redeliverWebhook,
+		publishRoomEvent,
+		createIncomingWebhook,
+		getIncomingWebhooks,
+		deleteIncomingWebhook,
+		resolveIncomingWebhook,
+		cfg.IncomingWebhooks.BaseURL,
+		setRetentionPolicy,
+		getRetentionPolicy,
+		getRetentionPolicies,
 	)
 
+	// Initialize webhook dispatcher
+	var dispatcher *webhooks.Dispatcher
+	if cfg.Webhooks.Enabled {
+		dispatcher = webhooks.NewDispatcher(webhooks.Deps{
+			Storage: roomStorage,
+		}, webhooks.Config{
+			PollInterval:   cfg.Webhooks.PollInterval,
+			BatchSize:      cfg.Webhooks.BatchSize,
+			Timeout:        cfg.Webhooks.Timeout,
+			MaxAttempts:    cfg.Webhooks.MaxAttempts,
+			InitialBackoff: cfg.Webhooks.InitialBackoff,
+			MaxBackoff:     cfg.Webhooks.MaxBackoff,
+			LogRetention:   cfg.Webhooks.LogRetention,
+
+			AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+		}, logger.Named("webhooks"))
+	}
+
 	// Initialize graceful shutdown
 	grShutdown := graceful.NewShutdown(logger)
 
@@ -122,6 +258,7 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *zap.Logger) (*App,
 		grShutdown: grShutdown,
 		metrics:    metrics,
 		server:     server,
+		dispatcher: dispatcher,
 		db:         db,
 	}, nil
 }
@@ -133,6 +270,10 @@ func (a *App) Start(ctx context.Context) {
 		}
 	}()
 
+	if a.dispatcher != nil {
+		go a.dispatcher.Run(ctx)
+	}
+
 	if err := a.grShutdown.Wait(a.cfg.GracefulShutdown); err != nil {
 		a.logger.Error("Error during graceful shutdown", zap.Error(err))
 	} else {
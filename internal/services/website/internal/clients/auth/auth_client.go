@@ -3,8 +3,11 @@ package auth
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/auth"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -71,6 +74,63 @@ func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*Validate
 	}, nil
 }
 
+// GetUsers returns a page of the users of the Auth Service using service token.
+func (c *AuthClient) GetUsers(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	resp, err := c.client.GetUsers(ctx, &auth.GetUsersRequest{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get users")
+	}
+
+	users := make([]*entities.User, 0, len(resp.Users))
+	for _, user := range resp.Users {
+		userID, err := uuid.Parse(user.Id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid user ID %q", user.Id)
+		}
+		users = append(users, &entities.User{
+			ID:       userID,
+			Email:    user.Email,
+			Username: user.Username,
+		})
+	}
+	return users, nil
+}
+
+// RegisterUser creates a regular user in the Auth Service.
+func (c *AuthClient) RegisterUser(ctx context.Context, email, username, password string) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ctx, cancel := c.createServiceContext(ctx)
+	defer cancel()
+
+	if _, err := c.client.RegisterUser(ctx, &auth.RegisterUserRequest{
+		Email:    email,
+		Username: username,
+		Password: password,
+	}); err != nil {
+		return errors.Wrap(err, "failed to register user")
+	}
+	return nil
+}
+
+func (c *AuthClient) createServiceContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+	return metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + c.serviceToken,
+	})), cancel
+}
+
 // Close closes the gRPC connection.
 func (c *AuthClient) Close() error {
 	return c.conn.Close()
@@ -23,6 +23,8 @@ type Config struct {
 	Handlers         HandlersConfig    `koanf:"handlers"`
 	AuthService      AuthServiceConfig `koanf:"auth_service"`
 	Vault            VaultConfig       `koanf:"vault"`
+	Webhooks         WebhooksConfig    `koanf:"webhooks"`
+	IncomingWebhooks IncomingWebhooks  `koanf:"incoming_webhooks"`
 	GracefulShutdown time.Duration     `koanf:"graceful_shutdown"`
 }
 
@@ -64,8 +66,33 @@ type GRPCConfig struct {
 }
 
 type AuthServiceConfig struct {
-	Address   string `koanf:"address"`
-	JWTSecret string `koanf:"jwt_secret"`
+	Address      string `koanf:"address"`
+	JWTSecret    string `koanf:"jwt_secret"`
+	ServiceToken string `koanf:"service_token"` // Accepted from other services in place of a user token.
+}
+
+// WebhooksConfig tunes the delivery of outgoing room webhooks. A delivery is
+// attempted up to MaxAttempts times, waiting InitialBackoff after the first
+// failure and twice as long after each next one, up to MaxBackoff. Webhooks
+// may only reach public addresses unless AllowPrivateNetworks is set.
+type WebhooksConfig struct {
+	Enabled        bool          `koanf:"enabled"`
+	PollInterval   time.Duration `koanf:"poll_interval"`
+	BatchSize      int           `koanf:"batch_size"`
+	Timeout        time.Duration `koanf:"timeout"`
+	MaxAttempts    int           `koanf:"max_attempts"`
+	InitialBackoff time.Duration `koanf:"initial_backoff"`
+	MaxBackoff     time.Duration `koanf:"max_backoff"`
+	LogRetention   time.Duration `koanf:"log_retention"`
+
+	AllowPrivateNetworks bool `koanf:"allow_private_networks"`
+}
+
+// IncomingWebhooks configures the URLs handed out for incoming webhooks.
+// BaseURL is where the Chat Service accepts their messages; the token is
+// appended to it.
+type IncomingWebhooks struct {
+	BaseURL string `koanf:"base_url"`
 }
 
 type VaultConfig struct {
@@ -108,6 +135,10 @@ func LoadConfig(configPath string) (*Config, error) {
 		if jwtSecret := k.String("vault.data.jwt_secret"); jwtSecret != "" {
 			k.Set("auth_service.jwt_secret", jwtSecret)
 		}
+
+		if serviceToken := k.String("vault.data.service_token"); serviceToken != "" {
+			k.Set("auth_service.service_token", serviceToken)
+		}
 	}
 
 	var config Config
@@ -133,6 +164,16 @@ func loadDefaults() error {
 		"auth_service.address":              "auth-service:9090",
 		"auth_service.jwt_secret":           "your_jwt_secret_key",
 		"vault.timeout":                     5 * time.Minute,
+		"webhooks.enabled":                  true,
+		"webhooks.poll_interval":            time.Second,
+		"webhooks.batch_size":               50,
+		"webhooks.timeout":                  10 * time.Second,
+		"webhooks.max_attempts":             8,
+		"webhooks.initial_backoff":          10 * time.Second,
+		"webhooks.max_backoff":              time.Hour,
+		"webhooks.log_retention":            7 * 24 * time.Hour,
+		"webhooks.allow_private_networks":   false,
+		"incoming_webhooks.base_url":        "http://localhost:8082/api/v1/chat/hooks",
 		"graceful_shutdown":                 15 * time.Second,
 	}
 
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/website"
+	"github.com/HexArch/go-chat/internal/services/website/internal/controllers/middleware"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *WebsiteServiceServer) CreateIncomingWebhook(ctx context.Context, req *website.CreateIncomingWebhookRequest) (*website.CreateIncomingWebhookResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("CreateIncomingWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	webhook, token, err := s.createIncomingUC.Execute(ctx, ownerID, &entities.IncomingWebhook{
+		RoomID: roomID,
+		Name:   req.Name,
+	})
+	if err != nil {
+		return nil, s.incomingWebhookStatusError(err, "create", req.RoomId)
+	}
+
+	s.logger.Info("Incoming webhook created",
+		zap.String("room_id", req.RoomId),
+		zap.String("webhook_id", webhook.ID.String()),
+		zap.String("owner_id", ownerID.String()))
+
+	return &website.CreateIncomingWebhookResponse{
+		Webhook: incomingWebhookToProto(webhook),
+		Token:   token,
+		Url:     strings.TrimSuffix(s.incomingHookURL, "/") + "/" + token,
+	}, nil
+}
+
+func (s *WebsiteServiceServer) GetIncomingWebhooks(ctx context.Context, req *website.GetIncomingWebhooksRequest) (*website.IncomingWebhooksResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetIncomingWebhooks", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	webhooks, err := s.incomingHooksUC.Execute(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, s.incomingWebhookStatusError(err, "list", req.RoomId)
+	}
+
+	response := &website.IncomingWebhooksResponse{
+		Webhooks: make([]*website.IncomingWebhook, len(webhooks)),
+	}
+	for i, webhook := range webhooks {
+		response.Webhooks[i] = incomingWebhookToProto(webhook)
+	}
+	return response, nil
+}
+
+func (s *WebsiteServiceServer) DeleteIncomingWebhook(ctx context.Context, req *website.DeleteIncomingWebhookRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("DeleteIncomingWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, webhookID, err := s.parseWebhookID(req.RoomId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deleteIncomingUC.Execute(ctx, roomID, webhookID, ownerID); err != nil {
+		return nil, s.incomingWebhookStatusError(err, "delete", req.RoomId)
+	}
+
+	s.logger.Info("Incoming webhook deleted",
+		zap.String("room_id", req.RoomId),
+		zap.String("webhook_id", req.WebhookId))
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebsiteServiceServer) ResolveIncomingWebhook(ctx context.Context, req *website.ResolveIncomingWebhookRequest) (*website.IncomingWebhook, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("ResolveIncomingWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	if !middleware.IsServiceContext(ctx) {
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "service token required")
+	}
+
+	webhook, err := s.resolveIncomingUC.Execute(ctx, req.Token)
+	if errors.Is(err, entities.ErrIncomingWebhookNotFound) {
+		s.metrics.RecordError("incoming_webhook_not_found")
+		return nil, status.Error(codes.NotFound, "incoming webhook not found")
+	}
+	if err != nil {
+		s.logger.Error("Failed to resolve incoming webhook", zap.Error(err))
+		s.metrics.RecordError("resolve_incoming_webhook_failed")
+		return nil, status.Error(codes.Internal, "failed to resolve incoming webhook")
+	}
+
+	return incomingWebhookToProto(webhook), nil
+}
+
+// incomingWebhookStatusError maps a failed incoming webhook operation to a gRPC status.
+func (s *WebsiteServiceServer) incomingWebhookStatusError(err error, action, roomID string) error {
+	var webhookErr *entities.WebhookError
+	switch {
+	case errors.As(err, &webhookErr):
+		s.metrics.RecordError("invalid_incoming_webhook")
+		return status.Error(codes.InvalidArgument, webhookErr.Error())
+	case errors.Is(err, entities.ErrRoomNotFound):
+		s.metrics.RecordError("room_not_found")
+		return status.Error(codes.NotFound, "room not found")
+	case errors.Is(err, entities.ErrIncomingWebhookNotFound):
+		s.metrics.RecordError("incoming_webhook_not_found")
+		return status.Error(codes.NotFound, "incoming webhook not found")
+	case errors.Is(err, entities.ErrRoomWebhookForbidden):
+		s.metrics.RecordError("permission_denied")
+		return status.Error(codes.PermissionDenied, "only the room owner can manage its webhooks")
+	case errors.Is(err, entities.ErrIncomingWebhookLimitReached):
+		s.metrics.RecordError("incoming_webhook_limit_reached")
+		return status.Errorf(codes.FailedPrecondition, "a room can have at most %d incoming webhooks", entities.MaxRoomIncomingWebhooks)
+	}
+
+	s.logger.Error("Failed to "+action+" incoming webhook",
+		zap.Error(err),
+		zap.String("room_id", roomID))
+	s.metrics.RecordError("incoming_webhook_failed")
+	return status.Error(codes.Internal, "failed to "+action+" incoming webhook")
+}
+
+func incomingWebhookToProto(webhook *entities.IncomingWebhook) *website.IncomingWebhook {
+	return &website.IncomingWebhook{
+		Id:        webhook.ID.String(),
+		RoomId:    webhook.RoomID.String(),
+		Name:      webhook.Name,
+		CreatedBy: webhook.CreatedBy.String(),
+		CreatedAt: timestamppb.New(webhook.CreatedAt),
+	}
+}
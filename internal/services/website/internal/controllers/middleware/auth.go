@@ -19,6 +19,7 @@ type ContextKey string
 const (
 	UserIDKey           ContextKey = "user_id"
 	PermissionsKey      ContextKey = "permissions"
+	ServiceKey          ContextKey = "service"
 	AuthorizationHeader            = "authorization"
 	BearerPrefix                   = "Bearer "
 )
@@ -29,21 +30,32 @@ var publicEndpoints = map[string]bool{
 	"/website.RoomService/GetAllRooms": true,
 }
 
+// serviceEndpoints can only be called by other services with the service token.
+var serviceEndpoints = map[string]bool{
+	"/website.RoomService/GetEnabledScripts":      true,
+	"/website.RoomService/PublishRoomEvent":       true,
+	"/website.RoomService/ResolveIncomingWebhook": true,
+	"/website.RoomService/GetRetentionPolicies":   true,
+}
+
 type AuthMiddleware struct {
-	logger     *zap.Logger
-	authClient *auth.AuthClient
-	metrics    *metrics.WebsiteMetrics
+	logger       *zap.Logger
+	authClient   *auth.AuthClient
+	metrics      *metrics.WebsiteMetrics
+	serviceToken string
 }
 
 func NewAuthMiddleware(
 	logger *zap.Logger,
 	authClient *auth.AuthClient,
 	metrics *metrics.WebsiteMetrics,
+	serviceToken string,
 ) *AuthMiddleware {
 	return &AuthMiddleware{
-		logger:     logger,
-		authClient: authClient,
-		metrics:    metrics,
+		logger:       logger,
+		authClient:   authClient,
+		metrics:      metrics,
+		serviceToken: serviceToken,
 	}
 }
 
@@ -58,13 +70,14 @@ func (m *AuthMiddleware) UnaryInterceptor() grpc.UnaryServerInterceptor {
 		m.metrics.IncActiveRequests()
 		defer m.metrics.DecActiveRequests()
 
-		// Skip auth for public endpoints.
-		if publicEndpoints[info.FullMethod] {
-			return handler(ctx, req)
-		}
-
 		token, err := m.extractToken(ctx)
 		if err != nil {
+			// Public endpoints are open to anonymous callers. Callers presenting
+			// a token are authenticated, so that they see what it grants them.
+			if publicEndpoints[info.FullMethod] {
+				return handler(ctx, req)
+			}
+
 			m.logger.Debug("Failed to extract token",
 				zap.String("method", info.FullMethod),
 				zap.Error(err))
@@ -72,6 +85,15 @@ func (m *AuthMiddleware) UnaryInterceptor() grpc.UnaryServerInterceptor {
 			return nil, err
 		}
 
+		// Other services authenticate with the shared service token.
+		if m.serviceToken != "" && token == m.serviceToken {
+			return handler(context.WithValue(ctx, ServiceKey, true), req)
+		}
+		if serviceEndpoints[info.FullMethod] {
+			m.metrics.RecordError("permission_denied")
+			return nil, status.Error(codes.PermissionDenied, "service token required")
+		}
+
 		// Validate token and get user info.
 		validationResp, err := m.authClient.ValidateToken(ctx, token)
 		if err != nil {
@@ -124,6 +146,12 @@ func GetUserIDFromContext(ctx context.Context) (string, error) {
 	return userID, nil
 }
 
+// IsServiceContext reports whether the request was made by another service.
+func IsServiceContext(ctx context.Context) bool {
+	service, _ := ctx.Value(ServiceKey).(bool)
+	return service
+}
+
 func GetPermissionsFromContext(ctx context.Context) ([]string, error) {
 	permissions, ok := ctx.Value(PermissionsKey).([]string)
 	if !ok {
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/website"
+	"github.com/HexArch/go-chat/internal/services/website/internal/controllers/middleware"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *WebsiteServiceServer) SetRetentionPolicy(ctx context.Context, req *website.SetRetentionPolicyRequest) (*website.RetentionPolicy, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("SetRetentionPolicy", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	policy, err := s.setRetentionUC.Execute(ctx, ownerID, &entities.RetentionPolicy{
+		RoomID:   roomID,
+		Days:     int(req.Days),
+		Messages: int(req.Messages),
+	})
+	if err != nil {
+		return nil, s.retentionStatusError(err, "set", req.RoomId)
+	}
+
+	s.logger.Info("Retention policy set",
+		zap.String("room_id", req.RoomId),
+		zap.Int("days", policy.Days),
+		zap.Int("messages", policy.Messages),
+		zap.String("owner_id", ownerID.String()))
+
+	return retentionPolicyToProto(policy), nil
+}
+
+func (s *WebsiteServiceServer) GetRetentionPolicy(ctx context.Context, req *website.GetRetentionPolicyRequest) (*website.RetentionPolicy, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetRetentionPolicy", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	policy, err := s.getRetentionUC.Execute(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, s.retentionStatusError(err, "get", req.RoomId)
+	}
+
+	return retentionPolicyToProto(policy), nil
+}
+
+func (s *WebsiteServiceServer) GetRetentionPolicies(ctx context.Context, req *website.GetRetentionPoliciesRequest) (*website.RetentionPoliciesResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetRetentionPolicies", "success", time.Since(start).Seconds())
+	}()
+
+	if !middleware.IsServiceContext(ctx) {
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "service token required")
+	}
+
+	policies, err := s.retentionListUC.Execute(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get retention policies", zap.Error(err))
+		s.metrics.RecordError("get_retention_policies_failed")
+		return nil, status.Error(codes.Internal, "failed to get retention policies")
+	}
+
+	response := &website.RetentionPoliciesResponse{
+		Policies: make([]*website.RetentionPolicy, len(policies)),
+	}
+	for i, policy := range policies {
+		response.Policies[i] = retentionPolicyToProto(policy)
+	}
+	return response, nil
+}
+
+// retentionStatusError maps a failed retention policy operation to a gRPC status.
+func (s *WebsiteServiceServer) retentionStatusError(err error, action, roomID string) error {
+	var retentionErr *entities.RetentionError
+	switch {
+	case errors.As(err, &retentionErr):
+		s.metrics.RecordError("invalid_retention_policy")
+		return status.Error(codes.InvalidArgument, retentionErr.Error())
+	case errors.Is(err, entities.ErrRoomNotFound):
+		s.metrics.RecordError("room_not_found")
+		return status.Error(codes.NotFound, "room not found")
+	case errors.Is(err, entities.ErrRetentionForbidden):
+		s.metrics.RecordError("permission_denied")
+		return status.Error(codes.PermissionDenied, "only the room owner can manage its retention policy")
+	}
+
+	s.logger.Error("Failed to "+action+" retention policy",
+		zap.Error(err),
+		zap.String("room_id", roomID))
+	s.metrics.RecordError("retention_policy_failed")
+	return status.Error(codes.Internal, "failed to "+action+" retention policy")
+}
+
+func retentionPolicyToProto(policy *entities.RetentionPolicy) *website.RetentionPolicy {
+	pb := &website.RetentionPolicy{
+		RoomId:   policy.RoomID.String(),
+		Days:     int32(policy.Days),
+		Messages: int32(policy.Messages),
+	}
+	if policy.UpdatedBy != uuid.Nil {
+		pb.UpdatedBy = policy.UpdatedBy.String()
+		pb.UpdatedAt = timestamppb.New(policy.UpdatedAt)
+	}
+	return pb
+}
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/website"
+	"github.com/HexArch/go-chat/internal/services/website/internal/controllers/middleware"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *WebsiteServiceServer) CreateRoomScript(ctx context.Context, req *website.CreateRoomScriptRequest) (*website.RoomScript, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("CreateRoomScript", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	script, err := s.createScriptUC.Execute(ctx, ownerID, &entities.RoomScript{
+		RoomID:           roomID,
+		Name:             req.Name,
+		Source:           req.Source,
+		Enabled:          req.Enabled,
+		ScheduleInterval: time.Duration(req.ScheduleSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, s.scriptStatusError(err, "create", req.RoomId)
+	}
+
+	s.logger.Info("Room script created",
+		zap.String("room_id", req.RoomId),
+		zap.String("script_id", script.ID.String()),
+		zap.String("owner_id", ownerID.String()))
+
+	return roomScriptToProto(script), nil
+}
+
+func (s *WebsiteServiceServer) UpdateRoomScript(ctx context.Context, req *website.UpdateRoomScriptRequest) (*website.RoomScript, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("UpdateRoomScript", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	scriptID, err := uuid.Parse(req.ScriptId)
+	if err != nil {
+		s.metrics.RecordError("invalid_script_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid script ID format")
+	}
+
+	script, err := s.updateScriptUC.Execute(ctx, ownerID, &entities.RoomScript{
+		ID:               scriptID,
+		RoomID:           roomID,
+		Name:             req.Name,
+		Source:           req.Source,
+		Enabled:          req.Enabled,
+		ScheduleInterval: time.Duration(req.ScheduleSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, s.scriptStatusError(err, "update", req.RoomId)
+	}
+
+	s.logger.Info("Room script updated",
+		zap.String("room_id", req.RoomId),
+		zap.String("script_id", req.ScriptId),
+		zap.Bool("enabled", script.Enabled))
+
+	return roomScriptToProto(script), nil
+}
+
+func (s *WebsiteServiceServer) DeleteRoomScript(ctx context.Context, req *website.DeleteRoomScriptRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("DeleteRoomScript", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	scriptID, err := uuid.Parse(req.ScriptId)
+	if err != nil {
+		s.metrics.RecordError("invalid_script_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid script ID format")
+	}
+
+	if err := s.deleteScriptUC.Execute(ctx, roomID, scriptID, ownerID); err != nil {
+		return nil, s.scriptStatusError(err, "delete", req.RoomId)
+	}
+
+	s.logger.Info("Room script deleted",
+		zap.String("room_id", req.RoomId),
+		zap.String("script_id", req.ScriptId))
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebsiteServiceServer) GetRoomScripts(ctx context.Context, req *website.GetRoomScriptsRequest) (*website.RoomScriptsResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetRoomScripts", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	scripts, err := s.roomScriptsUC.Execute(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, s.scriptStatusError(err, "list", req.RoomId)
+	}
+
+	return roomScriptsToProto(scripts), nil
+}
+
+func (s *WebsiteServiceServer) GetEnabledScripts(ctx context.Context, req *website.GetEnabledScriptsRequest) (*website.RoomScriptsResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetEnabledScripts", "success", time.Since(start).Seconds())
+	}()
+
+	if !middleware.IsServiceContext(ctx) {
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "service token required")
+	}
+
+	var roomID *uuid.UUID
+	if req.RoomId != "" {
+		id, err := uuid.Parse(req.RoomId)
+		if err != nil {
+			s.metrics.RecordError("invalid_room_id")
+			return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+		}
+		roomID = &id
+	}
+
+	scripts, err := s.enabledScriptsUC.Execute(ctx, roomID)
+	if err != nil {
+		s.logger.Error("Failed to get enabled scripts",
+			zap.Error(err),
+			zap.String("room_id", req.RoomId))
+		s.metrics.RecordError("get_enabled_scripts_failed")
+		return nil, status.Error(codes.Internal, "failed to fetch scripts")
+	}
+
+	return roomScriptsToProto(scripts), nil
+}
+
+// roomRequester returns the ID of the user managing room scripts or webhooks.
+func (s *WebsiteServiceServer) roomRequester(ctx context.Context) (uuid.UUID, error) {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		s.metrics.RecordError("unauthorized")
+		return uuid.Nil, status.Error(codes.Unauthenticated, "unauthorized access")
+	}
+
+	requesterID, err := uuid.Parse(userID)
+	if err != nil {
+		s.metrics.RecordError("unauthorized")
+		return uuid.Nil, status.Error(codes.Unauthenticated, "unauthorized access")
+	}
+	return requesterID, nil
+}
+
+// scriptStatusError maps a failed script operation to a gRPC status.
+func (s *WebsiteServiceServer) scriptStatusError(err error, action, roomID string) error {
+	var scriptErr *entities.ScriptError
+	switch {
+	case errors.As(err, &scriptErr):
+		s.metrics.RecordError("invalid_script")
+		return status.Error(codes.InvalidArgument, scriptErr.Error())
+	case errors.Is(err, entities.ErrRoomNotFound):
+		s.metrics.RecordError("room_not_found")
+		return status.Error(codes.NotFound, "room not found")
+	case errors.Is(err, entities.ErrScriptNotFound):
+		s.metrics.RecordError("script_not_found")
+		return status.Error(codes.NotFound, "script not found")
+	case errors.Is(err, entities.ErrRoomScriptForbidden):
+		s.metrics.RecordError("permission_denied")
+		return status.Error(codes.PermissionDenied, "only the room owner can manage its scripts")
+	case errors.Is(err, entities.ErrScriptLimitReached):
+		s.metrics.RecordError("script_limit_reached")
+		return status.Errorf(codes.FailedPrecondition, "a room can have at most %d scripts", entities.MaxRoomScripts)
+	}
+
+	s.logger.Error("Failed to "+action+" room script",
+		zap.Error(err),
+		zap.String("room_id", roomID))
+	s.metrics.RecordError(action + "_room_script_failed")
+	return status.Error(codes.Internal, "failed to "+action+" room script")
+}
+
+// roomScriptToProto converts a room script into its protobuf form.
+func roomScriptToProto(script *entities.RoomScript) *website.RoomScript {
+	return &website.RoomScript{
+		Id:              script.ID.String(),
+		RoomId:          script.RoomID.String(),
+		Name:            script.Name,
+		Source:          script.Source,
+		Enabled:         script.Enabled,
+		ScheduleSeconds: int32(script.ScheduleInterval / time.Second),
+		CreatedAt:       timestamppb.New(script.CreatedAt),
+		UpdatedAt:       timestamppb.New(script.UpdatedAt),
+	}
+}
+
+func roomScriptsToProto(scripts []*entities.RoomScript) *website.RoomScriptsResponse {
+	response := &website.RoomScriptsResponse{
+		Scripts: make([]*website.RoomScript, len(scripts)),
+	}
+	for i, script := range scripts {
+		response.Scripts[i] = roomScriptToProto(script)
+	}
+	return response
+}
@@ -92,7 +92,7 @@ func (s *Server) startGRPCServer(ctx context.Context) error {
 	}
 
 	// Create middleware.
-	authMiddleware := middleware.NewAuthMiddleware(s.logger, s.authClient, s.metrics)
+	authMiddleware := middleware.NewAuthMiddleware(s.logger, s.authClient, s.metrics, s.cfg.AuthService.ServiceToken)
 
 	// Create gRPC server.
 	s.grpcServer = grpc.NewServer(
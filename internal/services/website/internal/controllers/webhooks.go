@@ -0,0 +1,384 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/website"
+	"github.com/HexArch/go-chat/internal/services/website/internal/controllers/middleware"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *WebsiteServiceServer) CreateRoomWebhook(ctx context.Context, req *website.CreateRoomWebhookRequest) (*website.RoomWebhook, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("CreateRoomWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	webhook, err := s.createWebhookUC.Execute(ctx, ownerID, &entities.Webhook{
+		RoomID:  roomID,
+		URL:     req.Url,
+		Events:  req.Events,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		return nil, s.webhookStatusError(err, "create", req.RoomId)
+	}
+
+	s.logger.Info("Room webhook created",
+		zap.String("room_id", req.RoomId),
+		zap.String("webhook_id", webhook.ID.String()),
+		zap.String("owner_id", ownerID.String()))
+
+	return roomWebhookToProto(webhook, true), nil
+}
+
+func (s *WebsiteServiceServer) UpdateRoomWebhook(ctx context.Context, req *website.UpdateRoomWebhookRequest) (*website.RoomWebhook, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("UpdateRoomWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, webhookID, err := s.parseWebhookID(req.RoomId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.updateWebhookUC.Execute(ctx, ownerID, &entities.Webhook{
+		ID:      webhookID,
+		RoomID:  roomID,
+		URL:     req.Url,
+		Events:  req.Events,
+		Enabled: req.Enabled,
+	}, req.RotateSecret)
+	if err != nil {
+		return nil, s.webhookStatusError(err, "update", req.RoomId)
+	}
+
+	s.logger.Info("Room webhook updated",
+		zap.String("room_id", req.RoomId),
+		zap.String("webhook_id", req.WebhookId),
+		zap.Bool("enabled", webhook.Enabled),
+		zap.Bool("secret_rotated", req.RotateSecret))
+
+	return roomWebhookToProto(webhook, req.RotateSecret), nil
+}
+
+func (s *WebsiteServiceServer) DeleteRoomWebhook(ctx context.Context, req *website.DeleteRoomWebhookRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("DeleteRoomWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, webhookID, err := s.parseWebhookID(req.RoomId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deleteWebhookUC.Execute(ctx, roomID, webhookID, ownerID); err != nil {
+		return nil, s.webhookStatusError(err, "delete", req.RoomId)
+	}
+
+	s.logger.Info("Room webhook deleted",
+		zap.String("room_id", req.RoomId),
+		zap.String("webhook_id", req.WebhookId))
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebsiteServiceServer) GetRoomWebhooks(ctx context.Context, req *website.GetRoomWebhooksRequest) (*website.RoomWebhooksResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetRoomWebhooks", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	webhooks, err := s.roomWebhooksUC.Execute(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, s.webhookStatusError(err, "list", req.RoomId)
+	}
+
+	response := &website.RoomWebhooksResponse{
+		Webhooks: make([]*website.RoomWebhook, len(webhooks)),
+	}
+	for i, webhook := range webhooks {
+		response.Webhooks[i] = roomWebhookToProto(webhook, false)
+	}
+	return response, nil
+}
+
+func (s *WebsiteServiceServer) GetWebhookDeliveries(ctx context.Context, req *website.GetWebhookDeliveriesRequest) (*website.WebhookDeliveriesResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetWebhookDeliveries", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, webhookID, err := s.parseWebhookID(req.RoomId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.deliveriesUC.Execute(ctx, roomID, webhookID, ownerID, int(req.Limit))
+	if err != nil {
+		return nil, s.webhookStatusError(err, "list deliveries of", req.RoomId)
+	}
+
+	response := &website.WebhookDeliveriesResponse{
+		Attempts: make([]*website.WebhookAttempt, len(attempts)),
+	}
+	for i, attempt := range attempts {
+		response.Attempts[i] = &website.WebhookAttempt{
+			Id:         attempt.ID.String(),
+			DeliveryId: attempt.DeliveryID.String(),
+			Event:      attempt.Event,
+			Attempt:    int32(attempt.Attempt),
+			StatusCode: int32(attempt.StatusCode),
+			Error:      attempt.Error,
+			DurationMs: attempt.Duration.Milliseconds(),
+			CreatedAt:  timestamppb.New(attempt.CreatedAt),
+		}
+	}
+	return response, nil
+}
+
+func (s *WebsiteServiceServer) GetWebhookDeadLetters(ctx context.Context, req *website.GetWebhookDeadLettersRequest) (*website.WebhookDeadLettersResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetWebhookDeadLetters", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, webhookID, err := s.parseWebhookID(req.RoomId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters, err := s.deadLettersUC.Execute(ctx, roomID, webhookID, ownerID, int(req.Limit))
+	if err != nil {
+		return nil, s.webhookStatusError(err, "list dead letters of", req.RoomId)
+	}
+
+	response := &website.WebhookDeadLettersResponse{
+		DeadLetters: make([]*website.WebhookDeadLetter, len(deadLetters)),
+	}
+	for i, deadLetter := range deadLetters {
+		response.DeadLetters[i] = &website.WebhookDeadLetter{
+			Id:        deadLetter.ID.String(),
+			Event:     deadLetter.Event,
+			Payload:   string(deadLetter.Payload),
+			Attempts:  int32(deadLetter.Attempts),
+			LastError: deadLetter.LastError,
+			CreatedAt: timestamppb.New(deadLetter.CreatedAt),
+			FailedAt:  timestamppb.New(deadLetter.FailedAt),
+		}
+	}
+	return response, nil
+}
+
+func (s *WebsiteServiceServer) RedeliverWebhook(ctx context.Context, req *website.RedeliverWebhookRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("RedeliverWebhook", "success", time.Since(start).Seconds())
+	}()
+
+	ownerID, err := s.roomRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roomID, webhookID, err := s.parseWebhookID(req.RoomId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterID, err := uuid.Parse(req.DeadLetterId)
+	if err != nil {
+		s.metrics.RecordError("invalid_dead_letter_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid dead letter ID format")
+	}
+
+	if err := s.redeliverUC.Execute(ctx, roomID, webhookID, deadLetterID, ownerID); err != nil {
+		return nil, s.webhookStatusError(err, "redeliver", req.RoomId)
+	}
+
+	s.logger.Info("Webhook dead letter redelivered",
+		zap.String("room_id", req.RoomId),
+		zap.String("webhook_id", req.WebhookId),
+		zap.String("dead_letter_id", req.DeadLetterId))
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebsiteServiceServer) PublishRoomEvent(ctx context.Context, req *website.PublishRoomEventRequest) (*website.PublishRoomEventResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("PublishRoomEvent", "success", time.Since(start).Seconds())
+	}()
+
+	if !middleware.IsServiceContext(ctx) {
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "service token required")
+	}
+
+	roomID, err := uuid.Parse(req.RoomId)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	eventID, err := uuid.Parse(req.EventId)
+	if err != nil {
+		s.metrics.RecordError("invalid_event_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID format")
+	}
+
+	data := json.RawMessage(req.Data)
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	if !json.Valid(data) {
+		s.metrics.RecordError("invalid_event_data")
+		return nil, status.Error(codes.InvalidArgument, "event data must be JSON")
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		occurredAt = req.OccurredAt.AsTime()
+	}
+
+	subscribed, err := s.publishRoomEvtUC.Execute(ctx, &entities.WebhookEvent{
+		ID:         eventID,
+		RoomID:     roomID,
+		Type:       req.Event,
+		Data:       data,
+		OccurredAt: occurredAt,
+	})
+	var webhookErr *entities.WebhookError
+	if errors.As(err, &webhookErr) {
+		s.metrics.RecordError("invalid_event")
+		return nil, status.Error(codes.InvalidArgument, webhookErr.Error())
+	}
+	if err != nil {
+		s.logger.Error("Failed to publish room event",
+			zap.Error(err),
+			zap.String("room_id", req.RoomId),
+			zap.String("event", req.Event))
+		s.metrics.RecordError("publish_room_event_failed")
+		return nil, status.Error(codes.Internal, "failed to publish room event")
+	}
+
+	return &website.PublishRoomEventResponse{Subscribed: subscribed}, nil
+}
+
+// parseWebhookID parses the room and webhook IDs addressing a webhook.
+func (s *WebsiteServiceServer) parseWebhookID(rawRoomID, rawWebhookID string) (uuid.UUID, uuid.UUID, error) {
+	roomID, err := uuid.Parse(rawRoomID)
+	if err != nil {
+		s.metrics.RecordError("invalid_room_id")
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid room ID format")
+	}
+
+	webhookID, err := uuid.Parse(rawWebhookID)
+	if err != nil {
+		s.metrics.RecordError("invalid_webhook_id")
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid webhook ID format")
+	}
+	return roomID, webhookID, nil
+}
+
+// webhookStatusError maps a failed webhook operation to a gRPC status.
+func (s *WebsiteServiceServer) webhookStatusError(err error, action, roomID string) error {
+	var webhookErr *entities.WebhookError
+	switch {
+	case errors.As(err, &webhookErr):
+		s.metrics.RecordError("invalid_webhook")
+		return status.Error(codes.InvalidArgument, webhookErr.Error())
+	case errors.Is(err, entities.ErrRoomNotFound):
+		s.metrics.RecordError("room_not_found")
+		return status.Error(codes.NotFound, "room not found")
+	case errors.Is(err, entities.ErrWebhookNotFound):
+		s.metrics.RecordError("webhook_not_found")
+		return status.Error(codes.NotFound, "webhook not found")
+	case errors.Is(err, entities.ErrDeadLetterNotFound):
+		s.metrics.RecordError("dead_letter_not_found")
+		return status.Error(codes.NotFound, "dead letter not found")
+	case errors.Is(err, entities.ErrRoomWebhookForbidden):
+		s.metrics.RecordError("permission_denied")
+		return status.Error(codes.PermissionDenied, "only the room owner can manage its webhooks")
+	case errors.Is(err, entities.ErrWebhookLimitReached):
+		s.metrics.RecordError("webhook_limit_reached")
+		return status.Errorf(codes.FailedPrecondition, "a room can have at most %d webhooks", entities.MaxRoomWebhooks)
+	}
+
+	s.logger.Error("Failed to "+action+" room webhook",
+		zap.Error(err),
+		zap.String("room_id", roomID))
+	s.metrics.RecordError("room_webhook_failed")
+	return status.Error(codes.Internal, "failed to "+action+" room webhook")
+}
+
+// roomWebhookToProto converts a room webhook into its protobuf form. The secret
+// is only included when withSecret is set.
+func roomWebhookToProto(webhook *entities.Webhook, withSecret bool) *website.RoomWebhook {
+	proto := &website.RoomWebhook{
+		Id:        webhook.ID.String(),
+		RoomId:    webhook.RoomID.String(),
+		Url:       webhook.URL,
+		Events:    webhook.Events,
+		Enabled:   webhook.Enabled,
+		CreatedAt: timestamppb.New(webhook.CreatedAt),
+		UpdatedAt: timestamppb.New(webhook.UpdatedAt),
+	}
+	if withSecret {
+		proto.Secret = webhook.Secret
+	}
+	return proto
+}
@@ -7,14 +7,39 @@ import (
 	"github.com/HexArch/go-chat/internal/api/generated/go-chat/api/proto/website"
 	"github.com/HexArch/go-chat/internal/services/website/internal/controllers/cache"
 	"github.com/HexArch/go-chat/internal/services/website/internal/controllers/middleware"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
 	"github.com/HexArch/go-chat/internal/services/website/internal/metrics"
+	createGroupRoomUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-group-room"
+	createIncomingWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-incoming-webhook"
 	createRoomUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-room"
+	createRoomScriptUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-room-script"
+	createRoomWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/create-room-webhook"
+	deleteIncomingWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-incoming-webhook"
 	deleteRoomUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-room"
+	deleteRoomScriptUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-room-script"
+	deleteRoomWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/delete-room-webhook"
 	getallrooms "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-all-rooms"
+	getEnabledScriptsUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-enabled-scripts"
+	getIncomingWebhooksUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-incoming-webhooks"
+	getMemberRoomsUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-member-rooms"
+	getOrCreateDirectRoomUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-or-create-direct-room"
 	getOwnerRoomsUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-owner-rooms"
+	getRetentionPoliciesUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-retention-policies"
+	getRetentionPolicyUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-retention-policy"
 	getRoomUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-room"
+	getRoomScriptsUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-room-scripts"
+	getRoomWebhooksUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-room-webhooks"
+	getWebhookDeadLettersUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-webhook-dead-letters"
+	getWebhookDeliveriesUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/get-webhook-deliveries"
+	publishRoomEventUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/publish-room-event"
+	redeliverWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/redeliver-webhook"
+	resolveIncomingWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/resolve-incoming-webhook"
 	searchRoomsUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/search-rooms"
+	setRetentionPolicyUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/set-retention-policy"
+	updateRoomScriptUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/update-room-script"
+	updateRoomWebhookUC "github.com/HexArch/go-chat/internal/services/website/internal/use-cases/update-room-webhook"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,6 +58,34 @@ type WebsiteServiceServer struct {
 	getOwnerRoomsUC *getOwnerRoomsUC.UseCase
 	searchRoomsUC   *searchRoomsUC.UseCase
 	getAllRoomsUC   *getallrooms.UseCase
+	directRoomUC    *getOrCreateDirectRoomUC.UseCase
+	groupRoomUC     *createGroupRoomUC.UseCase
+	memberRoomsUC   *getMemberRoomsUC.UseCase
+
+	createScriptUC   *createRoomScriptUC.UseCase
+	updateScriptUC   *updateRoomScriptUC.UseCase
+	deleteScriptUC   *deleteRoomScriptUC.UseCase
+	roomScriptsUC    *getRoomScriptsUC.UseCase
+	enabledScriptsUC *getEnabledScriptsUC.UseCase
+
+	createWebhookUC  *createRoomWebhookUC.UseCase
+	updateWebhookUC  *updateRoomWebhookUC.UseCase
+	deleteWebhookUC  *deleteRoomWebhookUC.UseCase
+	roomWebhooksUC   *getRoomWebhooksUC.UseCase
+	deliveriesUC     *getWebhookDeliveriesUC.UseCase
+	deadLettersUC    *getWebhookDeadLettersUC.UseCase
+	redeliverUC      *redeliverWebhookUC.UseCase
+	publishRoomEvtUC *publishRoomEventUC.UseCase
+
+	createIncomingUC  *createIncomingWebhookUC.UseCase
+	incomingHooksUC   *getIncomingWebhooksUC.UseCase
+	deleteIncomingUC  *deleteIncomingWebhookUC.UseCase
+	resolveIncomingUC *resolveIncomingWebhookUC.UseCase
+	incomingHookURL   string // Chat service URL incoming webhooks post to, without the token.
+
+	setRetentionUC  *setRetentionPolicyUC.UseCase
+	getRetentionUC  *getRetentionPolicyUC.UseCase
+	retentionListUC *getRetentionPoliciesUC.UseCase
 }
 
 func NewWebsiteServiceServer(
@@ -45,6 +98,30 @@ func NewWebsiteServiceServer(
 	getOwnerRoomsUC *getOwnerRoomsUC.UseCase,
 	searchRoomsUC *searchRoomsUC.UseCase,
 	getAllRoomsUC *getallrooms.UseCase,
+	directRoomUC *getOrCreateDirectRoomUC.UseCase,
+	groupRoomUC *createGroupRoomUC.UseCase,
+	memberRoomsUC *getMemberRoomsUC.UseCase,
+	createScriptUC *createRoomScriptUC.UseCase,
+	updateScriptUC *updateRoomScriptUC.UseCase,
+	deleteScriptUC *deleteRoomScriptUC.UseCase,
+	roomScriptsUC *getRoomScriptsUC.UseCase,
+	enabledScriptsUC *getEnabledScriptsUC.UseCase,
+	createWebhookUC *createRoomWebhookUC.UseCase,
+	updateWebhookUC *updateRoomWebhookUC.UseCase,
+	deleteWebhookUC *deleteRoomWebhookUC.UseCase,
+	roomWebhooksUC *getRoomWebhooksUC.UseCase,
+	deliveriesUC *getWebhookDeliveriesUC.UseCase,
+	deadLettersUC *getWebhookDeadLettersUC.UseCase,
+	redeliverUC *redeliverWebhookUC.UseCase,
+	publishRoomEvtUC *publishRoomEventUC.UseCase,
+	createIncomingUC *createIncomingWebhookUC.UseCase,
+	incomingHooksUC *getIncomingWebhooksUC.UseCase,
+	deleteIncomingUC *deleteIncomingWebhookUC.UseCase,
+	resolveIncomingUC *resolveIncomingWebhookUC.UseCase,
+	incomingHookURL string,
+	setRetentionUC *setRetentionPolicyUC.UseCase,
+	getRetentionUC *getRetentionPolicyUC.UseCase,
+	retentionListUC *getRetentionPoliciesUC.UseCase,
 ) *WebsiteServiceServer {
 	return &WebsiteServiceServer{
 		logger:          logger,
@@ -56,6 +133,34 @@ func NewWebsiteServiceServer(
 		getOwnerRoomsUC: getOwnerRoomsUC,
 		searchRoomsUC:   searchRoomsUC,
 		getAllRoomsUC:   getAllRoomsUC,
+		directRoomUC:    directRoomUC,
+		groupRoomUC:     groupRoomUC,
+		memberRoomsUC:   memberRoomsUC,
+
+		createScriptUC:   createScriptUC,
+		updateScriptUC:   updateScriptUC,
+		deleteScriptUC:   deleteScriptUC,
+		roomScriptsUC:    roomScriptsUC,
+		enabledScriptsUC: enabledScriptsUC,
+
+		createWebhookUC:  createWebhookUC,
+		updateWebhookUC:  updateWebhookUC,
+		deleteWebhookUC:  deleteWebhookUC,
+		roomWebhooksUC:   roomWebhooksUC,
+		deliveriesUC:     deliveriesUC,
+		deadLettersUC:    deadLettersUC,
+		redeliverUC:      redeliverUC,
+		publishRoomEvtUC: publishRoomEvtUC,
+
+		createIncomingUC:  createIncomingUC,
+		incomingHooksUC:   incomingHooksUC,
+		deleteIncomingUC:  deleteIncomingUC,
+		resolveIncomingUC: resolveIncomingUC,
+		incomingHookURL:   incomingHookURL,
+
+		setRetentionUC:  setRetentionUC,
+		getRetentionUC:  getRetentionUC,
+		retentionListUC: retentionListUC,
 	}
 }
 
@@ -109,13 +214,7 @@ func (s *WebsiteServiceServer) CreateRoom(ctx context.Context, req *website.Crea
 		zap.String("owner_id", room.OwnerID.String()))
 
 	return &website.CreateRoomResponse{
-		Room: &website.Room{
-			Id:        room.ID.String(),
-			Name:      room.Name,
-			OwnerId:   room.OwnerID.String(),
-			CreatedAt: timestamppb.New(room.CreatedAt),
-			UpdatedAt: timestamppb.New(room.UpdatedAt),
-		},
+		Room: roomToProto(room),
 	}, nil
 }
 
@@ -153,13 +252,7 @@ func (s *WebsiteServiceServer) GetAllRooms(ctx context.Context, req *website.Get
 	}
 
 	for i, room := range rooms {
-		response.Rooms[i] = &website.Room{
-			Id:        room.ID.String(),
-			Name:      room.Name,
-			OwnerId:   room.OwnerID.String(),
-			CreatedAt: timestamppb.New(room.CreatedAt),
-			UpdatedAt: timestamppb.New(room.UpdatedAt),
-		}
+		response.Rooms[i] = roomToProto(room)
 	}
 
 	return response, nil
@@ -178,36 +271,52 @@ func (s *WebsiteServiceServer) GetRoom(ctx context.Context, req *website.GetRoom
 	}
 
 	// Check cache first.
-	if room, found := s.roomCache.Get(roomID); found {
+	room, found := s.roomCache.Get(roomID)
+	if found {
 		s.metrics.RecordCacheHit("room")
-		return &website.Room{
-			Id:        room.ID.String(),
-			Name:      room.Name,
-			OwnerId:   room.OwnerID.String(),
-			CreatedAt: timestamppb.New(room.CreatedAt),
-			UpdatedAt: timestamppb.New(room.UpdatedAt),
-		}, nil
+	} else {
+		room, err = s.getRoomUC.Execute(ctx, roomID)
+		if err != nil {
+			if errors.Is(err, entities.ErrRoomNotFound) {
+				s.metrics.RecordError("room_not_found")
+				return nil, status.Error(codes.NotFound, "room not found")
+			}
+			s.logger.Error("Failed to get room",
+				zap.Error(err),
+				zap.String("room_id", req.RoomId))
+			s.metrics.RecordError("get_room_failed")
+			return nil, status.Error(codes.Internal, "failed to fetch room")
+		}
+
+		// Cache the room for future requests.
+		s.roomCache.Set(room.ID, room)
 	}
 
-	room, err := s.getRoomUC.Execute(ctx, roomID)
-	if err != nil {
-		s.logger.Error("Failed to get room",
-			zap.Error(err),
-			zap.String("room_id", req.RoomId))
-		s.metrics.RecordError("get_room_failed")
-		return nil, status.Error(codes.Internal, "failed to fetch room")
+	// Direct and group rooms are only shown to their members and to other
+	// services, as if they did not exist for anyone else.
+	if !canViewRoom(ctx, room) {
+		s.metrics.RecordError("room_not_found")
+		return nil, status.Error(codes.NotFound, "room not found")
 	}
 
-	// Cache the room for future requests.
-	s.roomCache.Set(room.ID, room)
+	return roomToProto(room), nil
+}
 
-	return &website.Room{
-		Id:        room.ID.String(),
-		Name:      room.Name,
-		OwnerId:   room.OwnerID.String(),
-		CreatedAt: timestamppb.New(room.CreatedAt),
-		UpdatedAt: timestamppb.New(room.UpdatedAt),
-	}, nil
+// canViewRoom reports whether the caller may see the room and its members.
+func canViewRoom(ctx context.Context, room *entities.Room) bool {
+	if !room.IsPrivate() || middleware.IsServiceContext(ctx) {
+		return true
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return false
+	}
+	return room.IsMember(id)
 }
 
 func (s *WebsiteServiceServer) GetOwnerRooms(ctx context.Context, req *website.GetOwnerRoomsRequest) (*website.RoomsResponse, error) {
@@ -236,13 +345,7 @@ func (s *WebsiteServiceServer) GetOwnerRooms(ctx context.Context, req *website.G
 	}
 
 	for i, room := range rooms {
-		response.Rooms[i] = &website.Room{
-			Id:        room.ID.String(),
-			Name:      room.Name,
-			OwnerId:   room.OwnerID.String(),
-			CreatedAt: timestamppb.New(room.CreatedAt),
-			UpdatedAt: timestamppb.New(room.UpdatedAt),
-		}
+		response.Rooms[i] = roomToProto(room)
 	}
 
 	return response, nil
@@ -290,13 +393,7 @@ func (s *WebsiteServiceServer) SearchRooms(ctx context.Context, req *website.Sea
 	}
 
 	for i, room := range rooms {
-		response.Rooms[i] = &website.Room{
-			Id:        room.ID.String(),
-			Name:      room.Name,
-			OwnerId:   room.OwnerID.String(),
-			CreatedAt: timestamppb.New(room.CreatedAt),
-			UpdatedAt: timestamppb.New(room.UpdatedAt),
-		}
+		response.Rooms[i] = roomToProto(room)
 	}
 
 	return response, nil
@@ -353,3 +450,196 @@ func (s *WebsiteServiceServer) DeleteRoom(ctx context.Context, req *website.Dele
 
 	return &emptypb.Empty{}, nil
 }
+
+func (s *WebsiteServiceServer) GetOrCreateDirectRoom(ctx context.Context, req *website.GetOrCreateDirectRoomRequest) (*website.CreateRoomResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetOrCreateDirectRoom", "success", time.Since(start).Seconds())
+	}()
+
+	// Validate requester.
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		s.metrics.RecordError("unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "unauthorized access")
+	}
+
+	userA, err := uuid.Parse(req.UserA)
+	if err != nil {
+		s.metrics.RecordError("invalid_user_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	userB, err := uuid.Parse(req.UserB)
+	if err != nil {
+		s.metrics.RecordError("invalid_user_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	// Verify that requester is one of the two users, and make them the owner of a new room.
+	switch userID {
+	case req.UserA:
+	case req.UserB:
+		userA, userB = userB, userA
+	default:
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "can only open direct rooms you are part of")
+	}
+
+	room, err := s.directRoomUC.Execute(ctx, userA, userB)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidMembers) {
+			s.metrics.RecordError("invalid_room_members")
+			return nil, status.Error(codes.InvalidArgument, "a direct room needs two distinct users")
+		}
+		s.logger.Error("Failed to get or create direct room",
+			zap.Error(err),
+			zap.String("user_a", req.UserA),
+			zap.String("user_b", req.UserB))
+		s.metrics.RecordError("direct_room_failed")
+		return nil, status.Error(codes.Internal, "failed to get or create direct room")
+	}
+
+	s.roomCache.Set(room.ID, room)
+
+	return &website.CreateRoomResponse{
+		Room: roomToProto(room),
+	}, nil
+}
+
+func (s *WebsiteServiceServer) CreateGroupRoom(ctx context.Context, req *website.CreateGroupRoomRequest) (*website.CreateRoomResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("CreateGroupRoom", "success", time.Since(start).Seconds())
+	}()
+
+	// Validate requester.
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		s.metrics.RecordError("unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "unauthorized access")
+	}
+
+	ownerID, err := uuid.Parse(req.OwnerId)
+	if err != nil {
+		s.metrics.RecordError("invalid_owner_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid owner ID format")
+	}
+
+	// Verify that requester is the owner.
+	if userID != req.OwnerId {
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "can only create rooms for yourself")
+	}
+
+	// Validate room name.
+	if len(req.Name) < 3 || len(req.Name) > 50 {
+		s.metrics.RecordError("invalid_room_name")
+		return nil, status.Error(codes.InvalidArgument, "room name must be between 3 and 50 characters")
+	}
+
+	memberIDs := make([]uuid.UUID, len(req.MemberIds))
+	for i, rawID := range req.MemberIds {
+		if memberIDs[i], err = uuid.Parse(rawID); err != nil {
+			s.metrics.RecordError("invalid_member_id")
+			return nil, status.Error(codes.InvalidArgument, "invalid member ID format")
+		}
+	}
+
+	room, err := s.groupRoomUC.Execute(ctx, req.Name, ownerID, memberIDs)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidMembers) {
+			s.metrics.RecordError("invalid_room_members")
+			return nil, status.Errorf(codes.InvalidArgument, "a group room needs between 2 and %d members", entities.MaxGroupMembers)
+		}
+		s.logger.Error("Failed to create group room",
+			zap.Error(err),
+			zap.String("name", req.Name),
+			zap.String("owner_id", req.OwnerId))
+		s.metrics.RecordError("create_group_room_failed")
+		return nil, status.Error(codes.Internal, "failed to create group room")
+	}
+
+	s.metrics.RecordRoomCreation()
+	s.roomCache.Set(room.ID, room)
+
+	s.logger.Info("Group room created successfully",
+		zap.String("room_id", room.ID.String()),
+		zap.String("owner_id", room.OwnerID.String()),
+		zap.Int("members", len(room.MemberIDs)))
+
+	return &website.CreateRoomResponse{
+		Room: roomToProto(room),
+	}, nil
+}
+
+func (s *WebsiteServiceServer) GetMemberRooms(ctx context.Context, req *website.GetMemberRoomsRequest) (*website.RoomsResponse, error) {
+	start := time.Now()
+	defer func() {
+		s.metrics.RecordRequestDuration("GetMemberRooms", "success", time.Since(start).Seconds())
+	}()
+
+	// Validate requester.
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		s.metrics.RecordError("unauthorized")
+		return nil, status.Error(codes.Unauthenticated, "unauthorized access")
+	}
+
+	memberID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		s.metrics.RecordError("invalid_user_id")
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	// Private conversations are only listed to their own members.
+	if userID != req.UserId {
+		s.metrics.RecordError("permission_denied")
+		return nil, status.Error(codes.PermissionDenied, "can only list your own conversations")
+	}
+
+	rooms, err := s.memberRoomsUC.Execute(ctx, memberID)
+	if err != nil {
+		s.logger.Error("Failed to get member rooms",
+			zap.Error(err),
+			zap.String("user_id", req.UserId))
+		s.metrics.RecordError("get_member_rooms_failed")
+		return nil, status.Error(codes.Internal, "failed to fetch conversations")
+	}
+
+	response := &website.RoomsResponse{
+		Rooms: make([]*website.Room, len(rooms)),
+	}
+
+	for i, room := range rooms {
+		response.Rooms[i] = roomToProto(room)
+	}
+
+	return response, nil
+}
+
+// roomToProto converts a room into its protobuf form.
+func roomToProto(room *entities.Room) *website.Room {
+	pbRoom := &website.Room{
+		Id:        room.ID.String(),
+		Name:      room.Name,
+		OwnerId:   room.OwnerID.String(),
+		CreatedAt: timestamppb.New(room.CreatedAt),
+		UpdatedAt: timestamppb.New(room.UpdatedAt),
+	}
+
+	switch room.Kind {
+	case entities.RoomKindDirect:
+		pbRoom.Kind = website.RoomKind_ROOM_KIND_DIRECT
+	case entities.RoomKindGroup:
+		pbRoom.Kind = website.RoomKind_ROOM_KIND_GROUP
+	default:
+		pbRoom.Kind = website.RoomKind_ROOM_KIND_PUBLIC
+	}
+
+	for _, memberID := range room.MemberIDs {
+		pbRoom.MemberIds = append(pbRoom.MemberIds, memberID.String())
+	}
+
+	return pbRoom
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// IncomingWebhookTokenPrefix starts every incoming webhook token.
+	IncomingWebhookTokenPrefix = "whk_"
+	// MaxRoomIncomingWebhooks bounds the number of incoming webhooks of a room.
+	MaxRoomIncomingWebhooks = 10
+	// MaxIncomingWebhookNameLength bounds the name messages of an incoming webhook are posted under.
+	MaxIncomingWebhookNameLength = 50
+)
+
+// IncomingWebhook lets an integration post messages to a room over HTTP with a
+// secret token instead of a user account. Its messages are authored by its ID
+// and shown under its name. Only the hash of the token is stored.
+type IncomingWebhook struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	Name      string
+	TokenHash string
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+}
+
+// HashIncomingWebhookToken returns the hash under which an incoming webhook
+// token is stored. Tokens are random, so a plain SHA-256 keeps them secret at rest.
+func HashIncomingWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrIncomingWebhookNotFound is used when an incoming webhook or its token could not be found.
+var ErrIncomingWebhookNotFound = errors.New("incoming webhook not found")
+
+// ErrIncomingWebhookLimitReached is used when a room already has the maximum number of incoming webhooks.
+var ErrIncomingWebhookLimitReached = errors.New("room incoming webhook limit reached")
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxRetentionDays bounds the age limit of a retention policy.
+	MaxRetentionDays = 3650
+	// MaxRetentionMessages bounds the message limit of a retention policy.
+	MaxRetentionMessages = 1_000_000
+)
+
+// RetentionPolicy bounds how long the chat service keeps the messages of a
+// room: messages older than Days days and all but the newest Messages messages
+// are purged. A zero field sets no bound; a room without a policy keeps its
+// messages forever.
+type RetentionPolicy struct {
+	RoomID    uuid.UUID
+	Days      int
+	Messages  int
+	UpdatedBy uuid.UUID
+	UpdatedAt time.Time
+}
+
+// KeepsForever reports whether the policy purges nothing.
+func (p *RetentionPolicy) KeepsForever() bool {
+	return p.Days == 0 && p.Messages == 0
+}
+
+// RetentionError tells why a retention policy is invalid.
+type RetentionError struct {
+	Reason string
+}
+
+func (e *RetentionError) Error() string {
+	return "invalid retention policy: " + e.Reason
+}
+
+// ErrRetentionForbidden is used when a user other than the room owner manages its retention policy.
+var ErrRetentionForbidden = errors.New("only the room owner can manage its retention policy")
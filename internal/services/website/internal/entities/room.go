@@ -7,14 +7,50 @@ import (
 	"github.com/google/uuid"
 )
 
+// RoomKind tells who can see and join a room.
+type RoomKind string
+
+const (
+	// RoomKindPublic rooms are listed and searchable, and anyone can join them.
+	RoomKindPublic RoomKind = "public"
+	// RoomKindDirect rooms are private conversations between exactly two users.
+	RoomKindDirect RoomKind = "direct"
+	// RoomKindGroup rooms are private conversations between a small set of users.
+	RoomKindGroup RoomKind = "group"
+)
+
+// MaxGroupMembers bounds the size of a group conversation, owner included.
+const MaxGroupMembers = 20
+
 type Room struct {
 	ID        uuid.UUID
 	Name      string
 	OwnerID   uuid.UUID
+	Kind      RoomKind
+	MemberIDs []uuid.UUID // Only set for direct and group rooms.
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// IsPrivate reports whether only the members of the room can join it.
+func (r *Room) IsPrivate() bool {
+	return r.Kind == RoomKindDirect || r.Kind == RoomKindGroup
+}
+
+// IsMember reports whether the user can join the room.
+func (r *Room) IsMember(userID uuid.UUID) bool {
+	if !r.IsPrivate() {
+		return true
+	}
+
+	for _, memberID := range r.MemberIDs {
+		if memberID == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // ErrRoomNotFound is used when a room could not be found.
 var ErrRoomNotFound = errors.New("room not found")
 
@@ -23,3 +59,6 @@ var ErrRoomAlreadyExists = errors.New("room already exists")
 
 // ErrRoomDeleteForbidden is used when an unauthorized user tries to delete a room.
 var ErrRoomDeleteForbidden = errors.New("you are not allowed to delete this room")
+
+// ErrInvalidMembers is used when the members of a direct or group room are invalid.
+var ErrInvalidMembers = errors.New("invalid room members")
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxRoomScripts bounds the number of scripts attached to a room.
+	MaxRoomScripts = 5
+	// MaxScriptNameLength bounds the name of a script.
+	MaxScriptNameLength = 50
+	// MaxScriptSourceLength bounds the Starlark source of a script, in bytes.
+	MaxScriptSourceLength = 16 * 1024
+	// MinScheduleInterval is the shortest interval on_schedule may run at.
+	MinScheduleInterval = time.Minute
+	// MaxScheduleInterval is the longest interval on_schedule may run at.
+	MaxScheduleInterval = 7 * 24 * time.Hour
+)
+
+// RoomScript is a Starlark script the owner of a room attached to it. The
+// website service stores scripts; the chat service runs their hooks.
+type RoomScript struct {
+	ID               uuid.UUID
+	RoomID           uuid.UUID
+	Name             string
+	Source           string
+	Enabled          bool
+	ScheduleInterval time.Duration // How often on_schedule runs; zero never runs it.
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// ErrScriptNotFound is used when a script could not be found in its room.
+var ErrScriptNotFound = errors.New("script not found")
+
+// ErrInvalidScript is used when the name, source or schedule of a script is invalid.
+var ErrInvalidScript = errors.New("invalid script")
+
+// ScriptError tells why a script is invalid, e.g. where its source fails to compile.
+type ScriptError struct {
+	Reason string
+}
+
+func (e *ScriptError) Error() string {
+	return "invalid script: " + e.Reason
+}
+
+// Unwrap lets callers match any ScriptError with ErrInvalidScript.
+func (e *ScriptError) Unwrap() error {
+	return ErrInvalidScript
+}
+
+// ErrScriptLimitReached is used when a room already has the maximum number of scripts.
+var ErrScriptLimitReached = errors.New("room script limit reached")
+
+// ErrRoomScriptForbidden is used when a user other than the room owner manages its scripts.
+var ErrRoomScriptForbidden = errors.New("only the room owner can manage its scripts")
@@ -0,0 +1,10 @@
+package entities
+
+import "github.com/google/uuid"
+
+// User is an account of the Auth Service.
+type User struct {
+	ID       uuid.UUID
+	Email    string
+	Username string
+}
@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events a webhook can subscribe to.
+const (
+	WebhookEventMessageCreated = "message.created"
+	WebhookEventUserJoined     = "user.joined"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to.
+var WebhookEvents = []string{WebhookEventMessageCreated, WebhookEventUserJoined}
+
+const (
+	// MaxRoomWebhooks bounds the number of webhooks attached to a room.
+	MaxRoomWebhooks = 5
+	// MaxWebhookURLLength bounds the URL of a webhook.
+	MaxWebhookURLLength = 2048
+	// DefaultWebhookLogLimit is how many delivery log entries or dead letters are listed by default.
+	DefaultWebhookLogLimit = 50
+	// MaxWebhookLogLimit bounds how many delivery log entries or dead letters are listed at once.
+	MaxWebhookLogLimit = 200
+)
+
+// Webhook is an outgoing webhook the owner of a room attached to it: the
+// events it subscribes to are POSTed to its URL, signed with its secret.
+type Webhook struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	URL       string
+	Secret    string
+	Events    []string
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Subscribes reports whether the webhook receives the event.
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is an event of a room published by the chat service.
+type WebhookEvent struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Type       string
+	Data       json.RawMessage
+	OccurredAt time.Time
+}
+
+// WebhookPayload is the JSON body POSTed to webhooks.
+type WebhookPayload struct {
+	ID         uuid.UUID       `json:"id"`
+	Event      string          `json:"event"`
+	RoomID     uuid.UUID       `json:"room_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery is a payload waiting to be delivered to a webhook. Attempts
+// counts the attempts started so far.
+type WebhookDelivery struct {
+	ID            uuid.UUID
+	WebhookID     uuid.UUID
+	RoomID        uuid.UUID
+	Event         string
+	Payload       []byte
+	Attempts      int
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+
+	// URL and Secret of the webhook, set on claimed deliveries.
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt is an entry of the delivery log: one attempt to deliver a payload.
+type WebhookAttempt struct {
+	ID         uuid.UUID
+	DeliveryID uuid.UUID
+	WebhookID  uuid.UUID
+	Event      string
+	Attempt    int
+	StatusCode int    // Zero when no response was received.
+	Error      string // Empty when the attempt succeeded.
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
+
+// Succeeded reports whether the webhook accepted the payload.
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == ""
+}
+
+// WebhookDeadLetter is a delivery that failed permanently or ran out of
+// attempts. Its ID is the ID of the delivery.
+type WebhookDeadLetter struct {
+	ID        uuid.UUID
+	WebhookID uuid.UUID
+	RoomID    uuid.UUID
+	Event     string
+	Payload   []byte
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	FailedAt  time.Time
+}
+
+// ErrWebhookNotFound is used when a webhook could not be found in its room.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// ErrDeadLetterNotFound is used when a dead letter could not be found for its webhook.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// ErrInvalidWebhook is used when the URL or events of a webhook are invalid.
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// WebhookError tells why a webhook is invalid.
+type WebhookError struct {
+	Reason string
+}
+
+func (e *WebhookError) Error() string {
+	return "invalid webhook: " + e.Reason
+}
+
+// Unwrap lets callers match any WebhookError with ErrInvalidWebhook.
+func (e *WebhookError) Unwrap() error {
+	return ErrInvalidWebhook
+}
+
+// ErrWebhookLimitReached is used when a room already has the maximum number of webhooks.
+var ErrWebhookLimitReached = errors.New("room webhook limit reached")
+
+// ErrRoomWebhookForbidden is used when a user other than the room owner manages its webhooks.
+var ErrRoomWebhookForbidden = errors.New("only the room owner can manage its webhooks")
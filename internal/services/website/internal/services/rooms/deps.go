@@ -2,6 +2,7 @@ package rooms
 
 import (
 	"context"
+	"time"
 
 	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
 	"github.com/google/uuid"
@@ -14,6 +15,32 @@ type RoomStorage interface {
 	GetRoomsByName(ctx context.Context, name string, limit, offset int) ([]*entities.Room, error)
 	DeleteRoom(ctx context.Context, roomID uuid.UUID) error
 	GetAllRooms(ctx context.Context, limit, offset int) ([]*entities.Room, error)
+	GetOrCreateDirectRoom(ctx context.Context, room *entities.Room) (*entities.Room, error)
+	GetRoomsByMemberID(ctx context.Context, userID uuid.UUID) ([]*entities.Room, error)
+	CreateRoomScript(ctx context.Context, script *entities.RoomScript, maxScripts int) error
+	GetRoomScript(ctx context.Context, roomID, scriptID uuid.UUID) (*entities.RoomScript, error)
+	UpdateRoomScript(ctx context.Context, script *entities.RoomScript) error
+	DeleteRoomScript(ctx context.Context, roomID, scriptID uuid.UUID) error
+	GetRoomScripts(ctx context.Context, roomID uuid.UUID) ([]*entities.RoomScript, error)
+	GetEnabledScripts(ctx context.Context, roomID *uuid.UUID, scheduledOnly bool) ([]*entities.RoomScript, error)
+	CreateRoomWebhook(ctx context.Context, webhook *entities.Webhook, maxWebhooks int) error
+	GetRoomWebhook(ctx context.Context, roomID, webhookID uuid.UUID) (*entities.Webhook, error)
+	UpdateRoomWebhook(ctx context.Context, webhook *entities.Webhook) error
+	DeleteRoomWebhook(ctx context.Context, roomID, webhookID uuid.UUID) error
+	GetRoomWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.Webhook, error)
+	GetEnabledWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.Webhook, error)
+	CreateWebhookDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+	GetWebhookAttempts(ctx context.Context, webhookID uuid.UUID, limit int) ([]*entities.WebhookAttempt, error)
+	GetWebhookDeadLetters(ctx context.Context, webhookID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error)
+	RedeliverWebhookDeadLetter(ctx context.Context, webhookID, deadLetterID uuid.UUID, now time.Time) error
+	CreateIncomingWebhook(ctx context.Context, webhook *entities.IncomingWebhook, maxWebhooks int) error
+	GetIncomingWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.IncomingWebhook, error)
+	GetIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*entities.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, roomID, webhookID uuid.UUID) error
+	GetRetentionPolicy(ctx context.Context, roomID uuid.UUID) (*entities.RetentionPolicy, error)
+	SetRetentionPolicy(ctx context.Context, policy *entities.RetentionPolicy) error
+	DeleteRetentionPolicy(ctx context.Context, roomID uuid.UUID) error
+	GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
 }
 
 type Deps struct {
@@ -0,0 +1,93 @@
+package rooms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// incomingWebhookTokenSize is the number of random bytes in an incoming webhook token.
+const incomingWebhookTokenSize = 32
+
+// CreateIncomingWebhook creates an incoming webhook for a room and returns it
+// with its token. Only the hash of the token is kept, so it cannot be shown again.
+func (s *service) CreateIncomingWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.IncomingWebhook) (*entities.IncomingWebhook, string, error) {
+	if err := s.checkRoomOwner(ctx, webhook.RoomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return nil, "", err
+	}
+
+	name := strings.TrimSpace(webhook.Name)
+	if n := utf8.RuneCountInString(name); n < 1 || n > entities.MaxIncomingWebhookNameLength {
+		return nil, "", &entities.WebhookError{Reason: fmt.Sprintf("name must be between 1 and %d characters", entities.MaxIncomingWebhookNameLength)}
+	}
+
+	token, err := generateIncomingWebhookToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	created := &entities.IncomingWebhook{
+		ID:        uuid.New(),
+		RoomID:    webhook.RoomID,
+		Name:      name,
+		TokenHash: entities.HashIncomingWebhookToken(token),
+		CreatedBy: ownerID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.roomStorage.CreateIncomingWebhook(ctx, created, entities.MaxRoomIncomingWebhooks); err != nil {
+		return nil, "", errors.Wrap(err, "failed to create incoming webhook")
+	}
+	return created, token, nil
+}
+
+func (s *service) GetIncomingWebhooks(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.IncomingWebhook, error) {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.roomStorage.GetIncomingWebhooks(ctx, roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get incoming webhooks")
+	}
+	return webhooks, nil
+}
+
+func (s *service) DeleteIncomingWebhook(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return err
+	}
+
+	if err := s.roomStorage.DeleteIncomingWebhook(ctx, roomID, webhookID); err != nil {
+		return errors.Wrap(err, "failed to delete incoming webhook")
+	}
+	return nil
+}
+
+// ResolveIncomingWebhook returns the incoming webhook a token belongs to.
+func (s *service) ResolveIncomingWebhook(ctx context.Context, token string) (*entities.IncomingWebhook, error) {
+	if !strings.HasPrefix(token, entities.IncomingWebhookTokenPrefix) {
+		return nil, entities.ErrIncomingWebhookNotFound
+	}
+
+	webhook, err := s.roomStorage.GetIncomingWebhookByTokenHash(ctx, entities.HashIncomingWebhookToken(token))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve incoming webhook")
+	}
+	return webhook, nil
+}
+
+func generateIncomingWebhookToken() (string, error) {
+	b := make([]byte, incomingWebhookTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate incoming webhook token")
+	}
+	return entities.IncomingWebhookTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,72 @@
+package rooms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// SetRetentionPolicy replaces the retention policy of a room. A policy without
+// bounds removes it, so that the room keeps its messages forever.
+func (s *service) SetRetentionPolicy(ctx context.Context, ownerID uuid.UUID, policy *entities.RetentionPolicy) (*entities.RetentionPolicy, error) {
+	if err := s.checkRoomOwner(ctx, policy.RoomID, ownerID, entities.ErrRetentionForbidden); err != nil {
+		return nil, err
+	}
+	if policy.Days < 0 || policy.Days > entities.MaxRetentionDays {
+		return nil, &entities.RetentionError{Reason: fmt.Sprintf("days must be between 0 and %d", entities.MaxRetentionDays)}
+	}
+	if policy.Messages < 0 || policy.Messages > entities.MaxRetentionMessages {
+		return nil, &entities.RetentionError{Reason: fmt.Sprintf("messages must be between 0 and %d", entities.MaxRetentionMessages)}
+	}
+
+	updated := &entities.RetentionPolicy{
+		RoomID:    policy.RoomID,
+		Days:      policy.Days,
+		Messages:  policy.Messages,
+		UpdatedBy: ownerID,
+		UpdatedAt: time.Now(),
+	}
+
+	if updated.KeepsForever() {
+		if err := s.roomStorage.DeleteRetentionPolicy(ctx, policy.RoomID); err != nil {
+			return nil, errors.Wrap(err, "failed to delete retention policy")
+		}
+		return updated, nil
+	}
+
+	if err := s.roomStorage.SetRetentionPolicy(ctx, updated); err != nil {
+		return nil, errors.Wrap(err, "failed to set retention policy")
+	}
+	return updated, nil
+}
+
+// GetRetentionPolicy returns the retention policy of a room. Rooms without one
+// get a policy without bounds.
+func (s *service) GetRetentionPolicy(ctx context.Context, roomID, ownerID uuid.UUID) (*entities.RetentionPolicy, error) {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRetentionForbidden); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.roomStorage.GetRetentionPolicy(ctx, roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retention policy")
+	}
+	if policy == nil {
+		return &entities.RetentionPolicy{RoomID: roomID}, nil
+	}
+	return policy, nil
+}
+
+// GetRetentionPolicies returns the policies of every room that does not keep
+// its messages forever.
+func (s *service) GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	policies, err := s.roomStorage.GetRetentionPolicies(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retention policies")
+	}
+	return policies, nil
+}
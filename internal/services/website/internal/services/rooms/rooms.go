@@ -16,6 +16,29 @@ type Service interface {
 	SearchRooms(ctx context.Context, name string, limit, offset int) ([]*entities.Room, error)
 	DeleteRoom(ctx context.Context, roomID, ownerID uuid.UUID) error
 	GetAllRooms(ctx context.Context, limit, offset int) ([]*entities.Room, error)
+	GetOrCreateDirectRoom(ctx context.Context, userA, userB uuid.UUID) (*entities.Room, error)
+	CreateGroupRoom(ctx context.Context, name string, ownerID uuid.UUID, memberIDs []uuid.UUID) (*entities.Room, error)
+	GetMemberRooms(ctx context.Context, userID uuid.UUID) ([]*entities.Room, error)
+	CreateRoomScript(ctx context.Context, ownerID uuid.UUID, script *entities.RoomScript) (*entities.RoomScript, error)
+	UpdateRoomScript(ctx context.Context, ownerID uuid.UUID, script *entities.RoomScript) (*entities.RoomScript, error)
+	DeleteRoomScript(ctx context.Context, roomID, scriptID, ownerID uuid.UUID) error
+	GetRoomScripts(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.RoomScript, error)
+	GetEnabledScripts(ctx context.Context, roomID *uuid.UUID, scheduledOnly bool) ([]*entities.RoomScript, error)
+	CreateRoomWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook) (*entities.Webhook, error)
+	UpdateRoomWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook, rotateSecret bool) (*entities.Webhook, error)
+	DeleteRoomWebhook(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error
+	GetRoomWebhooks(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.Webhook, error)
+	GetWebhookDeliveries(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookAttempt, error)
+	GetWebhookDeadLetters(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error)
+	RedeliverWebhook(ctx context.Context, roomID, webhookID, deadLetterID, ownerID uuid.UUID) error
+	PublishRoomEvent(ctx context.Context, event *entities.WebhookEvent) (bool, error)
+	CreateIncomingWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.IncomingWebhook) (*entities.IncomingWebhook, string, error)
+	GetIncomingWebhooks(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error
+	ResolveIncomingWebhook(ctx context.Context, token string) (*entities.IncomingWebhook, error)
+	SetRetentionPolicy(ctx context.Context, ownerID uuid.UUID, policy *entities.RetentionPolicy) (*entities.RetentionPolicy, error)
+	GetRetentionPolicy(ctx context.Context, roomID, ownerID uuid.UUID) (*entities.RetentionPolicy, error)
+	GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
 }
 
 type service struct {
@@ -43,6 +66,7 @@ func (s *service) CreateRoom(ctx context.Context, name string, ownerID uuid.UUID
 		ID:        uuid.New(),
 		Name:      name,
 		OwnerID:   ownerID,
+		Kind:      entities.RoomKindPublic,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -100,3 +124,65 @@ func (s *service) GetAllRooms(ctx context.Context, limit, offset int) ([]*entiti
 	}
 	return rooms, nil
 }
+
+// GetOrCreateDirectRoom returns the direct room of the two users, creating it
+// on first use. userA becomes the owner of a newly created room.
+func (s *service) GetOrCreateDirectRoom(ctx context.Context, userA, userB uuid.UUID) (*entities.Room, error) {
+	if userA == uuid.Nil || userB == uuid.Nil || userA == userB {
+		return nil, errors.Wrap(entities.ErrInvalidMembers, "a direct room needs two distinct users")
+	}
+
+	room, err := s.roomStorage.GetOrCreateDirectRoom(ctx, &entities.Room{
+		ID:        uuid.New(),
+		OwnerID:   userA,
+		Kind:      entities.RoomKindDirect,
+		MemberIDs: []uuid.UUID{userA, userB},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get or create direct room")
+	}
+	return room, nil
+}
+
+func (s *service) CreateGroupRoom(ctx context.Context, name string, ownerID uuid.UUID, memberIDs []uuid.UUID) (*entities.Room, error) {
+	// The owner is always a member; duplicates are ignored.
+	members := []uuid.UUID{ownerID}
+	seen := map[uuid.UUID]bool{ownerID: true}
+	for _, memberID := range memberIDs {
+		if memberID == uuid.Nil {
+			return nil, errors.Wrap(entities.ErrInvalidMembers, "invalid member ID")
+		}
+		if !seen[memberID] {
+			seen[memberID] = true
+			members = append(members, memberID)
+		}
+	}
+
+	if len(members) < 2 || len(members) > entities.MaxGroupMembers {
+		return nil, errors.Wrapf(entities.ErrInvalidMembers, "a group room needs between 2 and %d members", entities.MaxGroupMembers)
+	}
+
+	room := &entities.Room{
+		ID:        uuid.New(),
+		Name:      name,
+		OwnerID:   ownerID,
+		Kind:      entities.RoomKindGroup,
+		MemberIDs: members,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.roomStorage.CreateRoom(ctx, room); err != nil {
+		return nil, errors.Wrap(err, "failed to create group room")
+	}
+	return room, nil
+}
+
+func (s *service) GetMemberRooms(ctx context.Context, userID uuid.UUID) ([]*entities.Room, error) {
+	rooms, err := s.roomStorage.GetRoomsByMemberID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get member's rooms")
+	}
+	return rooms, nil
+}
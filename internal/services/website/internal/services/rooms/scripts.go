@@ -0,0 +1,125 @@
+package rooms
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HexArch/go-chat/internal/pkg/roomscript"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+func (s *service) CreateRoomScript(ctx context.Context, ownerID uuid.UUID, script *entities.RoomScript) (*entities.RoomScript, error) {
+	if err := s.checkRoomOwner(ctx, script.RoomID, ownerID, entities.ErrRoomScriptForbidden); err != nil {
+		return nil, err
+	}
+	if err := validateScript(script); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	created := *script
+	created.ID = uuid.New()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	if err := s.roomStorage.CreateRoomScript(ctx, &created, entities.MaxRoomScripts); err != nil {
+		return nil, errors.Wrap(err, "failed to create room script")
+	}
+	return &created, nil
+}
+
+func (s *service) UpdateRoomScript(ctx context.Context, ownerID uuid.UUID, script *entities.RoomScript) (*entities.RoomScript, error) {
+	if err := s.checkRoomOwner(ctx, script.RoomID, ownerID, entities.ErrRoomScriptForbidden); err != nil {
+		return nil, err
+	}
+	if err := validateScript(script); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.roomStorage.GetRoomScript(ctx, script.RoomID, script.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room script")
+	}
+
+	stored.Name = script.Name
+	stored.Source = script.Source
+	stored.Enabled = script.Enabled
+	stored.ScheduleInterval = script.ScheduleInterval
+	stored.UpdatedAt = time.Now()
+
+	if err := s.roomStorage.UpdateRoomScript(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "failed to update room script")
+	}
+	return stored, nil
+}
+
+func (s *service) DeleteRoomScript(ctx context.Context, roomID, scriptID, ownerID uuid.UUID) error {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomScriptForbidden); err != nil {
+		return err
+	}
+
+	if err := s.roomStorage.DeleteRoomScript(ctx, roomID, scriptID); err != nil {
+		return errors.Wrap(err, "failed to delete room script")
+	}
+	return nil
+}
+
+func (s *service) GetRoomScripts(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.RoomScript, error) {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomScriptForbidden); err != nil {
+		return nil, err
+	}
+
+	scripts, err := s.roomStorage.GetRoomScripts(ctx, roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room scripts")
+	}
+	return scripts, nil
+}
+
+// GetEnabledScripts returns the scripts the chat service runs: the enabled
+// scripts of a room, or of every room when roomID is nil.
+func (s *service) GetEnabledScripts(ctx context.Context, roomID *uuid.UUID, scheduledOnly bool) ([]*entities.RoomScript, error) {
+	scripts, err := s.roomStorage.GetEnabledScripts(ctx, roomID, scheduledOnly)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get enabled scripts")
+	}
+	return scripts, nil
+}
+
+// checkRoomOwner ensures that only the owner of a room manages its scripts and
+// webhooks; forbidden is returned to other users.
+func (s *service) checkRoomOwner(ctx context.Context, roomID, ownerID uuid.UUID, forbidden error) error {
+	room, err := s.roomStorage.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get room")
+	}
+	if room.OwnerID != ownerID {
+		return forbidden
+	}
+	return nil
+}
+
+// validateScript checks the limits of a script and compiles its source, so that
+// syntax errors are reported when the script is saved rather than when it runs.
+func validateScript(script *entities.RoomScript) error {
+	if name := utf8.RuneCountInString(script.Name); name < 1 || name > entities.MaxScriptNameLength {
+		return &entities.ScriptError{Reason: fmt.Sprintf("name must be between 1 and %d characters", entities.MaxScriptNameLength)}
+	}
+	if script.Source == "" || len(script.Source) > entities.MaxScriptSourceLength {
+		return &entities.ScriptError{Reason: fmt.Sprintf("source must be between 1 and %d bytes", entities.MaxScriptSourceLength)}
+	}
+	if interval := script.ScheduleInterval; interval != 0 &&
+		(interval < entities.MinScheduleInterval || interval > entities.MaxScheduleInterval || interval%time.Second != 0) {
+		return &entities.ScriptError{Reason: fmt.Sprintf("schedule must be whole seconds between %d and %d",
+			int(entities.MinScheduleInterval/time.Second), int(entities.MaxScheduleInterval/time.Second))}
+	}
+
+	if err := roomscript.Compile(script.Name, script.Source); err != nil {
+		return &entities.ScriptError{Reason: err.Error()}
+	}
+	return nil
+}
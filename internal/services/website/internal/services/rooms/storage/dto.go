@@ -1,38 +1,186 @@
 package storage
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Room struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Name      string    `gorm:"column:name;unique"`
-	OwnerID   uuid.UUID `gorm:"column:owner_id;type:uuid"`
+	ID        uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	Name      string            `gorm:"column:name"`
+	OwnerID   uuid.UUID         `gorm:"column:owner_id;type:uuid"`
+	Kind      string            `gorm:"column:kind;type:varchar(16);not null;default:public;index"`
+	DirectKey *string           `gorm:"column:direct_key;uniqueIndex"`
+	Members   []RoomMember      `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE"`
+	Scripts   []RoomScript      `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE"`
+	Webhooks  []RoomWebhook     `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE"`
+	Incoming  []IncomingWebhook `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE"`
+	Retention *RoomRetention    `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE"`
+	Imports   []RoomImport      `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time         `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time         `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// RoomMember is a member of a direct or group room.
+type RoomMember struct {
+	RoomID    uuid.UUID `gorm:"column:room_id;type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"column:user_id;type:uuid;primaryKey;index"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// RoomScript is a Starlark script attached to a room.
+type RoomScript struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoomID          uuid.UUID `gorm:"column:room_id;type:uuid;not null;index"`
+	Name            string    `gorm:"column:name;type:varchar(50);not null"`
+	Source          string    `gorm:"column:source;type:text;not null"`
+	Enabled         bool      `gorm:"column:enabled;not null"`
+	ScheduleSeconds int       `gorm:"column:schedule_seconds;not null;default:0"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// RoomWebhook is an outgoing webhook attached to a room.
+type RoomWebhook struct {
+	ID          uuid.UUID           `gorm:"type:uuid;primaryKey"`
+	RoomID      uuid.UUID           `gorm:"column:room_id;type:uuid;not null;index"`
+	URL         string              `gorm:"column:url;type:text;not null"`
+	Secret      string              `gorm:"column:secret;type:varchar(64);not null"`
+	Events      pq.StringArray      `gorm:"column:events;type:text[];not null"`
+	Enabled     bool                `gorm:"column:enabled;not null"`
+	Deliveries  []WebhookDelivery   `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	Attempts    []WebhookAttempt    `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	DeadLetters []WebhookDeadLetter `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time           `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time           `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// WebhookDelivery is a payload waiting to be delivered to a webhook.
+type WebhookDelivery struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
+	WebhookID     uuid.UUID `gorm:"column:webhook_id;type:uuid;not null;index"`
+	RoomID        uuid.UUID `gorm:"column:room_id;type:uuid;not null"`
+	Event         string    `gorm:"column:event;type:varchar(32);not null"`
+	Payload       string    `gorm:"column:payload;type:text;not null"`
+	Attempts      int       `gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt time.Time `gorm:"column:next_attempt_at;not null;index"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+// WebhookAttempt is an entry of the webhook delivery log.
+type WebhookAttempt struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeliveryID uuid.UUID `gorm:"column:delivery_id;type:uuid;not null;index"`
+	WebhookID  uuid.UUID `gorm:"column:webhook_id;type:uuid;not null;index:idx_webhook_attempts_webhook_created,priority:1"`
+	Event      string    `gorm:"column:event;type:varchar(32);not null"`
+	Attempt    int       `gorm:"column:attempt;not null"`
+	StatusCode int       `gorm:"column:status_code;not null;default:0"`
+	Error      string    `gorm:"column:error;type:text;not null;default:''"`
+	DurationMs int64     `gorm:"column:duration_ms;not null;default:0"`
+	CreatedAt  time.Time `gorm:"column:created_at;index;index:idx_webhook_attempts_webhook_created,priority:2"`
+}
+
+// WebhookDeadLetter is a delivery that will not be retried automatically.
+type WebhookDeadLetter struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	WebhookID uuid.UUID `gorm:"column:webhook_id;type:uuid;not null;index"`
+	RoomID    uuid.UUID `gorm:"column:room_id;type:uuid;not null"`
+	Event     string    `gorm:"column:event;type:varchar(32);not null"`
+	Payload   string    `gorm:"column:payload;type:text;not null"`
+	Attempts  int       `gorm:"column:attempts;not null"`
+	LastError string    `gorm:"column:last_error;type:text;not null;default:''"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	FailedAt  time.Time `gorm:"column:failed_at;not null"`
+}
+
+// IncomingWebhook lets an integration post to a room with a secret token.
+type IncomingWebhook struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoomID    uuid.UUID `gorm:"column:room_id;type:uuid;not null;index"`
+	Name      string    `gorm:"column:name;type:varchar(50);not null"`
+	TokenHash string    `gorm:"column:token_hash;type:char(64);not null;uniqueIndex"`
+	CreatedBy uuid.UUID `gorm:"column:created_by;type:uuid;not null"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// RoomRetention is the retention policy of a room. Rooms without one keep their messages forever.
+type RoomRetention struct {
+	RoomID    uuid.UUID `gorm:"column:room_id;type:uuid;primaryKey"`
+	Days      int       `gorm:"column:days;not null;default:0"`
+	Messages  int       `gorm:"column:messages;not null;default:0"`
+	UpdatedBy uuid.UUID `gorm:"column:updated_by;type:uuid;not null"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// RoomImport records the conversation of another chat system a room was
+// imported from, so that importing it again finds the room.
+type RoomImport struct {
+	Source     string    `gorm:"column:source;type:varchar(16);primaryKey"`
+	ExternalID string    `gorm:"column:external_id;type:varchar(64);primaryKey"`
+	RoomID     uuid.UUID `gorm:"column:room_id;type:uuid;not null;index"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// directKey identifies the direct room of a pair of users regardless of their order.
+func directKey(memberIDs []uuid.UUID) string {
+	keys := make([]string, len(memberIDs))
+	for i, memberID := range memberIDs {
+		keys[i] = memberID.String()
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ":")
 }
 
 func RoomToDTO(room *entities.Room) *Room {
-	return &Room{
+	kind := room.Kind
+	if kind == "" {
+		kind = entities.RoomKindPublic
+	}
+
+	dto := &Room{
 		ID:        room.ID,
 		Name:      room.Name,
 		OwnerID:   room.OwnerID,
+		Kind:      string(kind),
 		CreatedAt: room.CreatedAt,
 		UpdatedAt: room.UpdatedAt,
 	}
+
+	if kind == entities.RoomKindDirect {
+		key := directKey(room.MemberIDs)
+		dto.DirectKey = &key
+	}
+
+	for _, memberID := range room.MemberIDs {
+		dto.Members = append(dto.Members, RoomMember{
+			RoomID: room.ID,
+			UserID: memberID,
+		})
+	}
+
+	return dto
 }
 
 func DTOToRoom(dto *Room) *entities.Room {
-	return &entities.Room{
+	room := &entities.Room{
 		ID:        dto.ID,
 		Name:      dto.Name,
 		OwnerID:   dto.OwnerID,
+		Kind:      entities.RoomKind(dto.Kind),
 		CreatedAt: dto.CreatedAt,
 		UpdatedAt: dto.UpdatedAt,
 	}
+
+	for _, member := range dto.Members {
+		room.MemberIDs = append(room.MemberIDs, member.UserID)
+	}
+
+	return room
 }
 
 func DTOsToRooms(dtos []Room) []*entities.Room {
@@ -42,3 +190,155 @@ func DTOsToRooms(dtos []Room) []*entities.Room {
 	}
 	return rooms
 }
+
+func RoomScriptToDTO(script *entities.RoomScript) *RoomScript {
+	return &RoomScript{
+		ID:              script.ID,
+		RoomID:          script.RoomID,
+		Name:            script.Name,
+		Source:          script.Source,
+		Enabled:         script.Enabled,
+		ScheduleSeconds: int(script.ScheduleInterval / time.Second),
+		CreatedAt:       script.CreatedAt,
+		UpdatedAt:       script.UpdatedAt,
+	}
+}
+
+func DTOToRoomScript(dto *RoomScript) *entities.RoomScript {
+	return &entities.RoomScript{
+		ID:               dto.ID,
+		RoomID:           dto.RoomID,
+		Name:             dto.Name,
+		Source:           dto.Source,
+		Enabled:          dto.Enabled,
+		ScheduleInterval: time.Duration(dto.ScheduleSeconds) * time.Second,
+		CreatedAt:        dto.CreatedAt,
+		UpdatedAt:        dto.UpdatedAt,
+	}
+}
+
+func DTOsToRoomScripts(dtos []RoomScript) []*entities.RoomScript {
+	scripts := make([]*entities.RoomScript, len(dtos))
+	for i, dto := range dtos {
+		scripts[i] = DTOToRoomScript(&dto)
+	}
+	return scripts
+}
+
+func WebhookToDTO(webhook *entities.Webhook) *RoomWebhook {
+	return &RoomWebhook{
+		ID:        webhook.ID,
+		RoomID:    webhook.RoomID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Events:    pq.StringArray(webhook.Events),
+		Enabled:   webhook.Enabled,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func DTOToWebhook(dto *RoomWebhook) *entities.Webhook {
+	return &entities.Webhook{
+		ID:        dto.ID,
+		RoomID:    dto.RoomID,
+		URL:       dto.URL,
+		Secret:    dto.Secret,
+		Events:    []string(dto.Events),
+		Enabled:   dto.Enabled,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+}
+
+func DTOsToWebhooks(dtos []RoomWebhook) []*entities.Webhook {
+	webhooks := make([]*entities.Webhook, len(dtos))
+	for i, dto := range dtos {
+		webhooks[i] = DTOToWebhook(&dto)
+	}
+	return webhooks
+}
+
+func WebhookAttemptToDTO(attempt *entities.WebhookAttempt) *WebhookAttempt {
+	return &WebhookAttempt{
+		ID:         attempt.ID,
+		DeliveryID: attempt.DeliveryID,
+		WebhookID:  attempt.WebhookID,
+		Event:      attempt.Event,
+		Attempt:    attempt.Attempt,
+		StatusCode: attempt.StatusCode,
+		Error:      attempt.Error,
+		DurationMs: attempt.Duration.Milliseconds(),
+		CreatedAt:  attempt.CreatedAt,
+	}
+}
+
+func DTOToWebhookAttempt(dto *WebhookAttempt) *entities.WebhookAttempt {
+	return &entities.WebhookAttempt{
+		ID:         dto.ID,
+		DeliveryID: dto.DeliveryID,
+		WebhookID:  dto.WebhookID,
+		Event:      dto.Event,
+		Attempt:    dto.Attempt,
+		StatusCode: dto.StatusCode,
+		Error:      dto.Error,
+		Duration:   time.Duration(dto.DurationMs) * time.Millisecond,
+		CreatedAt:  dto.CreatedAt,
+	}
+}
+
+func DTOToWebhookDeadLetter(dto *WebhookDeadLetter) *entities.WebhookDeadLetter {
+	return &entities.WebhookDeadLetter{
+		ID:        dto.ID,
+		WebhookID: dto.WebhookID,
+		RoomID:    dto.RoomID,
+		Event:     dto.Event,
+		Payload:   []byte(dto.Payload),
+		Attempts:  dto.Attempts,
+		LastError: dto.LastError,
+		CreatedAt: dto.CreatedAt,
+		FailedAt:  dto.FailedAt,
+	}
+}
+
+func IncomingWebhookToDTO(webhook *entities.IncomingWebhook) *IncomingWebhook {
+	return &IncomingWebhook{
+		ID:        webhook.ID,
+		RoomID:    webhook.RoomID,
+		Name:      webhook.Name,
+		TokenHash: webhook.TokenHash,
+		CreatedBy: webhook.CreatedBy,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func DTOToIncomingWebhook(dto *IncomingWebhook) *entities.IncomingWebhook {
+	return &entities.IncomingWebhook{
+		ID:        dto.ID,
+		RoomID:    dto.RoomID,
+		Name:      dto.Name,
+		TokenHash: dto.TokenHash,
+		CreatedBy: dto.CreatedBy,
+		CreatedAt: dto.CreatedAt,
+	}
+}
+
+func RetentionPolicyToDTO(policy *entities.RetentionPolicy) *RoomRetention {
+	return &RoomRetention{
+		RoomID:    policy.RoomID,
+		Days:      policy.Days,
+		Messages:  policy.Messages,
+		UpdatedBy: policy.UpdatedBy,
+		UpdatedAt: policy.UpdatedAt,
+	}
+}
+
+func DTOToRetentionPolicy(dto *RoomRetention) *entities.RetentionPolicy {
+	return &entities.RetentionPolicy{
+		RoomID:    dto.RoomID,
+		Days:      dto.Days,
+		Messages:  dto.Messages,
+		UpdatedBy: dto.UpdatedBy,
+		UpdatedAt: dto.UpdatedAt,
+	}
+}
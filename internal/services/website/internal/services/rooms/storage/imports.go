@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetImportedRoom returns the room imported from a conversation of another
+// chat system.
+func (s *storage) GetImportedRoom(ctx context.Context, source, externalID string) (*entities.Room, error) {
+	var link RoomImport
+	if err := s.db.WithContext(ctx).
+		First(&link, "source = ? AND external_id = ?", source, externalID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrRoomNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get imported room")
+	}
+	return s.GetRoomByID(ctx, link.RoomID)
+}
+
+// CreateImportedRoom creates a room, with its members, for a conversation of
+// another chat system.
+func (s *storage) CreateImportedRoom(ctx context.Context, source, externalID string, room *entities.Room) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(RoomToDTO(room)).Error; err != nil {
+			return errors.Wrap(err, "failed to create room")
+		}
+		if err := tx.Create(&RoomImport{Source: source, ExternalID: externalID, RoomID: room.ID}).Error; err != nil {
+			return errors.Wrap(err, "failed to record room import")
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create imported room")
+	}
+	return nil
+}
+
+// LinkImportedRoom records that a conversation of another chat system was
+// imported into an existing room. A conversation already linked keeps its room.
+func (s *storage) LinkImportedRoom(ctx context.Context, source, externalID string, roomID uuid.UUID) error {
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&RoomImport{Source: source, ExternalID: externalID, RoomID: roomID}).Error; err != nil {
+		return errors.Wrap(err, "failed to link imported room")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateIncomingWebhook stores a new incoming webhook unless its room already
+// has maxWebhooks incoming webhooks.
+func (s *storage) CreateIncomingWebhook(ctx context.Context, webhook *entities.IncomingWebhook, maxWebhooks int) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the room serializes concurrent creates against the limit.
+		var room Room
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&room, "id = ?", webhook.RoomID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrRoomNotFound
+			}
+			return errors.Wrap(err, "failed to lock room")
+		}
+
+		var count int64
+		if err := tx.Model(&IncomingWebhook{}).Where("room_id = ?", webhook.RoomID).Count(&count).Error; err != nil {
+			return errors.Wrap(err, "failed to count incoming webhooks")
+		}
+		if count >= int64(maxWebhooks) {
+			return entities.ErrIncomingWebhookLimitReached
+		}
+
+		if err := tx.Create(IncomingWebhookToDTO(webhook)).Error; err != nil {
+			return errors.Wrap(err, "failed to create incoming webhook")
+		}
+		return nil
+	})
+}
+
+func (s *storage) GetIncomingWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.IncomingWebhook, error) {
+	var dtos []IncomingWebhook
+	if err := s.db.WithContext(ctx).
+		Where("room_id = ?", roomID).
+		Order("created_at").
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find incoming webhooks")
+	}
+
+	webhooks := make([]*entities.IncomingWebhook, len(dtos))
+	for i := range dtos {
+		webhooks[i] = DTOToIncomingWebhook(&dtos[i])
+	}
+	return webhooks, nil
+}
+
+func (s *storage) GetIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*entities.IncomingWebhook, error) {
+	var dto IncomingWebhook
+	if err := s.db.WithContext(ctx).First(&dto, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrIncomingWebhookNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get incoming webhook")
+	}
+	return DTOToIncomingWebhook(&dto), nil
+}
+
+func (s *storage) DeleteIncomingWebhook(ctx context.Context, roomID, webhookID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Delete(&IncomingWebhook{}, "id = ? AND room_id = ?", webhookID, roomID)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to delete incoming webhook")
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrIncomingWebhookNotFound
+	}
+	return nil
+}
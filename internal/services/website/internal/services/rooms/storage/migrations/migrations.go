@@ -7,8 +7,34 @@ import (
 )
 
 func Migrate(db *gorm.DB) error {
-	if err := db.AutoMigrate(&storage.Room{}); err != nil {
+	// Room names used to be unique across all rooms; only public rooms keep unique names now.
+	if db.Migrator().HasTable(&storage.Room{}) {
+		for _, constraint := range []string{"uni_rooms_name", "rooms_name_key"} {
+			if err := db.Exec("ALTER TABLE rooms DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+				return errors.Wrap(err, "failed to drop room name constraint")
+			}
+		}
+	}
+
+	if err := db.AutoMigrate(
+		&storage.Room{},
+		&storage.RoomMember{},
+		&storage.RoomScript{},
+		&storage.RoomWebhook{},
+		&storage.WebhookDelivery{},
+		&storage.WebhookAttempt{},
+		&storage.WebhookDeadLetter{},
+		&storage.IncomingWebhook{},
+		&storage.RoomRetention{},
+		&storage.RoomImport{},
+	); err != nil {
 		return errors.Wrap(err, "failed to migrate rooms table")
 	}
+
+	if err := db.Exec(
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_rooms_public_name ON rooms (name) WHERE kind = 'public'",
+	).Error; err != nil {
+		return errors.Wrap(err, "failed to create public room name index")
+	}
 	return nil
 }
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetRetentionPolicy returns the retention policy of a room, or nil when it
+// keeps its messages forever.
+func (s *storage) GetRetentionPolicy(ctx context.Context, roomID uuid.UUID) (*entities.RetentionPolicy, error) {
+	var dto RoomRetention
+	if err := s.db.WithContext(ctx).First(&dto, "room_id = ?", roomID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get retention policy")
+	}
+	return DTOToRetentionPolicy(&dto), nil
+}
+
+// SetRetentionPolicy creates or replaces the retention policy of a room.
+func (s *storage) SetRetentionPolicy(ctx context.Context, policy *entities.RetentionPolicy) error {
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "room_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"days", "messages", "updated_by", "updated_at"}),
+		}).
+		Create(RetentionPolicyToDTO(policy)).Error; err != nil {
+		return errors.Wrap(err, "failed to set retention policy")
+	}
+	return nil
+}
+
+func (s *storage) DeleteRetentionPolicy(ctx context.Context, roomID uuid.UUID) error {
+	if err := s.db.WithContext(ctx).Delete(&RoomRetention{}, "room_id = ?", roomID).Error; err != nil {
+		return errors.Wrap(err, "failed to delete retention policy")
+	}
+	return nil
+}
+
+// GetRetentionPolicies returns the retention policies of every room that has one.
+func (s *storage) GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	var dtos []RoomRetention
+	if err := s.db.WithContext(ctx).Order("room_id").Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find retention policies")
+	}
+
+	policies := make([]*entities.RetentionPolicy, len(dtos))
+	for i := range dtos {
+		policies[i] = DTOToRetentionPolicy(&dtos[i])
+	}
+	return policies, nil
+}
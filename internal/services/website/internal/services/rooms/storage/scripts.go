@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateRoomScript stores a new script unless its room already has maxScripts scripts.
+func (s *storage) CreateRoomScript(ctx context.Context, script *entities.RoomScript, maxScripts int) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the room serializes concurrent creates against the limit.
+		var room Room
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&room, "id = ?", script.RoomID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrRoomNotFound
+			}
+			return errors.Wrap(err, "failed to lock room")
+		}
+
+		var count int64
+		if err := tx.Model(&RoomScript{}).Where("room_id = ?", script.RoomID).Count(&count).Error; err != nil {
+			return errors.Wrap(err, "failed to count room scripts")
+		}
+		if count >= int64(maxScripts) {
+			return entities.ErrScriptLimitReached
+		}
+
+		if err := tx.Create(RoomScriptToDTO(script)).Error; err != nil {
+			return errors.Wrap(err, "failed to create room script")
+		}
+		return nil
+	})
+}
+
+func (s *storage) GetRoomScript(ctx context.Context, roomID, scriptID uuid.UUID) (*entities.RoomScript, error) {
+	var dto RoomScript
+	if err := s.db.WithContext(ctx).First(&dto, "id = ? AND room_id = ?", scriptID, roomID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrScriptNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get room script")
+	}
+	return DTOToRoomScript(&dto), nil
+}
+
+func (s *storage) UpdateRoomScript(ctx context.Context, script *entities.RoomScript) error {
+	result := s.db.WithContext(ctx).
+		Model(&RoomScript{}).
+		Where("id = ? AND room_id = ?", script.ID, script.RoomID).
+		Updates(map[string]interface{}{
+			"name":             script.Name,
+			"source":           script.Source,
+			"enabled":          script.Enabled,
+			"schedule_seconds": RoomScriptToDTO(script).ScheduleSeconds,
+			"updated_at":       script.UpdatedAt,
+		})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to update room script")
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrScriptNotFound
+	}
+	return nil
+}
+
+func (s *storage) DeleteRoomScript(ctx context.Context, roomID, scriptID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Delete(&RoomScript{}, "id = ? AND room_id = ?", scriptID, roomID)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to delete room script")
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrScriptNotFound
+	}
+	return nil
+}
+
+func (s *storage) GetRoomScripts(ctx context.Context, roomID uuid.UUID) ([]*entities.RoomScript, error) {
+	var dtos []RoomScript
+	if err := s.db.WithContext(ctx).
+		Where("room_id = ?", roomID).
+		Order("created_at").
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find room scripts")
+	}
+	return DTOsToRoomScripts(dtos), nil
+}
+
+// GetEnabledScripts returns the enabled scripts of a room, or of every room when
+// roomID is nil. With scheduledOnly only scripts with a schedule are returned.
+func (s *storage) GetEnabledScripts(ctx context.Context, roomID *uuid.UUID, scheduledOnly bool) ([]*entities.RoomScript, error) {
+	query := s.db.WithContext(ctx).Where("enabled")
+	if roomID != nil {
+		query = query.Where("room_id = ?", *roomID)
+	}
+	if scheduledOnly {
+		query = query.Where("schedule_seconds > 0")
+	}
+
+	var dtos []RoomScript
+	if err := query.Order("room_id, created_at").Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find enabled scripts")
+	}
+	return DTOsToRoomScripts(dtos), nil
+}
@@ -2,12 +2,14 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Storage interface {
@@ -17,6 +19,41 @@ type Storage interface {
 	GetRoomsByName(ctx context.Context, name string, limit, offset int) ([]*entities.Room, error)
 	DeleteRoom(ctx context.Context, roomID uuid.UUID) error
 	GetAllRooms(ctx context.Context, limit, offset int) ([]*entities.Room, error)
+	GetOrCreateDirectRoom(ctx context.Context, room *entities.Room) (*entities.Room, error)
+	GetRoomsByMemberID(ctx context.Context, userID uuid.UUID) ([]*entities.Room, error)
+	CreateRoomScript(ctx context.Context, script *entities.RoomScript, maxScripts int) error
+	GetRoomScript(ctx context.Context, roomID, scriptID uuid.UUID) (*entities.RoomScript, error)
+	UpdateRoomScript(ctx context.Context, script *entities.RoomScript) error
+	DeleteRoomScript(ctx context.Context, roomID, scriptID uuid.UUID) error
+	GetRoomScripts(ctx context.Context, roomID uuid.UUID) ([]*entities.RoomScript, error)
+	GetEnabledScripts(ctx context.Context, roomID *uuid.UUID, scheduledOnly bool) ([]*entities.RoomScript, error)
+	CreateRoomWebhook(ctx context.Context, webhook *entities.Webhook, maxWebhooks int) error
+	GetRoomWebhook(ctx context.Context, roomID, webhookID uuid.UUID) (*entities.Webhook, error)
+	UpdateRoomWebhook(ctx context.Context, webhook *entities.Webhook) error
+	DeleteRoomWebhook(ctx context.Context, roomID, webhookID uuid.UUID) error
+	GetRoomWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.Webhook, error)
+	GetEnabledWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.Webhook, error)
+	CreateWebhookDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+	GetWebhookAttempts(ctx context.Context, webhookID uuid.UUID, limit int) ([]*entities.WebhookAttempt, error)
+	GetWebhookDeadLetters(ctx context.Context, webhookID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error)
+	RedeliverWebhookDeadLetter(ctx context.Context, webhookID, deadLetterID uuid.UUID, now time.Time) error
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.WebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, attempt *entities.WebhookAttempt) error
+	RetryWebhookDelivery(ctx context.Context, attempt *entities.WebhookAttempt, nextAttemptAt time.Time) error
+	DeadLetterWebhookDelivery(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookAttempt) error
+	PruneWebhookAttempts(ctx context.Context, before time.Time) (int64, error)
+	CreateIncomingWebhook(ctx context.Context, webhook *entities.IncomingWebhook, maxWebhooks int) error
+	GetIncomingWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.IncomingWebhook, error)
+	GetIncomingWebhookByTokenHash(ctx context.Context, tokenHash string) (*entities.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, roomID, webhookID uuid.UUID) error
+	GetRetentionPolicy(ctx context.Context, roomID uuid.UUID) (*entities.RetentionPolicy, error)
+	SetRetentionPolicy(ctx context.Context, policy *entities.RetentionPolicy) error
+	DeleteRetentionPolicy(ctx context.Context, roomID uuid.UUID) error
+	GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
+	GetPublicRoomByName(ctx context.Context, name string) (*entities.Room, error)
+	GetImportedRoom(ctx context.Context, source, externalID string) (*entities.Room, error)
+	CreateImportedRoom(ctx context.Context, source, externalID string, room *entities.Room) error
+	LinkImportedRoom(ctx context.Context, source, externalID string, roomID uuid.UUID) error
 }
 
 type storage struct {
@@ -37,7 +74,7 @@ func (s *storage) CreateRoom(ctx context.Context, room *entities.Room) error {
 
 func (s *storage) GetRoomByID(ctx context.Context, roomID uuid.UUID) (*entities.Room, error) {
 	var room Room
-	if err := s.db.WithContext(ctx).First(&room, "id = ?", roomID).Error; err != nil {
+	if err := s.db.WithContext(ctx).Preload("Members").First(&room, "id = ?", roomID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, entities.ErrRoomNotFound
 		}
@@ -48,7 +85,9 @@ func (s *storage) GetRoomByID(ctx context.Context, roomID uuid.UUID) (*entities.
 
 func (s *storage) GetRoomsByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*entities.Room, error) {
 	var dtos []Room
-	if err := s.db.WithContext(ctx).Where("owner_id = ?", ownerID).Find(&dtos).Error; err != nil {
+	if err := s.db.WithContext(ctx).
+		Where("owner_id = ? AND kind = ?", ownerID, entities.RoomKindPublic).
+		Find(&dtos).Error; err != nil {
 		return nil, errors.Wrap(err, "failed to find rooms by owner ID")
 	}
 	return DTOsToRooms(dtos), nil
@@ -57,7 +96,7 @@ func (s *storage) GetRoomsByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*
 func (s *storage) GetRoomsByName(ctx context.Context, name string, limit, offset int) ([]*entities.Room, error) {
 	var dtos []Room
 	if err := s.db.WithContext(ctx).
-		Where("name LIKE ?", "%"+name+"%").
+		Where("kind = ? AND name LIKE ?", entities.RoomKindPublic, "%"+name+"%").
 		Limit(limit).
 		Offset(offset).
 		Find(&dtos).Error; err != nil {
@@ -66,6 +105,19 @@ func (s *storage) GetRoomsByName(ctx context.Context, name string, limit, offset
 	return DTOsToRooms(dtos), nil
 }
 
+// GetPublicRoomByName returns the public room with exactly the given name.
+func (s *storage) GetPublicRoomByName(ctx context.Context, name string) (*entities.Room, error) {
+	var room Room
+	if err := s.db.WithContext(ctx).
+		First(&room, "kind = ? AND name = ?", entities.RoomKindPublic, name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrRoomNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get room by name")
+	}
+	return DTOToRoom(&room), nil
+}
+
 func (s *storage) DeleteRoom(ctx context.Context, roomID uuid.UUID) error {
 	if err := s.db.WithContext(ctx).Delete(&Room{}, "id = ?", roomID).Error; err != nil {
 		return errors.Wrap(err, "failed to delete room")
@@ -76,6 +128,7 @@ func (s *storage) DeleteRoom(ctx context.Context, roomID uuid.UUID) error {
 func (s *storage) GetAllRooms(ctx context.Context, limit, offset int) ([]*entities.Room, error) {
 	var dtos []Room
 	if err := s.db.WithContext(ctx).
+		Where("kind = ?", entities.RoomKindPublic).
 		Limit(limit).
 		Offset(offset).
 		Find(&dtos).Error; err != nil {
@@ -83,3 +136,50 @@ func (s *storage) GetAllRooms(ctx context.Context, limit, offset int) ([]*entiti
 	}
 	return DTOsToRooms(dtos), nil
 }
+
+// GetOrCreateDirectRoom creates the direct room between the members of room
+// unless one already exists, and returns the stored room either way.
+func (s *storage) GetOrCreateDirectRoom(ctx context.Context, room *entities.Room) (*entities.Room, error) {
+	dto := RoomToDTO(room)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Concurrent requests for the same pair race on the unique direct key.
+		result := tx.Omit("Members").
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "direct_key"}}, DoNothing: true}).
+			Create(dto)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "failed to create direct room")
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&dto.Members).Error; err != nil {
+			return errors.Wrap(err, "failed to add direct room members")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stored Room
+	if err := s.db.WithContext(ctx).
+		Preload("Members").
+		First(&stored, "direct_key = ?", *dto.DirectKey).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get direct room")
+	}
+	return DTOToRoom(&stored), nil
+}
+
+func (s *storage) GetRoomsByMemberID(ctx context.Context, userID uuid.UUID) ([]*entities.Room, error) {
+	var dtos []Room
+	if err := s.db.WithContext(ctx).
+		Preload("Members").
+		Where("id IN (?)", s.db.Model(&RoomMember{}).Select("room_id").Where("user_id = ?", userID)).
+		Order("updated_at DESC").
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find rooms by member ID")
+	}
+	return DTOsToRooms(dtos), nil
+}
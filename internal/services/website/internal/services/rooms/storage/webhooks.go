@@ -0,0 +1,319 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateRoomWebhook stores a new webhook unless its room already has maxWebhooks webhooks.
+func (s *storage) CreateRoomWebhook(ctx context.Context, webhook *entities.Webhook, maxWebhooks int) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the room serializes concurrent creates against the limit.
+		var room Room
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&room, "id = ?", webhook.RoomID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrRoomNotFound
+			}
+			return errors.Wrap(err, "failed to lock room")
+		}
+
+		var count int64
+		if err := tx.Model(&RoomWebhook{}).Where("room_id = ?", webhook.RoomID).Count(&count).Error; err != nil {
+			return errors.Wrap(err, "failed to count room webhooks")
+		}
+		if count >= int64(maxWebhooks) {
+			return entities.ErrWebhookLimitReached
+		}
+
+		if err := tx.Create(WebhookToDTO(webhook)).Error; err != nil {
+			return errors.Wrap(err, "failed to create room webhook")
+		}
+		return nil
+	})
+}
+
+func (s *storage) GetRoomWebhook(ctx context.Context, roomID, webhookID uuid.UUID) (*entities.Webhook, error) {
+	var dto RoomWebhook
+	if err := s.db.WithContext(ctx).First(&dto, "id = ? AND room_id = ?", webhookID, roomID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrWebhookNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get room webhook")
+	}
+	return DTOToWebhook(&dto), nil
+}
+
+func (s *storage) UpdateRoomWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	dto := WebhookToDTO(webhook)
+	result := s.db.WithContext(ctx).
+		Model(&RoomWebhook{}).
+		Where("id = ? AND room_id = ?", webhook.ID, webhook.RoomID).
+		Updates(map[string]interface{}{
+			"url":        dto.URL,
+			"secret":     dto.Secret,
+			"events":     dto.Events,
+			"enabled":    dto.Enabled,
+			"updated_at": dto.UpdatedAt,
+		})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to update room webhook")
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *storage) DeleteRoomWebhook(ctx context.Context, roomID, webhookID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Delete(&RoomWebhook{}, "id = ? AND room_id = ?", webhookID, roomID)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to delete room webhook")
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *storage) GetRoomWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.Webhook, error) {
+	var dtos []RoomWebhook
+	if err := s.db.WithContext(ctx).
+		Where("room_id = ?", roomID).
+		Order("created_at").
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find room webhooks")
+	}
+	return DTOsToWebhooks(dtos), nil
+}
+
+// GetEnabledWebhooks returns the enabled webhooks of a room, whatever their events.
+func (s *storage) GetEnabledWebhooks(ctx context.Context, roomID uuid.UUID) ([]*entities.Webhook, error) {
+	var dtos []RoomWebhook
+	if err := s.db.WithContext(ctx).
+		Where("room_id = ? AND enabled", roomID).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find enabled webhooks")
+	}
+	return DTOsToWebhooks(dtos), nil
+}
+
+// CreateWebhookDeliveries queues the deliveries, skipping the ones already queued.
+func (s *storage) CreateWebhookDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	dtos := make([]WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		dtos[i] = WebhookDelivery{
+			ID:            delivery.ID,
+			WebhookID:     delivery.WebhookID,
+			RoomID:        delivery.RoomID,
+			Event:         delivery.Event,
+			Payload:       string(delivery.Payload),
+			Attempts:      delivery.Attempts,
+			NextAttemptAt: delivery.NextAttemptAt,
+			CreatedAt:     delivery.CreatedAt,
+		}
+	}
+
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dtos).Error; err != nil {
+		return errors.Wrap(err, "failed to create webhook deliveries")
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries claims up to limit deliveries due at now for delivery
+// by the caller and counts their attempt. A claimed delivery is not due again
+// until leaseUntil, so a delivery whose claimer died is retried then. Deliveries
+// of disabled webhooks wait until the webhook is enabled again.
+func (s *storage) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	var rows []struct {
+		WebhookDelivery
+		URL    string
+		Secret string
+	}
+
+	// SKIP LOCKED lets every website instance claim a different batch.
+	err := s.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries AS d
+		SET attempts = d.attempts + 1, next_attempt_at = ?
+		FROM room_webhooks AS w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT pending.id FROM webhook_deliveries AS pending
+			JOIN room_webhooks AS hook ON hook.id = pending.webhook_id
+			WHERE hook.enabled AND pending.next_attempt_at <= ?
+			ORDER BY pending.next_attempt_at
+			LIMIT ?
+			FOR UPDATE OF pending SKIP LOCKED
+		)
+		RETURNING d.*, w.url, w.secret`,
+		leaseUntil, now, limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim webhook deliveries")
+	}
+
+	deliveries := make([]*entities.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = &entities.WebhookDelivery{
+			ID:            row.ID,
+			WebhookID:     row.WebhookID,
+			RoomID:        row.RoomID,
+			Event:         row.Event,
+			Payload:       []byte(row.Payload),
+			Attempts:      row.Attempts,
+			NextAttemptAt: row.NextAttemptAt,
+			CreatedAt:     row.CreatedAt,
+			URL:           row.URL,
+			Secret:        row.Secret,
+		}
+	}
+	return deliveries, nil
+}
+
+// CompleteWebhookDelivery logs the successful attempt of a delivery and removes it.
+func (s *storage) CompleteWebhookDelivery(ctx context.Context, attempt *entities.WebhookAttempt) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(WebhookAttemptToDTO(attempt)).Error; err != nil {
+			return errors.Wrap(err, "failed to log webhook attempt")
+		}
+		if err := tx.Delete(&WebhookDelivery{}, "id = ?", attempt.DeliveryID).Error; err != nil {
+			return errors.Wrap(err, "failed to delete webhook delivery")
+		}
+		return nil
+	})
+}
+
+// RetryWebhookDelivery logs a failed attempt of a delivery and schedules the next one.
+func (s *storage) RetryWebhookDelivery(ctx context.Context, attempt *entities.WebhookAttempt, nextAttemptAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(WebhookAttemptToDTO(attempt)).Error; err != nil {
+			return errors.Wrap(err, "failed to log webhook attempt")
+		}
+		if err := tx.Model(&WebhookDelivery{}).
+			Where("id = ?", attempt.DeliveryID).
+			Update("next_attempt_at", nextAttemptAt).Error; err != nil {
+			return errors.Wrap(err, "failed to reschedule webhook delivery")
+		}
+		return nil
+	})
+}
+
+// DeadLetterWebhookDelivery logs the last failed attempt of a delivery and moves
+// the delivery to the dead letters.
+func (s *storage) DeadLetterWebhookDelivery(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookAttempt) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(WebhookAttemptToDTO(attempt)).Error; err != nil {
+			return errors.Wrap(err, "failed to log webhook attempt")
+		}
+
+		deadLetter := WebhookDeadLetter{
+			ID:        delivery.ID,
+			WebhookID: delivery.WebhookID,
+			RoomID:    delivery.RoomID,
+			Event:     delivery.Event,
+			Payload:   string(delivery.Payload),
+			Attempts:  delivery.Attempts,
+			LastError: attempt.Error,
+			CreatedAt: delivery.CreatedAt,
+			FailedAt:  attempt.CreatedAt,
+		}
+		if err := tx.Create(&deadLetter).Error; err != nil {
+			return errors.Wrap(err, "failed to create webhook dead letter")
+		}
+
+		if err := tx.Delete(&WebhookDelivery{}, "id = ?", delivery.ID).Error; err != nil {
+			return errors.Wrap(err, "failed to delete webhook delivery")
+		}
+		return nil
+	})
+}
+
+// GetWebhookAttempts returns the latest entries of the delivery log of a webhook, newest first.
+func (s *storage) GetWebhookAttempts(ctx context.Context, webhookID uuid.UUID, limit int) ([]*entities.WebhookAttempt, error) {
+	var dtos []WebhookAttempt
+	if err := s.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find webhook attempts")
+	}
+
+	attempts := make([]*entities.WebhookAttempt, len(dtos))
+	for i := range dtos {
+		attempts[i] = DTOToWebhookAttempt(&dtos[i])
+	}
+	return attempts, nil
+}
+
+// GetWebhookDeadLetters returns the latest dead letters of a webhook, newest first.
+func (s *storage) GetWebhookDeadLetters(ctx context.Context, webhookID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error) {
+	var dtos []WebhookDeadLetter
+	if err := s.db.WithContext(ctx).
+		Where("webhook_id = ?", webhookID).
+		Order("failed_at DESC").
+		Limit(limit).
+		Find(&dtos).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find webhook dead letters")
+	}
+
+	deadLetters := make([]*entities.WebhookDeadLetter, len(dtos))
+	for i := range dtos {
+		deadLetters[i] = DTOToWebhookDeadLetter(&dtos[i])
+	}
+	return deadLetters, nil
+}
+
+// RedeliverWebhookDeadLetter moves a dead letter back to the pending deliveries
+// with a fresh set of attempts. The delivery keeps its ID.
+func (s *storage) RedeliverWebhookDeadLetter(ctx context.Context, webhookID, deadLetterID uuid.UUID, now time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deadLetter WebhookDeadLetter
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&deadLetter, "id = ? AND webhook_id = ?", deadLetterID, webhookID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrDeadLetterNotFound
+			}
+			return errors.Wrap(err, "failed to get webhook dead letter")
+		}
+
+		delivery := WebhookDelivery{
+			ID:            deadLetter.ID,
+			WebhookID:     deadLetter.WebhookID,
+			RoomID:        deadLetter.RoomID,
+			Event:         deadLetter.Event,
+			Payload:       deadLetter.Payload,
+			NextAttemptAt: now,
+			CreatedAt:     deadLetter.CreatedAt,
+		}
+		if err := tx.Create(&delivery).Error; err != nil {
+			return errors.Wrap(err, "failed to create webhook delivery")
+		}
+
+		if err := tx.Delete(&deadLetter).Error; err != nil {
+			return errors.Wrap(err, "failed to delete webhook dead letter")
+		}
+		return nil
+	})
+}
+
+// PruneWebhookAttempts deletes the delivery log entries created before a time.
+func (s *storage) PruneWebhookAttempts(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Delete(&WebhookAttempt{}, "created_at < ?", before)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "failed to prune webhook attempts")
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,257 @@
+package rooms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// webhookSecretSize is the number of random bytes in a webhook secret.
+const webhookSecretSize = 32
+
+func (s *service) CreateRoomWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook) (*entities.Webhook, error) {
+	if err := s.checkRoomOwner(ctx, webhook.RoomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return nil, err
+	}
+	events, err := validateWebhook(webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	created := *webhook
+	created.ID = uuid.New()
+	created.Secret = secret
+	created.Events = events
+	created.CreatedAt = now
+	created.UpdatedAt = now
+
+	if err := s.roomStorage.CreateRoomWebhook(ctx, &created, entities.MaxRoomWebhooks); err != nil {
+		return nil, errors.Wrap(err, "failed to create room webhook")
+	}
+	return &created, nil
+}
+
+// UpdateRoomWebhook replaces the URL, events and state of a webhook. With
+// rotateSecret, the webhook also gets a new secret.
+func (s *service) UpdateRoomWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook, rotateSecret bool) (*entities.Webhook, error) {
+	if err := s.checkRoomOwner(ctx, webhook.RoomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return nil, err
+	}
+	events, err := validateWebhook(webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.roomStorage.GetRoomWebhook(ctx, webhook.RoomID, webhook.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room webhook")
+	}
+
+	if rotateSecret {
+		if stored.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	stored.URL = webhook.URL
+	stored.Events = events
+	stored.Enabled = webhook.Enabled
+	stored.UpdatedAt = time.Now()
+
+	if err := s.roomStorage.UpdateRoomWebhook(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "failed to update room webhook")
+	}
+	return stored, nil
+}
+
+func (s *service) DeleteRoomWebhook(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return err
+	}
+
+	if err := s.roomStorage.DeleteRoomWebhook(ctx, roomID, webhookID); err != nil {
+		return errors.Wrap(err, "failed to delete room webhook")
+	}
+	return nil
+}
+
+func (s *service) GetRoomWebhooks(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.Webhook, error) {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.roomStorage.GetRoomWebhooks(ctx, roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room webhooks")
+	}
+	return webhooks, nil
+}
+
+// GetWebhookDeliveries returns the latest entries of the delivery log of a webhook.
+func (s *service) GetWebhookDeliveries(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookAttempt, error) {
+	if err := s.checkWebhookOwner(ctx, roomID, webhookID, ownerID); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.roomStorage.GetWebhookAttempts(ctx, webhookID, webhookLogLimit(limit))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook deliveries")
+	}
+	return attempts, nil
+}
+
+// GetWebhookDeadLetters returns the latest deliveries a webhook failed to accept.
+func (s *service) GetWebhookDeadLetters(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error) {
+	if err := s.checkWebhookOwner(ctx, roomID, webhookID, ownerID); err != nil {
+		return nil, err
+	}
+
+	deadLetters, err := s.roomStorage.GetWebhookDeadLetters(ctx, webhookID, webhookLogLimit(limit))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook dead letters")
+	}
+	return deadLetters, nil
+}
+
+// RedeliverWebhook queues a dead letter for delivery again.
+func (s *service) RedeliverWebhook(ctx context.Context, roomID, webhookID, deadLetterID, ownerID uuid.UUID) error {
+	if err := s.checkWebhookOwner(ctx, roomID, webhookID, ownerID); err != nil {
+		return err
+	}
+
+	if err := s.roomStorage.RedeliverWebhookDeadLetter(ctx, webhookID, deadLetterID, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to redeliver webhook")
+	}
+	return nil
+}
+
+// PublishRoomEvent queues an event of a room for delivery to the enabled
+// webhooks subscribed to it. It reports whether the room has enabled webhooks
+// at all, so that publishers can skip rooms without any. The deliveries are
+// derived from the event ID, so publishing an event again does not queue it twice.
+func (s *service) PublishRoomEvent(ctx context.Context, event *entities.WebhookEvent) (bool, error) {
+	if !isWebhookEvent(event.Type) {
+		return false, &entities.WebhookError{Reason: fmt.Sprintf("unknown event %q", event.Type)}
+	}
+
+	webhooks, err := s.roomStorage.GetEnabledWebhooks(ctx, event.RoomID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get enabled webhooks")
+	}
+	if len(webhooks) == 0 {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(entities.WebhookPayload{
+		ID:         event.ID,
+		Event:      event.Type,
+		RoomID:     event.RoomID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal webhook payload")
+	}
+
+	now := time.Now()
+	var deliveries []*entities.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, &entities.WebhookDelivery{
+			ID:            uuid.NewSHA1(event.ID, webhook.ID[:]),
+			WebhookID:     webhook.ID,
+			RoomID:        event.RoomID,
+			Event:         event.Type,
+			Payload:       payload,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := s.roomStorage.CreateWebhookDeliveries(ctx, deliveries); err != nil {
+		return true, errors.Wrap(err, "failed to queue webhook deliveries")
+	}
+	return true, nil
+}
+
+// checkWebhookOwner ensures that the webhook belongs to the room and that only
+// the owner of the room reads its logs.
+func (s *service) checkWebhookOwner(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error {
+	if err := s.checkRoomOwner(ctx, roomID, ownerID, entities.ErrRoomWebhookForbidden); err != nil {
+		return err
+	}
+	if _, err := s.roomStorage.GetRoomWebhook(ctx, roomID, webhookID); err != nil {
+		return errors.Wrap(err, "failed to get room webhook")
+	}
+	return nil
+}
+
+// validateWebhook checks the URL and events of a webhook and returns its events
+// without duplicates.
+func validateWebhook(webhook *entities.Webhook) ([]string, error) {
+	if len(webhook.URL) > entities.MaxWebhookURLLength {
+		return nil, &entities.WebhookError{Reason: fmt.Sprintf("url exceeds %d characters", entities.MaxWebhookURLLength)}
+	}
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &entities.WebhookError{Reason: "url must be an absolute http or https URL"}
+	}
+
+	if len(webhook.Events) == 0 {
+		return nil, &entities.WebhookError{Reason: "at least one event is required"}
+	}
+	events := make([]string, 0, len(webhook.Events))
+	seen := make(map[string]bool, len(webhook.Events))
+	for _, event := range webhook.Events {
+		if !isWebhookEvent(event) {
+			return nil, &entities.WebhookError{Reason: fmt.Sprintf("unknown event %q", event)}
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func isWebhookEvent(event string) bool {
+	for _, e := range entities.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookLogLimit(limit int) int {
+	if limit <= 0 {
+		return entities.DefaultWebhookLogLimit
+	}
+	if limit > entities.MaxWebhookLogLimit {
+		return entities.MaxWebhookLogLimit
+	}
+	return limit
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate webhook secret")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,27 @@
+package slackimport
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type Storage interface {
+	GetPublicRoomByName(ctx context.Context, name string) (*entities.Room, error)
+	GetOrCreateDirectRoom(ctx context.Context, room *entities.Room) (*entities.Room, error)
+	GetImportedRoom(ctx context.Context, source, externalID string) (*entities.Room, error)
+	CreateImportedRoom(ctx context.Context, source, externalID string, room *entities.Room) error
+	LinkImportedRoom(ctx context.Context, source, externalID string, roomID uuid.UUID) error
+}
+
+// Users are the accounts of the Auth Service.
+type Users interface {
+	GetUsers(ctx context.Context, limit, offset int) ([]*entities.User, error)
+	RegisterUser(ctx context.Context, email, username, password string) error
+}
+
+type Deps struct {
+	Storage Storage
+	Users   Users
+}
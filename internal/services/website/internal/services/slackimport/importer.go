@@ -0,0 +1,303 @@
+// Package slackimport brings the users and conversations of a Slack export
+// into go-chat. Slack users are matched to Auth Service users by email, or get
+// a placeholder account; conversations become rooms. Importing the same export
+// again reuses the users and rooms of the previous run, and the resulting
+// mapping lets the Chat Service import the messages.
+package slackimport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/pkg/slackexport"
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Source identifies Slack among the systems rooms are imported from.
+const Source = "slack"
+
+// usersPageSize is the number of Auth Service users fetched per request.
+const usersPageSize = 500
+
+// Config sets how placeholder accounts are created. Placeholders get the
+// address slack-<user ID>@<EmailDomain> and a random password, so nobody can
+// log into them until an administrator hands them over.
+type Config struct {
+	EmailDomain string
+}
+
+// Result counts what an import did.
+type Result struct {
+	UsersMatched int // Slack users matched to an existing user or placeholder.
+	UsersCreated int // Placeholders registered.
+	RoomsCreated int
+	RoomsReused  int // Rooms found from a previous run or by name.
+	RoomsSkipped int // Conversations without enough known members.
+}
+
+type Importer struct {
+	storage Storage
+	users   Users
+	cfg     Config
+	logger  *zap.Logger
+}
+
+func New(deps Deps, cfg Config, logger *zap.Logger) *Importer {
+	return &Importer{
+		storage: deps.Storage,
+		users:   deps.Users,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Import maps the users and conversations of an archive, creating what is
+// missing, and records the result in mapping.
+func (i *Importer) Import(ctx context.Context, archive *slackexport.Archive, mapping *slackexport.Mapping) (*Result, error) {
+	result := &Result{}
+
+	slackUsers, err := archive.Users()
+	if err != nil {
+		return nil, err
+	}
+	if err := i.importUsers(ctx, slackUsers, mapping, result); err != nil {
+		return nil, err
+	}
+
+	conversations, err := archive.Conversations()
+	if err != nil {
+		return nil, err
+	}
+	for idx := range conversations {
+		if err := i.importConversation(ctx, &conversations[idx], mapping, result); err != nil {
+			return nil, errors.Wrapf(err, "failed to import conversation %s", conversations[idx].ID)
+		}
+	}
+
+	return result, nil
+}
+
+// importUsers maps every Slack user to a user with the same email, or to its
+// placeholder, registering the placeholders that do not exist yet.
+func (i *Importer) importUsers(ctx context.Context, slackUsers []slackexport.User, mapping *slackexport.Mapping, result *Result) error {
+	byEmail, err := i.usersByEmail(ctx)
+	if err != nil {
+		return err
+	}
+
+	var created []string
+	for idx := range slackUsers {
+		slackUser := &slackUsers[idx]
+
+		if slackUser.Profile.Email != "" {
+			if user, ok := byEmail[strings.ToLower(slackUser.Profile.Email)]; ok {
+				mapping.Users[slackUser.ID] = slackexport.MappedUser{ID: user.ID, Username: user.Username}
+				result.UsersMatched++
+				continue
+			}
+		}
+
+		email := i.placeholderEmail(slackUser.ID)
+		if user, ok := byEmail[email]; ok {
+			mapping.Users[slackUser.ID] = slackexport.MappedUser{ID: user.ID, Username: user.Username}
+			result.UsersMatched++
+			continue
+		}
+
+		password, err := placeholderPassword()
+		if err != nil {
+			return err
+		}
+		if err := i.users.RegisterUser(ctx, email, placeholderUsername(slackUser), password); err != nil {
+			return errors.Wrapf(err, "failed to register placeholder for %s", slackUser.ID)
+		}
+		created = append(created, slackUser.ID)
+		result.UsersCreated++
+	}
+
+	if len(created) == 0 {
+		return nil
+	}
+
+	// Registration does not return the new IDs, so they are listed again.
+	if byEmail, err = i.usersByEmail(ctx); err != nil {
+		return err
+	}
+	for _, slackID := range created {
+		user, ok := byEmail[i.placeholderEmail(slackID)]
+		if !ok {
+			return errors.Errorf("placeholder for %s not found after registration", slackID)
+		}
+		mapping.Users[slackID] = slackexport.MappedUser{ID: user.ID, Username: user.Username}
+	}
+
+	i.logger.Info("Registered placeholder users", zap.Int("count", len(created)))
+	return nil
+}
+
+func (i *Importer) usersByEmail(ctx context.Context) (map[string]*entities.User, error) {
+	byEmail := make(map[string]*entities.User)
+	for offset := 0; ; offset += usersPageSize {
+		users, err := i.users.GetUsers(ctx, usersPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			byEmail[strings.ToLower(user.Email)] = user
+		}
+		if len(users) < usersPageSize {
+			return byEmail, nil
+		}
+	}
+}
+
+// importConversation maps a conversation to its room: the room of a previous
+// run, the direct room of its two members, the public room of the same name or
+// a new room.
+func (i *Importer) importConversation(ctx context.Context, conversation *slackexport.Conversation, mapping *slackexport.Mapping, result *Result) error {
+	room, err := i.storage.GetImportedRoom(ctx, Source, conversation.ID)
+	if err == nil {
+		mapping.Rooms[conversation.ID] = room.ID
+		result.RoomsReused++
+		return nil
+	}
+	if !errors.Is(err, entities.ErrRoomNotFound) {
+		return err
+	}
+
+	members := mapMembers(conversation, mapping)
+	if len(members) == 0 || (conversation.Kind != slackexport.KindChannel && len(members) < 2) {
+		i.logger.Warn("Skipping conversation without enough known members",
+			zap.String("conversation_id", conversation.ID),
+			zap.String("name", conversation.Name),
+		)
+		result.RoomsSkipped++
+		return nil
+	}
+
+	createdAt := time.Now()
+	if conversation.Created > 0 {
+		createdAt = time.Unix(conversation.Created, 0)
+	}
+	room = &entities.Room{
+		ID:        uuid.New(),
+		Name:      conversation.Name,
+		OwnerID:   members[0],
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	switch conversation.Kind {
+	case slackexport.KindChannel:
+		room.Kind = entities.RoomKindPublic
+
+		existing, err := i.storage.GetPublicRoomByName(ctx, room.Name)
+		if err == nil {
+			return i.link(ctx, conversation, existing, mapping, result)
+		}
+		if !errors.Is(err, entities.ErrRoomNotFound) {
+			return err
+		}
+
+	case slackexport.KindDirect:
+		if len(members) != 2 {
+			i.logger.Warn("Skipping direct conversation with itself", zap.String("conversation_id", conversation.ID))
+			result.RoomsSkipped++
+			return nil
+		}
+		room.Kind = entities.RoomKindDirect
+		room.Name = ""
+		room.MemberIDs = members
+
+		direct, err := i.storage.GetOrCreateDirectRoom(ctx, room)
+		if err != nil {
+			return err
+		}
+		return i.link(ctx, conversation, direct, mapping, result)
+
+	default:
+		room.Kind = entities.RoomKindGroup
+		room.MemberIDs = members
+		if conversation.Kind == slackexport.KindGroup {
+			room.Name = groupName(conversation, mapping)
+		}
+		if len(members) > entities.MaxGroupMembers {
+			i.logger.Warn("Imported group room is larger than allowed for new groups",
+				zap.String("conversation_id", conversation.ID),
+				zap.Int("members", len(members)),
+			)
+		}
+	}
+
+	if err := i.storage.CreateImportedRoom(ctx, Source, conversation.ID, room); err != nil {
+		return err
+	}
+	mapping.Rooms[conversation.ID] = room.ID
+	result.RoomsCreated++
+	return nil
+}
+
+func (i *Importer) link(ctx context.Context, conversation *slackexport.Conversation, room *entities.Room, mapping *slackexport.Mapping, result *Result) error {
+	if err := i.storage.LinkImportedRoom(ctx, Source, conversation.ID, room.ID); err != nil {
+		return err
+	}
+	mapping.Rooms[conversation.ID] = room.ID
+	result.RoomsReused++
+	return nil
+}
+
+func (i *Importer) placeholderEmail(slackID string) string {
+	return strings.ToLower("slack-" + slackID + "@" + i.cfg.EmailDomain)
+}
+
+// mapMembers returns the users of the members of a conversation, its creator
+// first so that they own the room.
+func mapMembers(conversation *slackexport.Conversation, mapping *slackexport.Mapping) []uuid.UUID {
+	var members []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, slackID := range append([]string{conversation.Creator}, conversation.Members...) {
+		user, ok := mapping.Users[slackID]
+		if !ok || seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		members = append(members, user.ID)
+	}
+	return members
+}
+
+// groupName names a group direct message after its members, as Slack shows it,
+// instead of its generated "mpdm-…" name.
+func groupName(conversation *slackexport.Conversation, mapping *slackexport.Mapping) string {
+	names := make([]string, 0, len(conversation.Members))
+	for _, slackID := range conversation.Members {
+		if user, ok := mapping.Users[slackID]; ok {
+			names = append(names, user.Username)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func placeholderUsername(user *slackexport.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.ID
+}
+
+// placeholderPassword returns a random password meeting the password policy
+// of the Auth Service.
+func placeholderPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "failed to generate password")
+	}
+	// The suffix covers the required character classes.
+	return base64.RawURLEncoding.EncodeToString(buf) + "Aa1!", nil
+}
@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+)
+
+type Storage interface {
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entities.WebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, attempt *entities.WebhookAttempt) error
+	RetryWebhookDelivery(ctx context.Context, attempt *entities.WebhookAttempt, nextAttemptAt time.Time) error
+	DeadLetterWebhookDelivery(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookAttempt) error
+	PruneWebhookAttempts(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Deps struct {
+	Storage Storage
+}
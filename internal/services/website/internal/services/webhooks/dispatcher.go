@@ -0,0 +1,249 @@
+// Package webhooks delivers the payloads queued for the outgoing webhooks of
+// rooms. Deliveries live in the database, so every website instance may run a
+// Dispatcher: each claims its own batches.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Headers of webhook requests.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	// pruneInterval is how often old entries of the delivery log are deleted.
+	pruneInterval = time.Hour
+	// maxDrainSize bounds how much of a response is read so that its connection
+	// can be reused.
+	maxDrainSize = 4096
+)
+
+// Config tunes the Dispatcher. A delivery is attempted up to MaxAttempts times;
+// the wait between attempts starts at InitialBackoff and doubles up to MaxBackoff.
+type Config struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	Timeout        time.Duration // Per request.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	LogRetention   time.Duration // How long the delivery log is kept.
+	// AllowPrivateNetworks lets webhooks reach loopback and private addresses,
+	// e.g. a receiver on the developer's machine.
+	AllowPrivateNetworks bool
+}
+
+// Dispatcher POSTs queued deliveries to their webhooks.
+type Dispatcher struct {
+	storage Storage
+	cfg     Config
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+func NewDispatcher(deps Deps, cfg Config, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		storage: deps.Storage,
+		cfg:     cfg,
+		client: &http.Client{
+			Transport: newTransport(cfg.AllowPrivateNetworks),
+			Timeout:   cfg.Timeout,
+			// A redirect could send the signed payload to a URL the room owner did not configure.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// Run delivers due deliveries until the context is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	pollTick := time.NewTicker(d.cfg.PollInterval)
+	defer pollTick.Stop()
+	pruneTick := time.NewTicker(pruneInterval)
+	defer pruneTick.Stop()
+
+	d.logger.Info("Webhook dispatcher started")
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Webhook dispatcher stopped")
+			return
+		case <-pollTick.C:
+			d.dispatch(ctx)
+		case <-pruneTick.C:
+			d.prune(ctx)
+		}
+	}
+}
+
+// dispatch claims batches of due deliveries and delivers them concurrently,
+// until no delivery is due.
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		// The lease outlasts the request, so a delivery is never sent twice at
+		// once unless its instance died.
+		now := time.Now()
+		deliveries, err := d.storage.ClaimWebhookDeliveries(ctx, now, now.Add(2*d.cfg.Timeout+d.cfg.PollInterval), d.cfg.BatchSize)
+		if err != nil {
+			d.logger.Error("Failed to claim webhook deliveries", zap.Error(err))
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func(delivery *entities.WebhookDelivery) {
+				defer wg.Done()
+				d.deliver(ctx, delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(deliveries) < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// deliver attempts a delivery and records the outcome: the delivery is done,
+// retried later, or moved to the dead letters.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *entities.WebhookDelivery) {
+	attempt, retry := d.send(ctx, delivery)
+
+	var err error
+	switch {
+	case attempt.Succeeded():
+		err = d.storage.CompleteWebhookDelivery(ctx, attempt)
+	case retry && delivery.Attempts < d.cfg.MaxAttempts:
+		err = d.storage.RetryWebhookDelivery(ctx, attempt, attempt.CreatedAt.Add(d.backoff(delivery.Attempts)))
+	default:
+		err = d.storage.DeadLetterWebhookDelivery(ctx, delivery, attempt)
+		if err == nil {
+			d.logger.Warn("Webhook delivery failed permanently",
+				zap.String("webhook_id", delivery.WebhookID.String()),
+				zap.String("delivery_id", delivery.ID.String()),
+				zap.Int("attempts", delivery.Attempts),
+				zap.String("error", attempt.Error),
+			)
+		}
+	}
+	if err != nil {
+		d.logger.Error("Failed to record webhook attempt",
+			zap.Error(err),
+			zap.String("delivery_id", delivery.ID.String()),
+		)
+	}
+}
+
+// send POSTs a delivery to its webhook. It reports whether a failed attempt is
+// worth retrying: client errors other than timeouts and rate limits are not.
+func (d *Dispatcher) send(ctx context.Context, delivery *entities.WebhookDelivery) (*entities.WebhookAttempt, bool) {
+	attempt := &entities.WebhookAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		WebhookID:  delivery.WebhookID,
+		Event:      delivery.Event,
+		Attempt:    delivery.Attempts,
+	}
+
+	start := time.Now()
+	defer func() {
+		attempt.Duration = time.Since(start)
+		attempt.CreatedAt = time.Now()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, false
+	}
+
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-chat-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, true
+	}
+	defer resp.Body.Close()
+
+	attempt.StatusCode = resp.StatusCode
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainSize))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return attempt, false
+	}
+
+	// The body is not kept: the log is shown to the room owner, and the receiver
+	// may be a service the owner should not read.
+	attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+
+	clientError := resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests
+	return attempt, !clientError
+}
+
+// backoff returns how long to wait after the given number of attempts: the
+// initial backoff doubled per attempt, capped, with up to 20% of jitter so that
+// deliveries failing together are not retried together.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.InitialBackoff
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.cfg.MaxBackoff {
+		wait = d.cfg.MaxBackoff
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)/5+1))
+}
+
+func (d *Dispatcher) prune(ctx context.Context) {
+	pruned, err := d.storage.PruneWebhookAttempts(ctx, time.Now().Add(-d.cfg.LogRetention))
+	if err != nil {
+		d.logger.Error("Failed to prune webhook delivery log", zap.Error(err))
+		return
+	}
+	if pruned > 0 {
+		d.logger.Info("Webhook delivery log pruned", zap.Int64("entries", pruned))
+	}
+}
+
+// Sign computes the X-Webhook-Signature header of a payload sent at timestamp:
+// the hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with the webhook secret.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// errPrivateAddress is returned when a webhook URL resolves to an address that
+// is not on the public internet.
+var errPrivateAddress = errors.New("webhook address is not public")
+
+// newTransport returns the transport of webhook requests. Unless private
+// networks are allowed, it refuses to connect to loopback, private, link-local
+// and unspecified addresses. The check runs on the address being dialed, after
+// name resolution, so a public host name resolving to an internal address is
+// refused as well. Proxies are not used, since the proxy address would be
+// checked in place of the receiver.
+func newTransport(allowPrivateNetworks bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivateNetworks {
+		dialer.Control = refusePrivateAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
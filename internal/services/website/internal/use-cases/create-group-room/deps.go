@@ -0,0 +1,16 @@
+package creategrouproom
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	CreateGroupRoom(ctx context.Context, name string, ownerID uuid.UUID, memberIDs []uuid.UUID) (*entities.Room, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,27 @@
+package creategrouproom
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, name string, ownerID uuid.UUID, memberIDs []uuid.UUID) (*entities.Room, error) {
+	room, err := uc.roomService.CreateGroupRoom(ctx, name, ownerID, memberIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create group room")
+	}
+	return room, nil
+}
@@ -0,0 +1,16 @@
+package createincomingwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	CreateIncomingWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.IncomingWebhook) (*entities.IncomingWebhook, string, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
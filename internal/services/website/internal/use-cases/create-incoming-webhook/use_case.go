@@ -0,0 +1,28 @@
+package createincomingwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+// Execute creates an incoming webhook and returns it with its token.
+func (uc *UseCase) Execute(ctx context.Context, ownerID uuid.UUID, webhook *entities.IncomingWebhook) (*entities.IncomingWebhook, string, error) {
+	created, token, err := uc.roomService.CreateIncomingWebhook(ctx, ownerID, webhook)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create incoming webhook")
+	}
+	return created, token, nil
+}
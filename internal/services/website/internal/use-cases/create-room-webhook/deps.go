@@ -0,0 +1,16 @@
+package createroomwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	CreateRoomWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook) (*entities.Webhook, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
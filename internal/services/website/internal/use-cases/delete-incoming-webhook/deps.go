@@ -0,0 +1,15 @@
+package deleteincomingwebhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	DeleteIncomingWebhook(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,15 @@
+package deleteroomscript
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	DeleteRoomScript(ctx context.Context, roomID, scriptID, ownerID uuid.UUID) error
+}
+
+type Deps struct {
+	RoomService RoomService
+}
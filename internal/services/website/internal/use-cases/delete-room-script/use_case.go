@@ -0,0 +1,25 @@
+package deleteroomscript
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, scriptID, ownerID uuid.UUID) error {
+	if err := uc.roomService.DeleteRoomScript(ctx, roomID, scriptID, ownerID); err != nil {
+		return errors.Wrap(err, "failed to delete room script")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package deleteroomwebhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	DeleteRoomWebhook(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,25 @@
+package deleteroomwebhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, webhookID, ownerID uuid.UUID) error {
+	if err := uc.roomService.DeleteRoomWebhook(ctx, roomID, webhookID, ownerID); err != nil {
+		return errors.Wrap(err, "failed to delete room webhook")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package getenabledscripts
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetEnabledScripts(ctx context.Context, roomID *uuid.UUID, scheduledOnly bool) ([]*entities.RoomScript, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
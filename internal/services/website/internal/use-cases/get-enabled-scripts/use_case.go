@@ -0,0 +1,29 @@
+package getenabledscripts
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+// Execute returns the enabled scripts of a room, or the enabled scripts with a
+// schedule of every room when roomID is nil.
+func (uc *UseCase) Execute(ctx context.Context, roomID *uuid.UUID) ([]*entities.RoomScript, error) {
+	scripts, err := uc.roomService.GetEnabledScripts(ctx, roomID, roomID == nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get enabled scripts")
+	}
+	return scripts, nil
+}
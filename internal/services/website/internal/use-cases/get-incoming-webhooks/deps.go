@@ -0,0 +1,16 @@
+package getincomingwebhooks
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetIncomingWebhooks(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.IncomingWebhook, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
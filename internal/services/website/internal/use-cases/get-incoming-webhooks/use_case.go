@@ -0,0 +1,27 @@
+package getincomingwebhooks
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.IncomingWebhook, error) {
+	webhooks, err := uc.roomService.GetIncomingWebhooks(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get incoming webhooks")
+	}
+	return webhooks, nil
+}
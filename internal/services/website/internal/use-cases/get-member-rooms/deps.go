@@ -0,0 +1,16 @@
+package getmemberrooms
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetMemberRooms(ctx context.Context, userID uuid.UUID) ([]*entities.Room, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
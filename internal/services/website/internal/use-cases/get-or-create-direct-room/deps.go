@@ -0,0 +1,16 @@
+package getorcreatedirectroom
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetOrCreateDirectRoom(ctx context.Context, userA, userB uuid.UUID) (*entities.Room, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
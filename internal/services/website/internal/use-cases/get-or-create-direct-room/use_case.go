@@ -0,0 +1,27 @@
+package getorcreatedirectroom
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, userA, userB uuid.UUID) (*entities.Room, error) {
+	room, err := uc.roomService.GetOrCreateDirectRoom(ctx, userA, userB)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get or create direct room")
+	}
+	return room, nil
+}
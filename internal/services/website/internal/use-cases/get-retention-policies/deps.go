@@ -0,0 +1,15 @@
+package getretentionpolicies
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+)
+
+type RoomService interface {
+	GetRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
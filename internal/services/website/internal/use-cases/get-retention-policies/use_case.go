@@ -0,0 +1,27 @@
+package getretentionpolicies
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+// Execute returns the retention policies of every room that has one.
+func (uc *UseCase) Execute(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	policies, err := uc.roomService.GetRetentionPolicies(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retention policies")
+	}
+	return policies, nil
+}
@@ -0,0 +1,16 @@
+package getretentionpolicy
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetRetentionPolicy(ctx context.Context, roomID, ownerID uuid.UUID) (*entities.RetentionPolicy, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,27 @@
+package getretentionpolicy
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, ownerID uuid.UUID) (*entities.RetentionPolicy, error) {
+	policy, err := uc.roomService.GetRetentionPolicy(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retention policy")
+	}
+	return policy, nil
+}
@@ -0,0 +1,16 @@
+package getroomscripts
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetRoomScripts(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.RoomScript, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
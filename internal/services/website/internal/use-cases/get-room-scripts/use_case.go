@@ -0,0 +1,27 @@
+package getroomscripts
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.RoomScript, error) {
+	scripts, err := uc.roomService.GetRoomScripts(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room scripts")
+	}
+	return scripts, nil
+}
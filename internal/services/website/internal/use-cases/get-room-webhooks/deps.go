@@ -0,0 +1,16 @@
+package getroomwebhooks
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetRoomWebhooks(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.Webhook, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,27 @@
+package getroomwebhooks
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, ownerID uuid.UUID) ([]*entities.Webhook, error) {
+	webhooks, err := uc.roomService.GetRoomWebhooks(ctx, roomID, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get room webhooks")
+	}
+	return webhooks, nil
+}
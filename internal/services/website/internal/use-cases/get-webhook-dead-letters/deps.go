@@ -0,0 +1,16 @@
+package getwebhookdeadletters
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetWebhookDeadLetters(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,27 @@
+package getwebhookdeadletters
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookDeadLetter, error) {
+	deadLetters, err := uc.roomService.GetWebhookDeadLetters(ctx, roomID, webhookID, ownerID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook dead letters")
+	}
+	return deadLetters, nil
+}
@@ -0,0 +1,16 @@
+package getwebhookdeliveries
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	GetWebhookDeliveries(ctx context.Context, roomID, webhookID, ownerID uuid.UUID, limit int) ([]*entities.WebhookAttempt, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
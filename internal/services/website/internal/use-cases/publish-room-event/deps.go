@@ -0,0 +1,15 @@
+package publishroomevent
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+)
+
+type RoomService interface {
+	PublishRoomEvent(ctx context.Context, event *entities.WebhookEvent) (bool, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
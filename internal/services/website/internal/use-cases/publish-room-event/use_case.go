@@ -0,0 +1,28 @@
+package publishroomevent
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+// Execute queues an event for the webhooks of its room and reports whether the
+// room has enabled webhooks.
+func (uc *UseCase) Execute(ctx context.Context, event *entities.WebhookEvent) (bool, error) {
+	subscribed, err := uc.roomService.PublishRoomEvent(ctx, event)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to publish room event")
+	}
+	return subscribed, nil
+}
@@ -0,0 +1,15 @@
+package redeliverwebhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	RedeliverWebhook(ctx context.Context, roomID, webhookID, deadLetterID, ownerID uuid.UUID) error
+}
+
+type Deps struct {
+	RoomService RoomService
+}
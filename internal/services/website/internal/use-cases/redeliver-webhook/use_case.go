@@ -0,0 +1,25 @@
+package redeliverwebhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, roomID, webhookID, deadLetterID, ownerID uuid.UUID) error {
+	if err := uc.roomService.RedeliverWebhook(ctx, roomID, webhookID, deadLetterID, ownerID); err != nil {
+		return errors.Wrap(err, "failed to redeliver webhook")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package resolveincomingwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+)
+
+type RoomService interface {
+	ResolveIncomingWebhook(ctx context.Context, token string) (*entities.IncomingWebhook, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
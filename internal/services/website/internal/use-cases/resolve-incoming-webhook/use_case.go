@@ -0,0 +1,27 @@
+package resolveincomingwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+// Execute returns the incoming webhook a token belongs to.
+func (uc *UseCase) Execute(ctx context.Context, token string) (*entities.IncomingWebhook, error) {
+	webhook, err := uc.roomService.ResolveIncomingWebhook(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve incoming webhook")
+	}
+	return webhook, nil
+}
@@ -0,0 +1,16 @@
+package setretentionpolicy
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	SetRetentionPolicy(ctx context.Context, ownerID uuid.UUID, policy *entities.RetentionPolicy) (*entities.RetentionPolicy, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
@@ -0,0 +1,27 @@
+package setretentionpolicy
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, ownerID uuid.UUID, policy *entities.RetentionPolicy) (*entities.RetentionPolicy, error) {
+	updated, err := uc.roomService.SetRetentionPolicy(ctx, ownerID, policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set retention policy")
+	}
+	return updated, nil
+}
@@ -0,0 +1,16 @@
+package updateroomscript
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	UpdateRoomScript(ctx context.Context, ownerID uuid.UUID, script *entities.RoomScript) (*entities.RoomScript, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
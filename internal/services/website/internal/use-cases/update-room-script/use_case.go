@@ -0,0 +1,27 @@
+package updateroomscript
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, ownerID uuid.UUID, script *entities.RoomScript) (*entities.RoomScript, error) {
+	updated, err := uc.roomService.UpdateRoomScript(ctx, ownerID, script)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update room script")
+	}
+	return updated, nil
+}
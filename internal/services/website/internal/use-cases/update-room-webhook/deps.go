@@ -0,0 +1,16 @@
+package updateroomwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+)
+
+type RoomService interface {
+	UpdateRoomWebhook(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook, rotateSecret bool) (*entities.Webhook, error)
+}
+
+type Deps struct {
+	RoomService RoomService
+}
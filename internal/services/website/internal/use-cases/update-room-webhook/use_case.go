@@ -0,0 +1,27 @@
+package updateroomwebhook
+
+import (
+	"context"
+
+	"github.com/HexArch/go-chat/internal/services/website/internal/entities"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type UseCase struct {
+	roomService RoomService
+}
+
+func New(deps Deps) *UseCase {
+	return &UseCase{
+		roomService: deps.RoomService,
+	}
+}
+
+func (uc *UseCase) Execute(ctx context.Context, ownerID uuid.UUID, webhook *entities.Webhook, rotateSecret bool) (*entities.Webhook, error) {
+	updated, err := uc.roomService.UpdateRoomWebhook(ctx, ownerID, webhook, rotateSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update room webhook")
+	}
+	return updated, nil
+}